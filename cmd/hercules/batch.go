@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/hercules.v9"
+)
+
+// batchResult is the outcome of the analysis of a single repository in the batch.
+type batchResult struct {
+	// Repository is the path or the URL of the analysed repository.
+	Repository string `json:"repository"`
+	// Output is the path to the Protocol Buffers results. Empty if the analysis failed.
+	Output string `json:"output,omitempty"`
+	// Error is the reason of the failure.
+	Error string `json:"error,omitempty"`
+}
+
+// batchOptions are the settings of runBatch() which are shared by all the repositories.
+type batchOptions struct {
+	// Jobs is the number of repositories which are analysed concurrently.
+	Jobs int
+	// CacheDir is the directory where to keep the clones of the remote repositories.
+	CacheDir string
+	// FirstParent follows only the first parent in the commit history.
+	FirstParent bool
+	// Quiet disables the progress messages in stderr.
+	Quiet bool
+}
+
+var unsafeFileNameChars = regexp.MustCompile("[^A-Za-z0-9._-]+")
+
+// readRepositoriesList loads the repository URIs from the text file, one per line.
+// Empty lines and lines starting with # are skipped, duplicates are removed.
+// "-" means stdin.
+func readRepositoriesList(path string) ([]string, error) {
+	var file io.ReadCloser
+	if path != "-" {
+		var err error
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+	} else {
+		file = os.Stdin
+	}
+	scanner := bufio.NewScanner(file)
+	var uris []string
+	seen := map[string]bool{}
+	for scanner.Scan() {
+		uri := strings.TrimSpace(scanner.Text())
+		if uri == "" || strings.HasPrefix(uri, "#") || seen[uri] {
+			continue
+		}
+		seen[uri] = true
+		uris = append(uris, uri)
+	}
+	return uris, scanner.Err()
+}
+
+// batchOutputName generates the unique file name of the results of the specified repository,
+// e.g. "github.com_src-d_go-git.pb".
+func batchOutputName(uri string, taken map[string]bool) string {
+	if index := strings.Index(uri, "://"); index >= 0 {
+		uri = uri[index+3:]
+	}
+	uri = strings.TrimSuffix(strings.TrimRight(uri, "/"), ".git")
+	name := strings.Trim(unsafeFileNameChars.ReplaceAllString(uri, "_"), "_.")
+	if name == "" {
+		name = "repository"
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	taken[unique] = true
+	return unique + ".pb"
+}
+
+// runBatch analyses each repository with the specified leaves and writes the Protocol Buffers
+// results to outputDir. The failures are recorded in the returned list which has the same order
+// as uris.
+func runBatch(uris []string, outputDir string, leaves []string,
+	facts map[string]interface{}, options batchOptions) []batchResult {
+	results := make([]batchResult, len(uris))
+	taken := map[string]bool{}
+	for i, uri := range uris {
+		results[i] = batchResult{
+			Repository: uri, Output: filepath.Join(outputDir, batchOutputName(uri, taken)),
+		}
+	}
+	jobs := options.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	queue := make(chan int)
+	wg := sync.WaitGroup{}
+	progressLock := sync.Mutex{}
+	finished := 0
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				result := &results[i]
+				err := analyseBatchRepository(result.Repository, result.Output, leaves, facts, options)
+				status := "done"
+				if err != nil {
+					result.Output = ""
+					result.Error = err.Error()
+					status = "failed: " + result.Error
+				}
+				if options.Quiet {
+					continue
+				}
+				progressLock.Lock()
+				finished++
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s %s\n", finished, len(uris), result.Repository, status)
+				progressLock.Unlock()
+			}
+		}()
+	}
+	for i := range uris {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+// batchRepositoryFacts copies the facts for the analysis of a single repository because
+// Initialize() writes to them. The checkpoint, the resume and the incremental directories become
+// the subdirectories named after the repository, and the name is appended to the DAG dump file,
+// so that the repositories do not overwrite each other's state.
+func batchRepositoryFacts(facts map[string]interface{}, name string) map[string]interface{} {
+	repositoryFacts := map[string]interface{}{}
+	for key, val := range facts {
+		repositoryFacts[key] = val
+	}
+	for _, key := range []string{hercules.ConfigPipelineCheckpointDirectory,
+		hercules.ConfigPipelineResume, hercules.ConfigPipelineIncremental} {
+		if dir, _ := facts[key].(string); dir != "" {
+			repositoryFacts[key] = filepath.Join(dir, name)
+		}
+	}
+	if path, _ := facts[hercules.ConfigPipelineDAGPath].(string); path != "" {
+		ext := filepath.Ext(path)
+		repositoryFacts[hercules.ConfigPipelineDAGPath] = strings.TrimSuffix(path, ext) + "_" + name + ext
+	}
+	return repositoryFacts
+}
+
+// analyseBatchRepository runs the pipeline over a single repository and writes the results
+// to the output file. loadRepository() and the items panic on errors, so the panics are
+// converted to the returned error.
+func analyseBatchRepository(uri, output string, leaves []string,
+	facts map[string]interface{}, options batchOptions) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	repository := loadRepository(uri, cacheRepositoryPath(options.CacheDir, uri), true, "")
+	pipeline := hercules.NewPipeline(repository)
+	pipeline.SetFeaturesFromFlags()
+	repositoryFacts := batchRepositoryFacts(facts, strings.TrimSuffix(filepath.Base(output), ".pb"))
+	deployed, results, err := runPipeline(
+		context.Background(), pipeline, leaves, repositoryFacts, options.FirstParent)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	protobufResults(file, uri, deployed, results)
+	return file.Close()
+}
+
+// combineBatch merges the results of the successfully analysed repositories the same way as
+// "hercules combine" does. The merging items are configured with the facts.
+// It returns the errors which happened while loading each file.
+func combineBatch(results []batchResult, facts map[string]interface{}, writer io.Writer) (
+	map[string][]string, error) {
+	var repos []string
+	allErrors := map[string][]string{}
+	mergedResults := map[string]interface{}{}
+	mergedMetadata := &hercules.CommonAnalysisResult{}
+	for _, result := range results {
+		if result.Output == "" {
+			continue
+		}
+		anotherResults, anotherMetadata, errs := loadMessage(result.Output, &repos)
+		if anotherMetadata != nil {
+			mergeResults(mergedResults, mergedMetadata, anotherResults, anotherMetadata, "", facts)
+		}
+		allErrors[result.Output] = errs
+	}
+	if len(repos) == 0 {
+		return allErrors, fmt.Errorf("there are no successfully analysed repositories to combine")
+	}
+	writeMergedResults(writer, repos, mergedResults, mergedMetadata)
+	return allErrors, nil
+}
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch <repositories file> <output directory>",
+	Short: "Analyse many repositories in one invocation.",
+	Long: `Run the same analyses over each repository listed in the text file, one path or URL per line.
+Empty lines and lines starting with # are skipped; "-" reads the list from stdin. The Protocol Buffers
+results are written to the output directory, one file per repository, and the per-repository failures
+are recorded in batch.json there instead of aborting. --combined additionally merges the successful
+results the same way as "hercules combine". --checkpoint-dir, --resume and --incremental use the
+subdirectories named after each repository, and --dump-dag writes one file per repository.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		jobs, err := flags.GetInt("jobs")
+		if err != nil {
+			panic(err)
+		}
+		cacheDir, err := flags.GetString("cache")
+		if err != nil {
+			panic(err)
+		}
+		combined, err := flags.GetString("combined")
+		if err != nil {
+			panic(err)
+		}
+		firstParent, err := flags.GetBool("first-parent")
+		if err != nil {
+			panic(err)
+		}
+		quiet, err := flags.GetBool("quiet")
+		if err != nil {
+			panic(err)
+		}
+		if jobs < 1 {
+			log.Fatalf("--jobs must be positive, got %d", jobs)
+		}
+		var leaves []string
+		for name, valPtr := range batchDeployed {
+			if *valPtr {
+				leaves = append(leaves, name)
+			}
+		}
+		if len(leaves) == 0 {
+			log.Fatal("no analyses were specified")
+		}
+		uris, err := readRepositoriesList(args[0])
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", args[0], err)
+		}
+		outputDir := args[1]
+		if err = os.MkdirAll(outputDir, 0755); err != nil {
+			log.Fatalf("failed to create %s: %v", outputDir, err)
+		}
+		results := runBatch(uris, outputDir, leaves, batchFacts, batchOptions{
+			Jobs: jobs, CacheDir: cacheDir, FirstParent: firstParent, Quiet: quiet,
+		})
+		report, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		reportPath := filepath.Join(outputDir, "batch.json")
+		if err = ioutil.WriteFile(reportPath, report, 0644); err != nil {
+			log.Fatalf("failed to write %s: %v", reportPath, err)
+		}
+		allErrors := map[string][]string{}
+		for _, result := range results {
+			if result.Error != "" {
+				allErrors[result.Repository] = []string{result.Error}
+			}
+		}
+		if combined != "" {
+			file, err := os.Create(combined)
+			if err != nil {
+				log.Fatalf("failed to create %s: %v", combined, err)
+			}
+			combineErrors, err := combineBatch(results, batchFacts, file)
+			file.Close()
+			if err != nil {
+				os.Remove(combined)
+				log.Print(err)
+			}
+			for key, errs := range combineErrors {
+				allErrors[key] = append(allErrors[key], errs...)
+			}
+		}
+		printErrors(allErrors)
+		for _, errs := range allErrors {
+			if len(errs) > 0 {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+var batchFacts map[string]interface{}
+var batchDeployed map[string]*bool
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.SetUsageFunc(batchCmd.UsageFunc())
+	batchFlags := batchCmd.Flags()
+	batchFlags.Int("jobs", 1, "Number of repositories to analyse concurrently.")
+	batchFlags.String("cache", "", "Directory where to keep the clones of the remote repositories "+
+		"between the runs. Empty means clone to memory every time.")
+	batchFlags.String("combined", "", "Merge the results of all the successfully analysed "+
+		"repositories and write them to this file.")
+	batchFlags.Bool("first-parent", false, "Follow only the first parent in the commit history - "+
+		"\"git log --first-parent\".")
+	batchFlags.Bool("quiet", false, "Do not print the progress to stderr.")
+	for _, name := range []string{"cache", "combined"} {
+		if err := batchCmd.MarkFlagFilename(name); err != nil {
+			panic(err)
+		}
+		hercules.PathifyFlagValue(batchFlags.Lookup(name))
+	}
+}
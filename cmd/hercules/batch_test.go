@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/src-d/hercules.v9/internal/pb"
+)
+
+func TestReadRepositoriesList(t *testing.T) {
+	file, err := ioutil.TempFile("", "hercules-batch-")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	file.WriteString("# comment\nhttps://github.com/src-d/hercules\n\n  /tmp/repo  \n" +
+		"https://github.com/src-d/hercules\n")
+	file.Close()
+	uris, err := readRepositoriesList(file.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/src-d/hercules", "/tmp/repo"}, uris)
+	_, err = readRepositoriesList(file.Name() + "xxx")
+	assert.NotNil(t, err)
+}
+
+func TestBatchOutputName(t *testing.T) {
+	taken := map[string]bool{}
+	assert.Equal(t, "github.com_src-d_hercules.pb",
+		batchOutputName("https://github.com/src-d/hercules.git", taken))
+	assert.Equal(t, "github.com_src-d_hercules_2.pb",
+		batchOutputName("https://github.com/src-d/hercules/", taken))
+	assert.Equal(t, "tmp_repo.pb", batchOutputName("/tmp/repo", taken))
+	assert.Equal(t, "repository.pb", batchOutputName("/", taken))
+}
+
+func TestBatchRepositoryFacts(t *testing.T) {
+	facts := map[string]interface{}{
+		hercules.ConfigPipelineCheckpointDirectory: "/tmp/checkpoints",
+		hercules.ConfigPipelineResume:              "",
+		hercules.ConfigPipelineIncremental:         "/tmp/state",
+		hercules.ConfigPipelineDAGPath:             "/tmp/dag.dot",
+		hercules.ConfigPipelineWorkers:             4,
+	}
+	repositoryFacts := batchRepositoryFacts(facts, "github.com_src-d_hercules")
+	assert.Equal(t, map[string]interface{}{
+		hercules.ConfigPipelineCheckpointDirectory: "/tmp/checkpoints/github.com_src-d_hercules",
+		hercules.ConfigPipelineResume:              "",
+		hercules.ConfigPipelineIncremental:         "/tmp/state/github.com_src-d_hercules",
+		hercules.ConfigPipelineDAGPath:             "/tmp/dag_github.com_src-d_hercules.dot",
+		hercules.ConfigPipelineWorkers:             4,
+	}, repositoryFacts)
+	// the original facts are intact
+	assert.Equal(t, "/tmp/checkpoints", facts[hercules.ConfigPipelineCheckpointDirectory])
+}
+
+func TestRunBatch(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	sivafile := filepath.Join(filepath.Dir(filename), "test_data", "hercules.siva")
+	outputDir, err := ioutil.TempDir("", "hercules-batch-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(outputDir)
+	facts, err := convertFacts(nil)
+	assert.Nil(t, err)
+	missing := filepath.Join(outputDir, "missing")
+	results := runBatch([]string{sivafile, missing}, outputDir, []string{"Devs"}, facts,
+		batchOptions{Jobs: 2, Quiet: true})
+	assert.Len(t, results, 2)
+	assert.Equal(t, sivafile, results[0].Repository)
+	assert.Equal(t, "", results[0].Error)
+	assert.Equal(t, filepath.Dir(results[0].Output), outputDir)
+	_, err = os.Stat(results[0].Output)
+	assert.Nil(t, err)
+	assert.Equal(t, missing, results[1].Repository)
+	assert.Equal(t, "", results[1].Output)
+	assert.NotEqual(t, "", results[1].Error)
+
+	buffer := &bytes.Buffer{}
+	errs, err := combineBatch(results, facts, buffer)
+	assert.Nil(t, err)
+	assert.Len(t, errs[results[0].Output], 0)
+	message := pb.AnalysisResults{}
+	assert.Nil(t, proto.Unmarshal(buffer.Bytes(), &message))
+	assert.Equal(t, sivafile, message.Header.Repository)
+	assert.Contains(t, message.Contents, "Devs")
+
+	_, err = combineBatch(results[1:], facts, buffer)
+	assert.NotNil(t, err)
+}
@@ -16,6 +16,7 @@ import (
 	progress "gopkg.in/cheggaaa/pb.v1"
 	"gopkg.in/src-d/hercules.v9"
 	"gopkg.in/src-d/hercules.v9/internal/pb"
+	"gopkg.in/src-d/hercules.v9/leaves"
 )
 
 // combineCmd represents the combine command
@@ -41,6 +42,13 @@ var combineCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		prefixRepository, err := cmd.Flags().GetBool("file-history-prefix-repository")
+		if err != nil {
+			panic(err)
+		}
+		facts := map[string]interface{}{
+			leaves.ConfigFileHistoryPrefixRepository: prefixRepository,
+		}
 		var repos []string
 		allErrors := map[string][]string{}
 		mergedResults := map[string]interface{}{}
@@ -59,7 +67,7 @@ var combineCmd = &cobra.Command{
 			bar.Increment()
 			anotherResults, anotherMetadata, errs := loadMessage(fileName, &repos)
 			if anotherMetadata != nil {
-				mergeResults(mergedResults, mergedMetadata, anotherResults, anotherMetadata, only)
+				mergeResults(mergedResults, mergedMetadata, anotherResults, anotherMetadata, only, facts)
 			}
 			allErrors[fileName] = errs
 			debug.FreeOSMemory()
@@ -67,66 +75,96 @@ var combineCmd = &cobra.Command{
 		bar.Finish()
 		os.Stderr.WriteString("\033[2K\r")
 		printErrors(allErrors)
-		sort.Strings(repos)
 		if mergedMetadata == nil {
 			return
 		}
-		mergedMessage := pb.AnalysisResults{
-			Header: &pb.Metadata{
-				Version:    int32(hercules.BinaryVersion),
-				Hash:       hercules.BinaryGitHash,
-				Repository: strings.Join(repos, " & "),
-			},
-			Contents: map[string][]byte{},
-		}
-		mergedMetadata.FillMetadata(mergedMessage.Header)
-		for key, val := range mergedResults {
-			buffer := bytes.Buffer{}
-			err := hercules.Registry.Summon(key)[0].(hercules.LeafPipelineItem).Serialize(
-				val, true, &buffer)
-			if err != nil {
-				panic(err)
-			}
-			mergedMessage.Contents[key] = buffer.Bytes()
-		}
-		serialized, err := proto.Marshal(&mergedMessage)
+		writeMergedResults(os.Stdout, repos, mergedResults, mergedMetadata)
+	},
+}
+
+// writeMergedResults serializes the combined analysis results to Protocol Buffers.
+func writeMergedResults(writer io.Writer, repos []string,
+	mergedResults map[string]interface{}, mergedMetadata *hercules.CommonAnalysisResult) {
+	sort.Strings(repos)
+	mergedMessage := pb.AnalysisResults{
+		Header: &pb.Metadata{
+			Version:    int32(hercules.BinaryVersion),
+			Hash:       hercules.BinaryGitHash,
+			Repository: strings.Join(repos, " & "),
+		},
+		Contents: map[string][]byte{},
+	}
+	mergedMetadata.FillMetadata(mergedMessage.Header)
+	for key, val := range mergedResults {
+		buffer := bytes.Buffer{}
+		err := hercules.Registry.Summon(key)[0].(hercules.LeafPipelineItem).Serialize(
+			val, hercules.ProtobufFormat, &buffer)
 		if err != nil {
 			panic(err)
 		}
-		os.Stdout.Write(serialized)
-	},
+		mergedMessage.Contents[key] = buffer.Bytes()
+	}
+	serialized, err := proto.Marshal(&mergedMessage)
+	if err != nil {
+		panic(err)
+	}
+	writer.Write(serialized)
 }
 
 func loadMessage(fileName string, repos *[]string) (
 	map[string]interface{}, *hercules.CommonAnalysisResult, []string) {
+	message, errs := readMessage(fileName)
+	if message == nil {
+		return nil, nil, errs
+	}
+	*repos = append(*repos, message.Header.Repository)
+	results, deserializeErrs := deserializeContents(fileName, message.Contents)
+	errs = append(errs, deserializeErrs...)
+	// tag the commits with their own origin before they are merged with the others
+	if commits, exists := results[(&leaves.CommitsAnalysis{}).Name()]; exists {
+		commits.(leaves.CommitsResult).TagRepository(message.Header.Repository)
+	}
+	return results, hercules.MetadataToCommonAnalysisResult(message.Header), errs
+}
+
+// readMessage parses the binary analysis results. The returned message is nil if the file
+// cannot be read or its header is missing.
+func readMessage(fileName string) (*pb.AnalysisResults, []string) {
 	var errs []string
 	fi, err := os.Stat(fileName)
 	if err != nil {
 		errs = append(errs, "Cannot access "+fileName+": "+err.Error())
-		return nil, nil, errs
+		return nil, errs
 	}
 	if fi.Size() == 0 {
 		errs = append(errs, "Cannot parse "+fileName+": file size is 0")
-		return nil, nil, errs
+		return nil, errs
 	}
 	buffer, err := ioutil.ReadFile(fileName)
 	if err != nil {
 		errs = append(errs, "Cannot read "+fileName+": "+err.Error())
-		return nil, nil, errs
+		return nil, errs
 	}
-	message := pb.AnalysisResults{}
-	err = proto.Unmarshal(buffer, &message)
+	message := &pb.AnalysisResults{}
+	err = proto.Unmarshal(buffer, message)
 	if err != nil {
 		errs = append(errs, "Cannot parse "+fileName+": "+err.Error())
-		return nil, nil, errs
+		return nil, errs
 	}
 	if message.Header == nil {
 		errs = append(errs, "Cannot parse "+fileName+": corrupted header")
-		return nil, nil, errs
+		return nil, errs
 	}
-	*repos = append(*repos, message.Header.Repository)
+	return message, errs
+}
+
+// deserializeContents converts the serialized results of each analysis to the objects returned
+// by the corresponding ResultMergeablePipelineItem.Deserialize().
+func deserializeContents(fileName string, contents map[string][]byte) (
+	map[string]interface{}, []string) {
+	var errs []string
 	results := map[string]interface{}{}
-	for key, val := range message.Contents {
+	for key, val := range contents {
 		summoned := hercules.Registry.Summon(key)
 		if len(summoned) == 0 {
 			errs = append(errs, fileName+": item not found: "+key)
@@ -144,7 +182,7 @@ func loadMessage(fileName string, repos *[]string) (
 		}
 		results[key] = msg
 	}
-	return results, hercules.MetadataToCommonAnalysisResult(message.Header), errs
+	return results, errs
 }
 
 func printErrors(allErrors map[string][]string) {
@@ -169,11 +207,13 @@ func printErrors(allErrors map[string][]string) {
 	}
 }
 
+// mergeResults joins anotherResults into mergedResults. The items are configured with the facts
+// before merging if they are not nil.
 func mergeResults(mergedResults map[string]interface{},
 	mergedCommons *hercules.CommonAnalysisResult,
 	anotherResults map[string]interface{},
 	anotherCommons *hercules.CommonAnalysisResult,
-	only string) {
+	only string, facts map[string]interface{}) {
 	for key, val := range anotherResults {
 		if only != "" && key != only {
 			continue
@@ -184,6 +224,11 @@ func mergeResults(mergedResults map[string]interface{},
 			continue
 		}
 		item := hercules.Registry.Summon(key)[0].(hercules.ResultMergeablePipelineItem)
+		if facts != nil {
+			if err := item.Configure(facts); err != nil {
+				panic(err)
+			}
+		}
 		mergedResult = item.MergeResults(mergedResult, val, mergedCommons, anotherCommons)
 		mergedResults[key] = mergedResult
 	}
@@ -207,4 +252,6 @@ func init() {
 	combineCmd.SetUsageFunc(combineCmd.UsageFunc())
 	combineCmd.Flags().String("only", "", "Consider only the specified analysis. "+
 		"Empty means all available. Choices: "+getOptionsString()+".")
+	combineCmd.Flags().Bool("file-history-prefix-repository", false,
+		"Prepend the repository names to the file paths in the merged FileHistoryAnalysis results.")
 }
@@ -0,0 +1,37 @@
+// +build tensorflow
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+func TestMergeResultsCommentSentiment(t *testing.T) {
+	name := (&leaves.CommentSentimentAnalysis{}).Name()
+	mergedCommons := &hercules.CommonAnalysisResult{
+		BeginTime: 1514764800, EndTime: 1514851200, CommitsNumber: 2}
+	mergedResults := map[string]interface{}{name: leaves.CommentSentimentResult{
+		EmotionsByDay: map[int]float32{0: 0.25},
+		CommentsByDay: map[int][]string{0: {"this is the first comment"}},
+	}}
+	anotherCommons := &hercules.CommonAnalysisResult{
+		BeginTime: 1514851200, EndTime: 1514937600, CommitsNumber: 3}
+	anotherResults := map[string]interface{}{name: leaves.CommentSentimentResult{
+		EmotionsByDay: map[int]float32{0: 0.75},
+		CommentsByDay: map[int][]string{0: {"this is the second comment"}},
+	}}
+	// the results do not carry items.FactCommitsByDay
+	facts := map[string]interface{}{leaves.ConfigFileHistoryPrefixRepository: false}
+	assert.NotPanics(t, func() {
+		mergeResults(mergedResults, mergedCommons, anotherResults, anotherCommons, "", facts)
+	})
+	merged := mergedResults[name].(leaves.CommentSentimentResult)
+	assert.Equal(t, map[int]float32{0: 0.25, 1: 0.75}, merged.EmotionsByDay)
+	assert.Equal(t, map[int][]string{
+		0: {"this is the first comment"}, 1: {"this is the second comment"}}, merged.CommentsByDay)
+	assert.Equal(t, 5, mergedCommons.CommitsNumber)
+}
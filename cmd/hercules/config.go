@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/yaml.v2"
+)
+
+// analysisConfig is the contents of the YAML file passed to --config, for example:
+//
+//	leaves: [burndown, devs]
+//	features: [uast]
+//	facts:
+//	  Burndown.Granularity: 30
+//	  Burndown.TrackPeople: true
+//	  IdentityDetector.PeopleDictPath: people.txt
+type analysisConfig struct {
+	// Leaves are the command line flags of the analyses to run, e.g. "burndown".
+	Leaves []string `yaml:"leaves"`
+	// Features are the same as the values of --feature.
+	Features []string `yaml:"features"`
+	// Facts are the values of the configuration options indexed by ConfigurationOption.Name.
+	Facts map[string]interface{} `yaml:"facts"`
+}
+
+// loadConfig reads the YAML configuration file and validates it against hercules.Registry.
+// The facts are converted to the types of the corresponding configuration options.
+// The validation errors are *hercules.UnknownConfigurationOptionError,
+// *hercules.ConfigurationOptionTypeError and *hercules.UnknownLeafError.
+func loadConfig(path string) (*analysisConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &analysisConfig{}
+	err = yaml.UnmarshalStrict(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	config.Facts, err = hercules.Registry.ConvertFacts(config.Facts)
+	if err != nil {
+		return nil, err
+	}
+	for _, flag := range config.Leaves {
+		if _, err = hercules.Registry.SummonLeaf(flag); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// applyConfig merges the configuration file with the command line: the facts, the analyses
+// and the features from the file take effect only if the corresponding flags were not
+// explicitly specified. `facts` and `deployed` are returned by hercules.Registry.AddFlags().
+func applyConfig(config *analysisConfig, flags *pflag.FlagSet,
+	facts map[string]interface{}, deployed map[string]*bool) error {
+	options := hercules.Registry.GetConfigurationOptions()
+	for name, value := range config.Facts {
+		if !flags.Changed(options[name].Flag) {
+			facts[name] = value
+		}
+	}
+	for _, flag := range config.Leaves {
+		leaf, err := hercules.Registry.SummonLeaf(flag)
+		if err != nil {
+			return err
+		}
+		if !flags.Changed(flag) {
+			*deployed[leaf.Name()] = true
+		}
+	}
+	if !flags.Changed("feature") {
+		for _, feature := range config.Features {
+			if err := flags.Set("feature", feature); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
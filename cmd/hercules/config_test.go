@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	file, err := ioutil.TempFile("", "hercules-config-")
+	assert.Nil(t, err)
+	file.WriteString(contents)
+	file.Close()
+	return file.Name()
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `leaves: [burndown, devs]
+facts:
+  Burndown.Granularity: 15
+  Burndown.TrackPeople: true
+  Pipeline.Workers: 4
+`)
+	defer os.Remove(path)
+	config, err := loadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"burndown", "devs"}, config.Leaves)
+	assert.Equal(t, map[string]interface{}{
+		"Burndown.Granularity":         15,
+		"Burndown.TrackPeople":         true,
+		hercules.ConfigPipelineWorkers: 4,
+	}, config.Facts)
+
+	_, err = loadConfig(path + "xxx")
+	assert.NotNil(t, err)
+	for contents, errType := range map[string]interface{}{
+		"facts:\n  Burndown.Xxx: 1\n":         &hercules.UnknownConfigurationOptionError{},
+		"facts:\n  Burndown.Granularity: x\n": &hercules.ConfigurationOptionTypeError{},
+		"leaves: [xxx]\n":                     &hercules.UnknownLeafError{},
+	} {
+		invalidPath := writeConfig(t, contents)
+		_, err = loadConfig(invalidPath)
+		os.Remove(invalidPath)
+		assert.IsType(t, errType, err, contents)
+	}
+	invalidPath := writeConfig(t, "analyses: [burndown]\n")
+	defer os.Remove(invalidPath)
+	_, err = loadConfig(invalidPath)
+	assert.NotNil(t, err)
+}
+
+func TestApplyConfig(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	facts, deployed := hercules.Registry.AddFlags(flags)
+	assert.Nil(t, flags.Parse([]string{"--granularity", "10", "--devs=false"}))
+	config := &analysisConfig{
+		Leaves: []string{"burndown", "devs"},
+		Facts: map[string]interface{}{
+			"Burndown.Granularity": 15,
+			"Burndown.Sampling":    15,
+		},
+	}
+	assert.Nil(t, applyConfig(config, flags, facts, deployed))
+	assert.True(t, *deployed["Burndown"])
+	assert.False(t, *deployed["Devs"])
+	assert.Equal(t, 10, facts["Burndown.Granularity"])
+	assert.Equal(t, 15, facts["Burndown.Sampling"])
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/src-d/hercules.v9/internal/pb"
+	"gopkg.in/src-d/hercules.v9/internal/yaml"
+)
+
+const (
+	convertFormatYAML     = "yaml"
+	convertFormatJSON     = "json"
+	convertFormatProtobuf = "pb"
+)
+
+// detectResultsFormat guesses the format of the analysis results by the first bytes.
+func detectResultsFormat(data []byte) string {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return convertFormatJSON
+	}
+	if bytes.HasPrefix(trimmed, []byte("hercules:")) {
+		return convertFormatYAML
+	}
+	return convertFormatProtobuf
+}
+
+// splitYAMLSections cuts the YAML results into the top-level blocks and removes their indentation,
+// so that each block becomes a standalone YAML document. It returns the keys in the original
+// order. We do not decode the whole document at once because the generic unmarshalling loses
+// the original text of the scalars, e.g. the commit hashes which look like numbers.
+func splitYAMLSections(text []byte) ([]string, map[string][]byte, error) {
+	var keys []string
+	sections := map[string]*bytes.Buffer{}
+	var current *bytes.Buffer
+	for i, line := range bytes.Split(text, []byte("\n")) {
+		if len(line) > 0 && line[0] != ' ' {
+			if !bytes.HasSuffix(line, []byte(":")) {
+				return nil, nil, fmt.Errorf("line %d: a top-level key is expected", i+1)
+			}
+			key := strings.TrimSuffix(string(line), ":")
+			if _, exists := sections[key]; exists {
+				return nil, nil, fmt.Errorf("line %d: duplicate key %s", i+1, key)
+			}
+			current = &bytes.Buffer{}
+			sections[key] = current
+			keys = append(keys, key)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		current.Write(bytes.TrimPrefix(line, []byte("  ")))
+		current.WriteByte('\n')
+	}
+	result := map[string][]byte{}
+	for key, buffer := range sections {
+		result[key] = buffer.Bytes()
+	}
+	return keys, result, nil
+}
+
+// readTextResults parses the YAML or JSON analysis results.
+// It returns the metadata, the deserialized results and the errors which are not fatal.
+func readTextResults(data []byte, format string) (
+	*pb.Metadata, map[string]interface{}, []string, error) {
+	var header textHeader
+	sections := map[string][]byte{}
+	var serializationFormat hercules.SerializationFormat
+	switch format {
+	case convertFormatYAML:
+		serializationFormat = hercules.YAMLFormat
+		_, yamlSections, err := splitYAMLSections(data)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for key, section := range yamlSections {
+			if key == "hercules" {
+				if err := yaml.Unmarshal(section, &header); err != nil {
+					return nil, nil, nil, fmt.Errorf("hercules: %v", err)
+				}
+				continue
+			}
+			sections[key] = section
+		}
+	case convertFormatJSON:
+		serializationFormat = hercules.JSONFormat
+		var message map[string]json.RawMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, nil, nil, err
+		}
+		for key, section := range message {
+			if key == "hercules" {
+				if err := json.Unmarshal(section, &header); err != nil {
+					return nil, nil, nil, fmt.Errorf("hercules: %v", err)
+				}
+				continue
+			}
+			sections[key] = section
+		}
+	default:
+		return nil, nil, nil, fmt.Errorf("%s is not a text format", format)
+	}
+	var errs []string
+	results := map[string]interface{}{}
+	for key, section := range sections {
+		summoned := hercules.Registry.Summon(key)
+		if len(summoned) == 0 {
+			errs = append(errs, "item not found: "+key)
+			continue
+		}
+		tdpi, ok := summoned[0].(hercules.TextDeserializablePipelineItem)
+		if !ok {
+			errs = append(errs, key+": TextDeserializablePipelineItem is not implemented")
+			continue
+		}
+		result, err := tdpi.DeserializeText(section, serializationFormat)
+		if err != nil {
+			errs = append(errs, key+": deserialization error: "+err.Error())
+			continue
+		}
+		results[key] = result
+	}
+	return header.metadata(), results, errs, nil
+}
+
+// writeConvertedResults serializes the analysis results in the specified format.
+func writeConvertedResults(
+	writer io.Writer, format string, header *pb.Metadata, results map[string]interface{}) error {
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	serialize := func(key string, format hercules.SerializationFormat, writer io.Writer) error {
+		item := hercules.Registry.Summon(key)[0].(hercules.LeafPipelineItem)
+		if err := item.Serialize(results[key], format, writer); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		return nil
+	}
+	switch format {
+	case convertFormatYAML:
+		printHeader(writer, header)
+		for _, key := range keys {
+			fmt.Fprintf(writer, "%s:\n", key)
+			if err := serialize(key, hercules.YAMLFormat, writer); err != nil {
+				return err
+			}
+		}
+	case convertFormatJSON:
+		message := map[string]interface{}{"hercules": jsonHeader(header)}
+		for _, key := range keys {
+			buffer := &bytes.Buffer{}
+			if err := serialize(key, hercules.JSONFormat, buffer); err != nil {
+				return err
+			}
+			message[key] = json.RawMessage(buffer.Bytes())
+		}
+		return json.NewEncoder(writer).Encode(message)
+	case convertFormatProtobuf:
+		message := pb.AnalysisResults{Header: header, Contents: map[string][]byte{}}
+		for _, key := range keys {
+			buffer := &bytes.Buffer{}
+			if err := serialize(key, hercules.ProtobufFormat, buffer); err != nil {
+				return err
+			}
+			message.Contents[key] = buffer.Bytes()
+		}
+		serialized, err := proto.Marshal(&message)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(serialized)
+		return err
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+	return nil
+}
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <results>",
+	Short: "Convert the analysis results between YAML, JSON and Protocol Buffers.",
+	Long: `Read the analysis results in any of the supported formats and write them in another format
+to stdout. The input format is detected automatically. "-" reads the results from stdin.
+The binary results are converted to YAML by default, and the text results to Protocol Buffers.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			panic(err)
+		}
+		fileName := args[0]
+		var data []byte
+		if fileName == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(fileName)
+		}
+		if err != nil {
+			log.Fatalf("Cannot read %s: %v", fileName, err)
+		}
+		inputFormat := detectResultsFormat(data)
+		if format == "" {
+			if inputFormat == convertFormatProtobuf {
+				format = convertFormatYAML
+			} else {
+				format = convertFormatProtobuf
+			}
+		}
+		var header *pb.Metadata
+		var results map[string]interface{}
+		var errs []string
+		if inputFormat == convertFormatProtobuf {
+			message := &pb.AnalysisResults{}
+			if err = proto.Unmarshal(data, message); err != nil {
+				log.Fatalf("Cannot parse %s: %v", fileName, err)
+			}
+			header = message.Header
+			if header == nil {
+				header = &pb.Metadata{}
+			}
+			results, errs = deserializeContents(fileName, message.Contents)
+		} else {
+			header, results, errs, err = readTextResults(data, inputFormat)
+			if err != nil {
+				log.Fatalf("Cannot parse %s: %v", fileName, err)
+			}
+		}
+		printErrors(map[string][]string{fileName: errs})
+		if err = writeConvertedResults(os.Stdout, format, header, results); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.SetUsageFunc(convertCmd.UsageFunc())
+	convertCmd.Flags().String("format", "", "Output format: yaml, json or pb. "+
+		"The default is yaml for the binary input and pb otherwise.")
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9/internal/pb"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+func TestDetectResultsFormat(t *testing.T) {
+	assert.Equal(t, convertFormatJSON, detectResultsFormat([]byte(` {"hercules": {}}`)))
+	assert.Equal(t, convertFormatYAML, detectResultsFormat([]byte("hercules:\n  version: 10\n")))
+	assert.Equal(t, convertFormatProtobuf, detectResultsFormat([]byte{0x0a, 0x10, 0x08}))
+}
+
+func TestSplitYAMLSections(t *testing.T) {
+	keys, sections, err := splitYAMLSections([]byte(`hercules:
+  version: 10
+  hash: 1234e5
+Devs:
+  days:
+    1: {}
+  people:
+  - "one"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hercules", "Devs"}, keys)
+	assert.Equal(t, "version: 10\nhash: 1234e5\n", string(sections["hercules"]))
+	assert.Equal(t, "days:\n  1: {}\npeople:\n- \"one\"\n\n", string(sections["Devs"]))
+	_, _, err = splitYAMLSections([]byte("hercules:\nversion: 10\n"))
+	assert.NotNil(t, err)
+	_, _, err = splitYAMLSections([]byte("Devs:\nDevs:\n"))
+	assert.NotNil(t, err)
+}
+
+func TestConvertResults(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	burndown, err := ioutil.ReadFile(filepath.Join(
+		filepath.Dir(filename), "..", "..", "internal", "test_data", "burndown.pb"))
+	assert.Nil(t, err)
+	header := &pb.Metadata{
+		Version:       10,
+		Hash:          "1234e5",
+		Repository:    "src-d/hercules",
+		BeginUnixTime: 1514764800,
+		EndUnixTime:   1546300800,
+		Commits:       100,
+		RunTime:       1500,
+		Signatures:    "committer",
+	}
+	results, errs := deserializeContents("test.pb", map[string][]byte{"Burndown": burndown})
+	assert.Len(t, errs, 0)
+	original := results["Burndown"].(leaves.BurndownResult)
+	for _, format := range []string{convertFormatYAML, convertFormatJSON} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, writeConvertedResults(buffer, format, header, results))
+		assert.Equal(t, format, detectResultsFormat(buffer.Bytes()))
+		header2, results2, errs, err := readTextResults(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Len(t, errs, 0, format)
+		assert.Equal(t, header, header2, format)
+		converted := results2["Burndown"].(leaves.BurndownResult)
+		assert.Equal(t, original.ReversedPeopleDict(), converted.ReversedPeopleDict(), format)
+		assert.Equal(t, original.Granularity(), converted.Granularity(), format)
+		assert.Equal(t, original.Sampling(), converted.Sampling(), format)
+		assert.Len(t, converted.GlobalHistory, len(original.GlobalHistory), format)
+		assert.Len(t, converted.FileHistories, len(original.FileHistories), format)
+
+		buffer = &bytes.Buffer{}
+		assert.Nil(t, writeConvertedResults(buffer, convertFormatProtobuf, header2, results2))
+		message := &pb.AnalysisResults{}
+		assert.Nil(t, proto.Unmarshal(buffer.Bytes(), message))
+		assert.Equal(t, header, message.Header, format)
+		assert.Contains(t, message.Contents, "Burndown", format)
+	}
+	_, _, errs, err = readTextResults([]byte(`{"hercules": {}, "Unknown": {}}`), convertFormatJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"item not found: Unknown"}, errs)
+	_, _, _, err = readTextResults([]byte("garbage"), convertFormatProtobuf)
+	assert.NotNil(t, err)
+}
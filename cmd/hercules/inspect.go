@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/hercules.v9/internal/pb"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <result.pb>",
+	Short: "Print the summary of the binary analysis results.",
+	Long: `Print the metadata header, the list of the contained analyses and a short summary of each:
+the matrix dimensions for Burndown, the number of files and people for Couples, the day range
+for Devs, etc.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		message, errs := readMessage(args[0])
+		if message != nil {
+			errs = append(errs, inspectMessage(os.Stdout, args[0], message)...)
+		}
+		printErrors(map[string][]string{args[0]: errs})
+		if message == nil {
+			os.Exit(1)
+		}
+	},
+}
+
+// inspectMessage writes the human-readable summary of the parsed analysis results.
+// It returns the deserialization errors.
+func inspectMessage(writer io.Writer, fileName string, message *pb.AnalysisResults) []string {
+	header := message.Header
+	fmt.Fprintln(writer, "hercules:")
+	fmt.Fprintf(writer, "  version: %d\n", header.Version)
+	fmt.Fprintf(writer, "  hash: %s\n", header.Hash)
+	fmt.Fprintf(writer, "  repository: %s\n", header.Repository)
+	fmt.Fprintf(writer, "  begin_unix_time: %d  # %s\n", header.BeginUnixTime, formatUnixTime(header.BeginUnixTime))
+	fmt.Fprintf(writer, "  end_unix_time: %d  # %s\n", header.EndUnixTime, formatUnixTime(header.EndUnixTime))
+	fmt.Fprintf(writer, "  commits: %d\n", header.Commits)
+	fmt.Fprintf(writer, "  run_time: %d  # %s\n", header.RunTime,
+		time.Duration(header.RunTime)*time.Millisecond)
+	if header.Signatures != "" {
+		fmt.Fprintf(writer, "  signatures: %s\n", header.Signatures)
+	}
+	if len(header.RunTimePerItem) > 0 {
+		fmt.Fprintln(writer, "  run_time_per_item:")
+		items := make([]string, 0, len(header.RunTimePerItem))
+		for key := range header.RunTimePerItem {
+			items = append(items, key)
+		}
+		sort.Strings(items)
+		for _, key := range items {
+			fmt.Fprintf(writer, "    %s: %.3f\n", key, header.RunTimePerItem[key])
+		}
+	}
+	keys := make([]string, 0, len(message.Contents))
+	for key := range message.Contents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(writer, "contents:")
+	for _, key := range keys {
+		fmt.Fprintf(writer, "  - %s  # %d bytes\n", key, len(message.Contents[key]))
+	}
+	results, errs := deserializeContents(fileName, message.Contents)
+	begin := time.Unix(header.BeginUnixTime, 0)
+	for _, key := range keys {
+		result, exists := results[key]
+		if !exists {
+			continue
+		}
+		fmt.Fprintf(writer, "%s:\n", key)
+		for _, line := range summarizeResult(result, begin) {
+			fmt.Fprintf(writer, "  %s\n", line)
+		}
+	}
+	return errs
+}
+
+// summarizeResult describes the deserialized result of a single analysis in a few lines.
+func summarizeResult(result interface{}, begin time.Time) []string {
+	switch result := result.(type) {
+	case leaves.BurndownResult:
+		lines := []string{
+			fmt.Sprintf("granularity: %d", result.Granularity()),
+			fmt.Sprintf("sampling: %d", result.Sampling()),
+			"project: " + formatMatrixShape(result.GlobalHistory),
+			fmt.Sprintf("files: %d", len(result.FileHistories)),
+			fmt.Sprintf("people: %d", len(result.ReversedPeopleDict())),
+		}
+		if len(result.PeopleHistories) > 0 {
+			lines = append(lines, fmt.Sprintf("people_histories: %d", len(result.PeopleHistories)))
+		}
+		if len(result.PeopleMatrix) > 0 {
+			lines = append(lines, "people_interaction: "+formatMatrixShape(result.PeopleMatrix))
+		}
+		return lines
+	case leaves.CouplesResult:
+		return []string{
+			fmt.Sprintf("files: %d", len(result.Files)),
+			fmt.Sprintf("people: %d", len(result.PeopleMatrix)),
+		}
+	case leaves.DevsResult:
+		lines := []string{
+			fmt.Sprintf("people: %d", len(result.ReversedPeopleDict())),
+			fmt.Sprintf("active_days: %d", len(result.Days)),
+		}
+		if len(result.Days) > 0 {
+			first, last := -1, -1
+			for day := range result.Days {
+				if first < 0 || day < first {
+					first = day
+				}
+				if day > last {
+					last = day
+				}
+			}
+			lines = append(lines, fmt.Sprintf("days: %d - %d  # %s - %s", first, last,
+				begin.AddDate(0, 0, first).UTC().Format("2006-01-02"),
+				begin.AddDate(0, 0, last).UTC().Format("2006-01-02")))
+		}
+		return lines
+	case leaves.FileHistoryResult:
+		return []string{fmt.Sprintf("files: %d", len(result.Files))}
+	case leaves.ShotnessResult:
+		return []string{fmt.Sprintf("nodes: %d", len(result.Nodes))}
+	case leaves.CommitsResult:
+		return []string{fmt.Sprintf("commits: %d", len(result.Commits))}
+	}
+	return []string{fmt.Sprintf("type: %T", result)}
+}
+
+// formatMatrixShape returns the dimensions of the dense matrix, e.g. "12 x 10".
+func formatMatrixShape(matrix leaves.DenseHistory) string {
+	columns := 0
+	for _, row := range matrix {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	return fmt.Sprintf("%d x %d", len(matrix), columns)
+}
+
+func formatUnixTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.SetUsageFunc(inspectCmd.UsageFunc())
+}
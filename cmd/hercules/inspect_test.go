@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9/internal/pb"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+func TestInspectMessage(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	burndown, err := ioutil.ReadFile(filepath.Join(
+		filepath.Dir(filename), "..", "..", "internal", "test_data", "burndown.pb"))
+	assert.Nil(t, err)
+	message := &pb.AnalysisResults{
+		Header: &pb.Metadata{
+			Version:        2,
+			Hash:           "abcdef",
+			Repository:     "src-d/hercules",
+			BeginUnixTime:  1514764800,
+			EndUnixTime:    1546300800,
+			Commits:        100,
+			RunTime:        1500,
+			RunTimePerItem: map[string]float64{"Burndown": 1.25},
+			Signatures:     "both",
+		},
+		Contents: map[string][]byte{
+			"Burndown": burndown,
+			"Unknown":  {1, 2, 3},
+		},
+	}
+	buffer := &bytes.Buffer{}
+	errs := inspectMessage(buffer, "test.pb", message)
+	assert.Equal(t, []string{"test.pb: item not found: Unknown"}, errs)
+	output := buffer.String()
+	assert.Contains(t, output, "  repository: src-d/hercules\n")
+	assert.Contains(t, output, "  begin_unix_time: 1514764800  # 2018-01-01T00:00:00Z\n")
+	assert.Contains(t, output, "  commits: 100\n")
+	assert.Contains(t, output, "  run_time: 1500  # 1.5s\n")
+	assert.Contains(t, output, "  signatures: both\n")
+	assert.Contains(t, output, "    Burndown: 1.250\n")
+	assert.Contains(t, output, "contents:\n  - Burndown  # ")
+	assert.Contains(t, output, "\n  - Unknown  # 3 bytes\n")
+	assert.Contains(t, output, "\nBurndown:\n  granularity: 30\n  sampling: 30\n  project: ")
+	assert.NotContains(t, output, "\nUnknown:")
+}
+
+func TestSummarizeResult(t *testing.T) {
+	begin := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"files: 2", "people: 3"}, summarizeResult(leaves.CouplesResult{
+		Files:        []string{"a", "b"},
+		PeopleMatrix: make([]map[int]int64, 3),
+	}, begin))
+	assert.Equal(t, []string{
+		"people: 0", "active_days: 2", "days: 3 - 10  # 2018-01-04 - 2018-01-11",
+	}, summarizeResult(leaves.DevsResult{Days: map[int]map[int]*leaves.DevDay{
+		10: {}, 3: {},
+	}}, begin))
+	assert.Equal(t, []string{"type: int"}, summarizeResult(7, begin))
+	assert.Equal(t, "2 x 3", formatMatrixShape(leaves.DenseHistory{{1}, {1, 2, 3}}))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+
+	"gopkg.in/src-d/hercules.v9"
+)
+
+// openMetricsContentType is the HTTP Content-Type of the OpenMetrics text format.
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// openMetricsFamilies collects the metrics of the deployed leaves which implement
+// MetricsPipelineItem together with the common analysis metrics. Every sample is labelled
+// with the repository.
+func openMetricsFamilies(
+	uri string, deployed []hercules.LeafPipelineItem,
+	results map[hercules.LeafPipelineItem]interface{}) []hercules.MetricFamily {
+	commonResult := results[nil].(*hercules.CommonAnalysisResult)
+	families := []hercules.MetricFamily{{
+		Name:    "hercules_commits",
+		Help:    "Number of analysed commits.",
+		Type:    hercules.GaugeMetric,
+		Metrics: []hercules.Metric{{Value: float64(commonResult.CommitsNumber)}},
+	}, {
+		Name:    "hercules_begin_time_seconds",
+		Help:    "Time of the first analysed commit.",
+		Type:    hercules.GaugeMetric,
+		Metrics: []hercules.Metric{{Value: float64(commonResult.BeginTime)}},
+	}, {
+		Name:    "hercules_end_time_seconds",
+		Help:    "Time of the last analysed commit.",
+		Type:    hercules.GaugeMetric,
+		Metrics: []hercules.Metric{{Value: float64(commonResult.EndTime)}},
+	}}
+	for _, item := range deployed {
+		exporter, ok := item.(hercules.MetricsPipelineItem)
+		if !ok {
+			continue
+		}
+		result, exists := results[item]
+		if !exists {
+			// the analysis was interrupted and the item could not finalize
+			continue
+		}
+		families = append(families, exporter.Metrics(result)...)
+	}
+	for i := range families {
+		for j, metric := range families[i].Metrics {
+			labels := map[string]string{"repository": uri}
+			for key, val := range metric.Labels {
+				labels[key] = val
+			}
+			families[i].Metrics[j].Labels = labels
+		}
+	}
+	return families
+}
+
+func openMetricsResults(
+	writer io.Writer, uri string, deployed []hercules.LeafPipelineItem,
+	results map[hercules.LeafPipelineItem]interface{}) {
+	if err := hercules.WriteOpenMetrics(writer, openMetricsFamilies(uri, deployed, results)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+// peopleCmd represents the people command
+var peopleCmd = &cobra.Command{
+	Use:   "people <repository> [cache]",
+	Short: "Print the identities of the developers in the --people-dict format.",
+	Long: `Run only the identity detection over the commits and write the merged identities to stdout
+in the format of --people-dict: one developer per line, the names and the emails separated with "|".
+Every line is preceded by a comment with the number of commits and the dates of the first and the
+last ones, and the bots are marked with "[bot] ". The output is intended to be reviewed, edited
+and passed back with --people-dict; the identity options such as --mailmap, --co-authors, --bots
+and --signatures as well as the commit selection flags such as --since, --ref and --all-refs apply
+the same way as in the analyses.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		disableStatus, err := flags.GetBool("quiet")
+		if err != nil {
+			panic(err)
+		}
+		sshIdentity, err := flags.GetString("ssh-identity")
+		if err != nil {
+			panic(err)
+		}
+		selection, err := parseCommitsFlags(flags)
+		if err != nil {
+			log.Fatal(err)
+		}
+		uri := args[0]
+		cachePath := ""
+		if len(args) == 2 {
+			cachePath = args[1]
+		}
+		repository := loadRepository(uri, cachePath, disableStatus, sshIdentity)
+		pipeline := hercules.NewPipeline(repository)
+		pipeline.Range = selection.Range
+		commits, err := selection.listCommits(pipeline, repository)
+		if err != nil {
+			log.Fatalf("failed to list the commits: %v", err)
+		}
+		if len(commits) == 0 {
+			log.Fatal("there are no commits to analyse")
+		}
+		peopleFacts[hercules.ConfigPipelineCommits] = commits
+		if err = writePeople(os.Stdout, uri, commits, peopleFacts); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// writePeople configures identity.Detector with the facts, which must contain the commits
+// unless the identities are already known, and writes the identities in the people dict format.
+func writePeople(writer io.Writer, uri string, commits []*object.Commit,
+	facts map[string]interface{}) error {
+	detector := &identity.Detector{}
+	if err := detector.Configure(facts); err != nil {
+		return err
+	}
+	activity, err := detector.CollectActivity(commits)
+	if err != nil {
+		return err
+	}
+	peopleCount := facts[identity.FactIdentityDetectorPeopleCount].(int)
+	bots, _ := facts[identity.FactIdentityDetectorBots].(map[int]bool)
+	fmt.Fprintf(writer, "# The developers of %s, %d commits.\n", uri, len(commits))
+	fmt.Fprintln(writer, "# Review, edit and pass this file to --people-dict.")
+	return detector.WritePeopleDict(writer, peopleCount, activity, bots)
+}
+
+var peopleFacts map[string]interface{}
+
+func init() {
+	rootCmd.AddCommand(peopleCmd)
+	peopleCmd.SetUsageFunc(peopleCmd.UsageFunc())
+	peopleFlags := peopleCmd.Flags()
+	addCommitsFlags(peopleCmd)
+	peopleFlags.Bool("quiet", !terminal.IsTerminal(int(os.Stdin.Fd())),
+		"Do not print status updates to stderr.")
+	peopleFlags.String("ssh-identity", "", "Path to SSH identity file (e.g., ~/.ssh/id_rsa) to clone from an SSH remote.")
+	if err := peopleCmd.MarkFlagFilename("ssh-identity"); err != nil {
+		panic(err)
+	}
+	hercules.PathifyFlagValue(peopleFlags.Lookup("ssh-identity"))
+	peopleFacts = hercules.AddItemFlags(peopleFlags, &identity.Detector{})
+}
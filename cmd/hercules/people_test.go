@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+func TestWritePeople(t *testing.T) {
+	when := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	commits := []*object.Commit{{
+		Author:    object.Signature{Name: "Vadim", Email: "vadim@sourced.tech", When: when},
+		Committer: object.Signature{Name: "Vadim", Email: "vadim@sourced.tech", When: when},
+	}, {
+		Author: object.Signature{Name: "dependabot[bot]", Email: "support@dependabot.com"},
+		Committer: object.Signature{
+			Name: "dependabot[bot]", Email: "support@dependabot.com", When: when.AddDate(0, 1, 0)},
+	}}
+	facts := map[string]interface{}{
+		identity.FactIdentityDetectorPeopleDict: map[string]int{
+			"vadim": 0, "vadim@sourced.tech": 0, "gmarkhor@gmail.com": 0,
+			"dependabot[bot]": 1, "support@dependabot.com": 1, "egor": 2,
+		},
+		identity.FactIdentityDetectorReversedPeopleDict: []string{
+			"vadim|vadim@sourced.tech", "dependabot[bot]", "egor"},
+	}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, writePeople(buffer, "src-d/hercules", commits, facts))
+	assert.Equal(t, `# The developers of src-d/hercules, 2 commits.
+# Review, edit and pass this file to --people-dict.
+# commits: 1, first: 2018-01-01, last: 2018-01-01
+vadim|vadim@sourced.tech|gmarkhor@gmail.com
+# commits: 1, first: 2018-02-01, last: 2018-02-01
+[bot] dependabot[bot]|support@dependabot.com
+# commits: 0
+egor
+`, buffer.String())
+	facts[identity.ConfigIdentityDetectorBots] = "unknown"
+	assert.NotNil(t, writePeople(buffer, "src-d/hercules", commits, facts))
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/hercules.v9"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+	"gopkg.in/src-d/hercules.v9/internal/report"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+// namedChart is the chart together with the name of the SVG file.
+type namedChart struct {
+	name  string
+	chart report.Chart
+}
+
+// burndownCharts plots the project burndown, the code ownership and the overwrites matrix.
+func burndownCharts(
+	repo string, result leaves.BurndownResult, begin time.Time, maxPeople int) []namedChart {
+	var charts []namedChart
+	sampling, granularity := result.Sampling(), result.Granularity()
+	samples := len(result.GlobalHistory)
+	x := make([]time.Time, samples)
+	for i := range x {
+		x[i] = begin.AddDate(0, 0, i*sampling)
+	}
+	if samples > 0 {
+		bands := len(result.GlobalHistory[samples-1])
+		project := &report.StackPlot{
+			Caption: repo + " code burndown (granularity " + fmt.Sprint(granularity) +
+				", sampling " + fmt.Sprint(sampling) + ")",
+			X:      x,
+			Layers: make([][]float64, bands),
+			Names:  make([]string, bands),
+		}
+		for band := 0; band < bands; band++ {
+			project.Names[band] = begin.AddDate(0, 0, band*granularity).Format("2006-01-02")
+			project.Layers[band] = make([]float64, samples)
+			for i, row := range result.GlobalHistory {
+				if band < len(row) {
+					project.Layers[band][i] = float64(row[band])
+				}
+			}
+		}
+		charts = append(charts, namedChart{"burndown-project", project})
+	}
+	names := result.ReversedPeopleDict()
+	if len(result.PeopleHistories) > 0 {
+		people := make([][]float64, len(result.PeopleHistories))
+		for person, history := range result.PeopleHistories {
+			people[person] = make([]float64, samples)
+			for i, row := range history {
+				if i >= samples {
+					break
+				}
+				for _, value := range row {
+					people[person][i] += float64(value)
+				}
+			}
+		}
+		labels := make([]string, len(people))
+		for i := range labels {
+			labels[i] = personName(names, i)
+		}
+		layers, labels := truncatePeople(people, labels, maxPeople)
+		charts = append(charts, namedChart{"ownership", &report.StackPlot{
+			Caption: repo + " code ownership through time",
+			X:       x, Layers: layers, Names: labels,
+		}})
+	}
+	if len(result.PeopleMatrix) > 0 {
+		charts = append(charts, namedChart{"overwrites", overwritesHeatmap(
+			repo, result.PeopleMatrix, names, maxPeople)})
+	}
+	return charts
+}
+
+// truncatePeople leaves the `maxPeople` series with the biggest sums and aggregates the rest
+// as "others". The series with equal sums keep their original order.
+func truncatePeople(series [][]float64, names []string, maxPeople int) ([][]float64, []string) {
+	order := make([]int, len(series))
+	sums := make([]float64, len(series))
+	for i, values := range series {
+		order[i] = i
+		for _, value := range values {
+			sums[i] += value
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if sums[order[i]] != sums[order[j]] {
+			return sums[order[i]] > sums[order[j]]
+		}
+		return order[i] < order[j]
+	})
+	var layers [][]float64
+	var labels []string
+	for rank, i := range order {
+		if rank == maxPeople && len(order) > maxPeople {
+			others := make([]float64, len(series[i]))
+			for _, j := range order[maxPeople:] {
+				for k, value := range series[j] {
+					others[k] += value
+				}
+			}
+			layers = append(layers, others)
+			labels = append(labels, "others")
+			break
+		}
+		layers = append(layers, series[i])
+		labels = append(labels, names[i])
+	}
+	return layers, labels
+}
+
+// overwritesHeatmap normalizes the rows of the people interaction matrix by the number of
+// added lines, the same as labours.py -m overwrites-matrix does.
+func overwritesHeatmap(
+	repo string, matrix leaves.DenseHistory, names []string, maxPeople int) *report.Heatmap {
+	order := make([]int, len(matrix))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return matrix[order[i]][0] > matrix[order[j]][0]
+	})
+	if len(order) > maxPeople {
+		order = order[:maxPeople]
+	}
+	heatmap := &report.Heatmap{
+		Caption: repo + " overwrites matrix",
+		Columns: []string{"Unidentified"},
+	}
+	for _, i := range order {
+		heatmap.Rows = append(heatmap.Rows, personName(names, i))
+		heatmap.Columns = append(heatmap.Columns, personName(names, i))
+	}
+	for _, i := range order {
+		row := matrix[i]
+		values := make([]float64, len(heatmap.Columns))
+		if added := float64(row[0]); added > 0 {
+			values[0] = -float64(row[1]) / added
+			for j, k := range order {
+				if k+2 < len(row) {
+					values[j+1] = -float64(row[k+2]) / added
+				}
+			}
+		}
+		heatmap.Values = append(heatmap.Values, values)
+	}
+	return heatmap
+}
+
+// devsEffortsChart plots the cumulative number of changed lines per developer.
+func devsEffortsChart(
+	repo string, result leaves.DevsResult, begin time.Time, maxPeople int) *report.StackPlot {
+	lastDay := 0
+	for day := range result.Days {
+		if day > lastDay {
+			lastDay = day
+		}
+	}
+	names := result.ReversedPeopleDict()
+	indexes := map[int]int{}
+	var efforts [][]float64
+	var effortNames []string
+	for day := 0; day <= lastDay; day++ {
+		// the series must not depend on the map iteration order
+		devs := make([]int, 0, len(result.Days[day]))
+		for dev := range result.Days[day] {
+			devs = append(devs, dev)
+		}
+		sort.Ints(devs)
+		for _, dev := range devs {
+			stats := result.Days[day][dev]
+			index, exists := indexes[dev]
+			if !exists {
+				index = len(efforts)
+				indexes[dev] = index
+				efforts = append(efforts, make([]float64, lastDay+1))
+				name := "<unmatched>"
+				if dev != identity.AuthorMissing {
+					name = personName(names, dev)
+				}
+				effortNames = append(effortNames, name)
+			}
+			efforts[index][day] += float64(stats.Added + stats.Removed + stats.Changed)
+		}
+	}
+	for _, series := range efforts {
+		for day := 1; day < len(series); day++ {
+			series[day] += series[day-1]
+		}
+	}
+	layers, labels := truncatePeople(efforts, effortNames, maxPeople)
+	x := make([]time.Time, lastDay+1)
+	for i := range x {
+		x[i] = begin.AddDate(0, 0, i)
+	}
+	return &report.StackPlot{
+		Caption: repo + " cumulative efforts through time (changed lines)",
+		X:       x, Layers: layers, Names: labels,
+	}
+}
+
+func personName(names []string, index int) string {
+	if index >= 0 && index < len(names) {
+		return names[index]
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report <result.pb>",
+	Short: "Plot the binary analysis results as SVG charts in pure Go.",
+	Long: `Render the common plots without labours.py: the project burndown, the code ownership
+and the overwrites matrix from --burndown (--burndown-people for the latter two) and the
+developer efforts from --devs. The result is a self-contained HTML page which is written to
+stdout, or separate SVG files if --svg is specified.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		maxPeople, err := flags.GetInt("max-people")
+		if err != nil {
+			panic(err)
+		}
+		if maxPeople < 1 {
+			log.Fatalf("--max-people must be positive, got %d", maxPeople)
+		}
+		svgDir, err := flags.GetString("svg")
+		if err != nil {
+			panic(err)
+		}
+		var repos []string
+		results, metadata, errs := loadMessage(args[0], &repos)
+		printErrors(map[string][]string{args[0]: errs})
+		if metadata == nil {
+			os.Exit(1)
+		}
+		repo := repos[0]
+		begin := metadata.BeginTimeAsTime()
+		var charts []namedChart
+		if result, exists := results[(&leaves.BurndownAnalysis{}).Name()]; exists {
+			charts = append(charts, burndownCharts(
+				repo, result.(leaves.BurndownResult), begin, maxPeople)...)
+		}
+		if result, exists := results[(&leaves.DevsAnalysis{}).Name()]; exists {
+			charts = append(charts, namedChart{"devs-efforts", devsEffortsChart(
+				repo, result.(leaves.DevsResult), begin, maxPeople)})
+		}
+		if len(charts) == 0 {
+			log.Fatalf("%s contains neither Burndown nor Devs results", args[0])
+		}
+		if svgDir == "" {
+			plain := make([]report.Chart, len(charts))
+			for i, chart := range charts {
+				plain[i] = chart.chart
+			}
+			if err = report.WriteHTML(os.Stdout, repo, plain); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		if err = os.MkdirAll(svgDir, 0777); err != nil {
+			log.Fatal(err)
+		}
+		for _, chart := range charts {
+			path := filepath.Join(svgDir, chart.name+".svg")
+			file, err := os.Create(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = chart.chart.WriteSVG(file)
+			file.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Fprintln(os.Stderr, "wrote", path)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.SetUsageFunc(reportCmd.UsageFunc())
+	reportFlags := reportCmd.Flags()
+	reportFlags.Int("max-people", 20, "Maximum number of developers in the ownership, "+
+		"overwrites and efforts plots. The rest are aggregated as \"others\".")
+	reportFlags.String("svg", "", "Write each chart to a separate SVG file in this directory "+
+		"instead of the HTML page to stdout.")
+	err := reportCmd.MarkFlagFilename("svg")
+	if err != nil {
+		panic(err)
+	}
+	hercules.PathifyFlagValue(reportFlags.Lookup("svg"))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+	"gopkg.in/src-d/hercules.v9/internal/report"
+	"gopkg.in/src-d/hercules.v9/leaves"
+)
+
+func TestTruncatePeople(t *testing.T) {
+	series := [][]float64{{1, 1}, {5, 5}, {2, 2}, {3, 3}}
+	names := []string{"a", "b", "c", "d"}
+	layers, labels := truncatePeople(series, names, 2)
+	assert.Equal(t, []string{"b", "d", "others"}, labels)
+	assert.Equal(t, [][]float64{{5, 5}, {3, 3}, {3, 3}}, layers)
+	layers, labels = truncatePeople(series, names, 4)
+	assert.Equal(t, []string{"b", "d", "c", "a"}, labels)
+	assert.Len(t, layers, 4)
+}
+
+func TestOverwritesHeatmap(t *testing.T) {
+	matrix := leaves.DenseHistory{
+		{10, -5, 0, -5},
+		{100, 0, -50, -25},
+	}
+	heatmap := overwritesHeatmap("repo", matrix, []string{"a", "b"}, 20)
+	assert.Equal(t, []string{"b", "a"}, heatmap.Rows)
+	assert.Equal(t, []string{"Unidentified", "b", "a"}, heatmap.Columns)
+	assert.Equal(t, [][]float64{{0, 0.25, 0.5}, {0.5, 0.5, 0}}, heatmap.Values)
+	heatmap = overwritesHeatmap("repo", matrix, []string{"a", "b"}, 1)
+	assert.Equal(t, []string{"b"}, heatmap.Rows)
+	assert.Equal(t, [][]float64{{0, 0.25}}, heatmap.Values)
+}
+
+func TestBurndownCharts(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	buffer, err := ioutil.ReadFile(filepath.Join(
+		filepath.Dir(filename), "..", "..", "internal", "test_data", "burndown.pb"))
+	assert.Nil(t, err)
+	iresult, err := (&leaves.BurndownAnalysis{}).Deserialize(buffer)
+	assert.Nil(t, err)
+	result := iresult.(leaves.BurndownResult)
+	begin := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	charts := burndownCharts("repo", result, begin, 3)
+	assert.Len(t, charts, 3)
+	assert.Equal(t, "burndown-project", charts[0].name)
+	project := charts[0].chart.(*report.StackPlot)
+	assert.Len(t, project.X, len(result.GlobalHistory))
+	assert.Equal(t, begin.AddDate(0, 0, 30), project.X[1])
+	assert.Len(t, project.Layers, len(result.GlobalHistory[len(result.GlobalHistory)-1]))
+	assert.Equal(t, "ownership", charts[1].name)
+	ownership := charts[1].chart.(*report.StackPlot)
+	assert.True(t, len(ownership.Layers) <= 4)
+	assert.Equal(t, "overwrites", charts[2].name)
+	overwrites := charts[2].chart.(*report.Heatmap)
+	assert.True(t, len(overwrites.Rows) <= 3)
+	assert.Len(t, overwrites.Columns, len(overwrites.Rows)+1)
+	for _, chart := range charts {
+		assert.Nil(t, chart.chart.WriteSVG(ioutil.Discard))
+	}
+}
+
+func TestDevsEffortsChart(t *testing.T) {
+	result := leaves.DevsResult{Days: map[int]map[int]*leaves.DevDay{
+		0: {0: {LineStats: plumbing.LineStats{Added: 10}}},
+		2: {
+			1:                      {LineStats: plumbing.LineStats{Added: 5, Removed: 5, Changed: 5}},
+			identity.AuthorMissing: {LineStats: plumbing.LineStats{Added: 1}},
+		},
+	}}
+	begin := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	plot := devsEffortsChart("repo", result, begin, 20)
+	assert.Len(t, plot.X, 3)
+	assert.Equal(t, begin.AddDate(0, 0, 2), plot.X[2])
+	assert.Len(t, plot.Layers, 3)
+	assert.Equal(t, []string{"#0", "#1", "<unmatched>"}, plot.Names)
+	assert.Equal(t, []float64{10, 10, 10}, plot.Layers[0])
+	assert.Equal(t, []float64{0, 0, 15}, plot.Layers[1])
+
+	// the developers with equal efforts are always ordered the same way
+	day := map[int]*leaves.DevDay{}
+	for dev := 0; dev < 10; dev++ {
+		day[dev] = &leaves.DevDay{LineStats: plumbing.LineStats{Added: 1}}
+	}
+	result = leaves.DevsResult{Days: map[int]map[int]*leaves.DevDay{0: day}}
+	for i := 0; i < 10; i++ {
+		plot = devsEffortsChart("repo", result, begin, 3)
+		assert.Equal(t, []string{"#0", "#1", "#2", "others"}, plot.Names)
+	}
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,12 +14,14 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"plugin"
 	"regexp"
 	"runtime/pprof"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 
 	"github.com/Masterminds/sprig"
@@ -28,6 +35,7 @@ import (
 	"gopkg.in/src-d/go-billy.v4/memfs"
 	"gopkg.in/src-d/go-billy.v4/osfs"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/cache"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
@@ -67,21 +75,27 @@ func loadSSHIdentity(sshIdentity string) (*ssh.PublicKeys, error) {
 	return ssh.NewPublicKeysFromFile("git", actual, "")
 }
 
+// isRemoteURI returns true if the repository must be cloned, e.g. it is an HTTPS or SSH URL.
+func isRemoteURI(uri string) bool {
+	return strings.Contains(uri, "://") ||
+		regexp.MustCompile("^[A-Za-z]\\w*@[A-Za-z0-9][\\w.]*:").MatchString(uri)
+}
+
+// cacheRepositoryPath returns the directory inside cacheDir where to keep the clone of
+// the remote repository. The result is empty if cacheDir is empty or the repository is local.
+func cacheRepositoryPath(cacheDir, uri string) string {
+	if cacheDir == "" || !isRemoteURI(uri) {
+		return ""
+	}
+	hash := sha1.Sum([]byte(uri))
+	return filepath.Join(cacheDir, hex.EncodeToString(hash[:]))
+}
+
 func loadRepository(uri string, cachePath string, disableStatus bool, sshIdentity string) *git.Repository {
 	var repository *git.Repository
 	var backend storage.Storer
 	var err error
-	if strings.Contains(uri, "://") || regexp.MustCompile("^[A-Za-z]\\w*@[A-Za-z0-9][\\w.]*:").MatchString(uri) {
-		if cachePath != "" {
-			backend = filesystem.NewStorage(osfs.New(cachePath), cache.NewObjectLRUDefault())
-			_, err = os.Stat(cachePath)
-			if !os.IsNotExist(err) {
-				log.Printf("warning: deleted %s\n", cachePath)
-				os.RemoveAll(cachePath)
-			}
-		} else {
-			backend = memory.NewStorage()
-		}
+	if isRemoteURI(uri) {
 		cloneOptions := &git.CloneOptions{URL: uri}
 		if !disableStatus {
 			fmt.Fprint(os.Stderr, "connecting...\r")
@@ -96,7 +110,28 @@ func loadRepository(uri string, cachePath string, disableStatus bool, sshIdentit
 			cloneOptions.Auth = auth
 		}
 
-		repository, err = git.Clone(backend, nil, cloneOptions)
+		if cachePath != "" {
+			backend = filesystem.NewStorage(osfs.New(cachePath), cache.NewObjectLRUDefault())
+			_, err = os.Stat(cachePath)
+			if !os.IsNotExist(err) {
+				err = nil
+				cached, errOpen := git.Open(backend, nil)
+				if errOpen == nil && getRemoteURL(cached) == uri {
+					repository = cached
+					err = fetchCachedRepository(repository, cloneOptions)
+				} else {
+					log.Printf("warning: deleted %s\n", cachePath)
+					os.RemoveAll(cachePath)
+					backend = filesystem.NewStorage(osfs.New(cachePath), cache.NewObjectLRUDefault())
+				}
+			}
+		} else {
+			backend = memory.NewStorage()
+		}
+
+		if repository == nil {
+			repository, err = git.Clone(backend, nil, cloneOptions)
+		}
 		if !disableStatus {
 			fmt.Fprint(os.Stderr, "\033[2K\r")
 		}
@@ -122,6 +157,45 @@ func loadRepository(uri string, cachePath string, disableStatus bool, sshIdentit
 	return repository
 }
 
+// getRemoteURL returns the URL of the remote which the repository was cloned from.
+func getRemoteURL(repository *git.Repository) string {
+	remote, err := repository.Remote(git.DefaultRemoteName)
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+// fetchCachedRepository downloads the new objects to the repository which was cloned before.
+// It also moves the local branch which HEAD points to, because Fetch() updates only
+// the remote branches.
+func fetchCachedRepository(repository *git.Repository, cloneOptions *git.CloneOptions) error {
+	err := repository.Fetch(&git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		Auth:       cloneOptions.Auth,
+		Progress:   cloneOptions.Progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+	head, err := repository.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return err
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return nil
+	}
+	remoteHead, err := repository.Reference(plumbing.NewRemoteReferenceName(
+		git.DefaultRemoteName, head.Target().Short()), true)
+	if err != nil {
+		// the branch was deleted in the remote
+		log.Printf("warning: %s was not fetched: %v\n", head.Target(), err)
+		return nil
+	}
+	return repository.Storer.SetReference(
+		plumbing.NewHashReference(head.Target(), remoteHead.Hash()))
+}
+
 type arrayPluginFlags map[string]bool
 
 func (apf *arrayPluginFlags) String() string {
@@ -188,11 +262,50 @@ targets can be added using the --plugin system.`,
 			}
 			return value
 		}
-		firstParent := getBool("first-parent")
-		commitsFile := getString("commits")
+		if configPath := getString("config"); configPath != "" {
+			config, err := loadConfig(configPath)
+			if err != nil {
+				log.Fatalf("invalid --config: %v", err)
+			}
+			err = applyConfig(config, flags, cmdlineFacts, cmdlineDeployed)
+			if err != nil {
+				log.Fatalf("invalid --config: %v", err)
+			}
+		}
+		selection, err := parseCommitsFlags(flags)
+		if err != nil {
+			log.Fatal(err)
+		}
 		protobuf := getBool("pb")
+		jsonOutput := getBool("json")
+		if protobuf && jsonOutput {
+			log.Fatal("--pb cannot be combined with --json")
+		}
+		format := getString("format")
+		if _, exists := resultContentTypes[format]; !exists {
+			log.Fatalf("invalid --format: %s", format)
+		}
+		if protobuf || jsonOutput {
+			if flags.Changed("format") {
+				log.Fatal("--pb and --json cannot be combined with --format")
+			}
+			if protobuf {
+				format = "pb"
+			} else {
+				format = "json"
+			}
+		}
 		profile := getBool("profile")
 		disableStatus := getBool("quiet")
+		progressFormat := getString("progress")
+		switch progressFormat {
+		case "bar":
+		case "json":
+			// the events replace the human-readable status
+			disableStatus = true
+		default:
+			log.Fatalf("invalid --progress: %s", progressFormat)
+		}
 		sshIdentity := getString("ssh-identity")
 
 		if profile {
@@ -219,6 +332,7 @@ targets can be added using the --plugin system.`,
 		// core logic
 		pipeline := hercules.NewPipeline(repository)
 		pipeline.SetFeaturesFromFlags()
+		pipeline.Range = selection.Range
 		var bar *progress.ProgressBar
 		if !disableStatus {
 			pipeline.OnProgress = func(commit, length int, action string) {
@@ -240,18 +354,25 @@ targets can be added using the --plugin system.`,
 				}
 			}
 		}
+		if progressFormat == "json" {
+			encoder := json.NewEncoder(os.Stderr)
+			pipeline.OnEvent = func(event hercules.ProgressEvent) {
+				if err := encoder.Encode(event); err != nil {
+					log.Printf("failed to write the progress event: %v", err)
+				}
+			}
+		}
 
-		var commits []*object.Commit
-		var err error
-		if commitsFile == "" {
+		if selection.File == "" && progressFormat == "bar" {
 			fmt.Fprint(os.Stderr, "git log...\r")
-			commits, err = pipeline.Commits(firstParent)
-		} else {
-			commits, err = hercules.LoadCommitsFromFile(commitsFile, repository)
 		}
+		commits, err := selection.listCommits(pipeline, repository)
 		if err != nil {
 			log.Fatalf("failed to list the commits: %v", err)
 		}
+		if len(commits) == 0 {
+			log.Fatal("there are no commits to analyse")
+		}
 		cmdlineFacts[hercules.ConfigPipelineCommits] = commits
 		dryRun, _ := cmdlineFacts[hercules.ConfigPipelineDryRun].(bool)
 		var deployed []hercules.LeafPipelineItem
@@ -267,8 +388,20 @@ targets can be added using the --plugin system.`,
 		if err != nil {
 			log.Fatal(err)
 		}
-		results, err := pipeline.Run(commits)
-		if err != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			// the second Ctrl-C kills the process
+			signal.Stop(interrupts)
+			cancel()
+		}()
+		results, err := pipeline.RunContext(ctx, commits)
+		signal.Stop(interrupts)
+		_, interrupted := err.(*hercules.InterruptedError)
+		if err != nil && !interrupted {
 			log.Fatalf("failed to run the pipeline: %v", err)
 		}
 		if !disableStatus {
@@ -278,39 +411,112 @@ targets can be added using the --plugin system.`,
 				fmt.Fprint(os.Stderr, "writing...\r")
 			}
 		}
-		if !protobuf {
-			printResults(uri, deployed, results)
-		} else {
-			protobufResults(uri, deployed, results)
+		switch format {
+		case "pb":
+			protobufResults(os.Stdout, uri, deployed, results)
+		case "json":
+			jsonResults(os.Stdout, uri, deployed, results)
+		case "openmetrics":
+			openMetricsResults(os.Stdout, uri, deployed, results)
+		default:
+			printResults(os.Stdout, uri, deployed, results)
+		}
+		if interrupted {
+			// the partial results are written, but the exit code must signal the interruption
+			log.Printf("the results are partial: %v", err)
+			os.Exit(130)
 		}
 	},
 }
 
 func printResults(
-	uri string, deployed []hercules.LeafPipelineItem,
+	writer io.Writer, uri string, deployed []hercules.LeafPipelineItem,
 	results map[hercules.LeafPipelineItem]interface{}) {
-	commonResult := results[nil].(*hercules.CommonAnalysisResult)
-
-	fmt.Println("hercules:")
-	fmt.Printf("  version: %d\n", hercules.BinaryVersion)
-	fmt.Println("  hash:", hercules.BinaryGitHash)
-	fmt.Println("  repository:", uri)
-	fmt.Println("  begin_unix_time:", commonResult.BeginTime)
-	fmt.Println("  end_unix_time:", commonResult.EndTime)
-	fmt.Println("  commits:", commonResult.CommitsNumber)
-	fmt.Println("  run_time:", commonResult.RunTime.Nanoseconds()/1e6)
+	printHeader(writer, resultsHeader(uri, results))
 
 	for _, item := range deployed {
-		result := results[item]
-		fmt.Printf("%s:\n", item.Name())
-		if err := item.Serialize(result, false, os.Stdout); err != nil {
+		result, exists := results[item]
+		if !exists {
+			// the analysis was interrupted and the item could not finalize
+			continue
+		}
+		fmt.Fprintf(writer, "%s:\n", item.Name())
+		if err := item.Serialize(result, hercules.YAMLFormat, writer); err != nil {
 			panic(err)
 		}
 	}
 }
 
+// resultsHeader returns the metadata of the analysis results with the current version.
+func resultsHeader(uri string, results map[hercules.LeafPipelineItem]interface{}) *pb.Metadata {
+	header := &pb.Metadata{
+		Version:    int32(hercules.BinaryVersion),
+		Hash:       hercules.BinaryGitHash,
+		Repository: uri,
+	}
+	return results[nil].(*hercules.CommonAnalysisResult).FillMetadata(header)
+}
+
+// printHeader writes the metadata as the "hercules" YAML block.
+func printHeader(writer io.Writer, header *pb.Metadata) {
+	fmt.Fprintln(writer, "hercules:")
+	fmt.Fprintf(writer, "  version: %d\n", header.Version)
+	fmt.Fprintln(writer, "  hash:", header.Hash)
+	fmt.Fprintln(writer, "  repository:", header.Repository)
+	fmt.Fprintln(writer, "  begin_unix_time:", header.BeginUnixTime)
+	fmt.Fprintln(writer, "  end_unix_time:", header.EndUnixTime)
+	fmt.Fprintln(writer, "  commits:", header.Commits)
+	fmt.Fprintln(writer, "  run_time:", header.RunTime)
+	if header.Signatures != "" {
+		fmt.Fprintln(writer, "  signatures:", header.Signatures)
+	}
+}
+
+// textHeader is the "hercules" block of the YAML and JSON results.
+type textHeader struct {
+	Version       int32  `yaml:"version" json:"version"`
+	Hash          string `yaml:"hash" json:"hash"`
+	Repository    string `yaml:"repository" json:"repository"`
+	BeginUnixTime int64  `yaml:"begin_unix_time" json:"begin_unix_time"`
+	EndUnixTime   int64  `yaml:"end_unix_time" json:"end_unix_time"`
+	Commits       int32  `yaml:"commits" json:"commits"`
+	RunTime       int64  `yaml:"run_time" json:"run_time"`
+	Signatures    string `yaml:"signatures" json:"signatures"`
+}
+
+// jsonHeader returns the metadata as the "hercules" JSON object.
+func jsonHeader(header *pb.Metadata) map[string]interface{} {
+	result := map[string]interface{}{
+		"version":         header.Version,
+		"hash":            header.Hash,
+		"repository":      header.Repository,
+		"begin_unix_time": header.BeginUnixTime,
+		"end_unix_time":   header.EndUnixTime,
+		"commits":         header.Commits,
+		"run_time":        header.RunTime,
+	}
+	if header.Signatures != "" {
+		result["signatures"] = header.Signatures
+	}
+	return result
+}
+
+// metadata converts the text header back to Protocol Buffers.
+func (header textHeader) metadata() *pb.Metadata {
+	return &pb.Metadata{
+		Version:       header.Version,
+		Hash:          header.Hash,
+		Repository:    header.Repository,
+		BeginUnixTime: header.BeginUnixTime,
+		EndUnixTime:   header.EndUnixTime,
+		Commits:       header.Commits,
+		RunTime:       header.RunTime,
+		Signatures:    header.Signatures,
+	}
+}
+
 func protobufResults(
-	uri string, deployed []hercules.LeafPipelineItem,
+	writer io.Writer, uri string, deployed []hercules.LeafPipelineItem,
 	results map[hercules.LeafPipelineItem]interface{}) {
 
 	header := pb.Metadata{
@@ -326,9 +532,13 @@ func protobufResults(
 	}
 
 	for _, item := range deployed {
-		result := results[item]
+		result, exists := results[item]
+		if !exists {
+			// the analysis was interrupted and the item could not finalize
+			continue
+		}
 		buffer := &bytes.Buffer{}
-		if err := item.Serialize(result, true, buffer); err != nil {
+		if err := item.Serialize(result, hercules.ProtobufFormat, buffer); err != nil {
 			panic(err)
 		}
 		message.Contents[item.Name()] = buffer.Bytes()
@@ -338,7 +548,32 @@ func protobufResults(
 	if err != nil {
 		panic(err)
 	}
-	os.Stdout.Write(serialized)
+	writer.Write(serialized)
+}
+
+// jsonResults writes the results as a single JSON object. The keys are the same as
+// in printResults().
+func jsonResults(
+	writer io.Writer, uri string, deployed []hercules.LeafPipelineItem,
+	results map[hercules.LeafPipelineItem]interface{}) {
+	message := map[string]interface{}{
+		"hercules": jsonHeader(resultsHeader(uri, results)),
+	}
+	for _, item := range deployed {
+		result, exists := results[item]
+		if !exists {
+			// the analysis was interrupted and the item could not finalize
+			continue
+		}
+		buffer := &bytes.Buffer{}
+		if err := item.Serialize(result, hercules.JSONFormat, buffer); err != nil {
+			panic(err)
+		}
+		message[item.Name()] = json.RawMessage(buffer.Bytes())
+	}
+	if err := json.NewEncoder(writer).Encode(message); err != nil {
+		panic(err)
+	}
 }
 
 // trimRightSpace removes the trailing whitespace characters.
@@ -473,34 +708,155 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-var cmdlineFacts map[string]interface{}
-var cmdlineDeployed map[string]*bool
+// parseRangeTime converts the value of --since or --until to time.Time.
+func parseRangeTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		result, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %s, expected YYYY-MM-DD or RFC3339", value)
+}
 
-func init() {
-	loadPlugins()
-	rootFlags := rootCmd.Flags()
-	rootFlags.String("commits", "", "Path to the text file with the "+
+// commitsSelection specifies which commits to analyse, see addCommitsFlags().
+type commitsSelection struct {
+	// File is the path to the text file with the commit hashes, see --commits.
+	File string
+	// FirstParent follows only the first parent of the merge commits.
+	FirstParent bool
+	// Range limits the commits by the dates and the revisions.
+	Range hercules.CommitsRange
+	// Refs are the references to traverse instead of HEAD, --all-refs is the last one.
+	Refs []string
+}
+
+// addCommitsFlags adds the command line flags which select the commits to analyse:
+// --commits, --first-parent, --since, --until, --from, --to, --ref and --all-refs.
+// parseCommitsFlags() reads them back.
+func addCommitsFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.String("commits", "", "Path to the text file with the "+
 		"commit history to follow instead of the default 'git log'. "+
 		"The format is the list of hashes, each hash on a "+
 		"separate line. The first hash is the root.")
-	err := rootCmd.MarkFlagFilename("commits")
+	err := cmd.MarkFlagFilename("commits")
 	if err != nil {
 		panic(err)
 	}
-	hercules.PathifyFlagValue(rootFlags.Lookup("commits"))
-	rootFlags.Bool("first-parent", false, "Follow only the first parent in the commit history - "+
+	hercules.PathifyFlagValue(flags.Lookup("commits"))
+	flags.Bool("first-parent", false, "Follow only the first parent in the commit history - "+
 		"\"git log --first-parent\".")
+	flags.String("since", "", "Analyse only the commits which were committed after this date - "+
+		"\"git log --since\". The format is YYYY-MM-DD or RFC3339.")
+	flags.String("until", "", "Analyse only the commits which were committed before this date - "+
+		"\"git log --until\". The format is YYYY-MM-DD or RFC3339.")
+	flags.String("from", "", "Exclude this revision and all its ancestors - "+
+		"\"git log <from>..\".")
+	flags.String("to", "", "Start the history traversal from this revision instead of HEAD - "+
+		"\"git log ..<to>\".")
+	flags.StringSlice("ref", []string{}, "Analyse the history of this branch, tag or "+
+		"other revision instead of HEAD. May be repeated.")
+	flags.String("all-refs", "", "Analyse the union of the histories of all the references "+
+		"which match the glob pattern, e.g. \"release-*\"; \"*\" selects all the references.")
+}
+
+// parseCommitsFlags reads the flags added by addCommitsFlags() and checks that they
+// can be combined.
+func parseCommitsFlags(flags *pflag.FlagSet) (commitsSelection, error) {
+	selection := commitsSelection{}
+	var err error
+	if selection.File, err = flags.GetString("commits"); err != nil {
+		panic(err)
+	}
+	if selection.FirstParent, err = flags.GetBool("first-parent"); err != nil {
+		panic(err)
+	}
+	if selection.Range.From, err = flags.GetString("from"); err != nil {
+		panic(err)
+	}
+	if selection.Range.To, err = flags.GetString("to"); err != nil {
+		panic(err)
+	}
+	for name, ptr := range map[string]*time.Time{
+		"since": &selection.Range.Since, "until": &selection.Range.Until} {
+		value, err := flags.GetString(name)
+		if err != nil {
+			panic(err)
+		}
+		if value == "" {
+			continue
+		}
+		*ptr, err = parseRangeTime(value)
+		if err != nil {
+			return selection, fmt.Errorf("invalid --%s: %v", name, err)
+		}
+	}
+	if selection.File != "" && selection.Range != (hercules.CommitsRange{}) {
+		return selection, errors.New("--commits cannot be combined with --since, --until, --from or --to")
+	}
+	if selection.Refs, err = flags.GetStringSlice("ref"); err != nil {
+		panic(err)
+	}
+	allRefs, err := flags.GetString("all-refs")
+	if err != nil {
+		panic(err)
+	}
+	if allRefs != "" {
+		selection.Refs = append(selection.Refs, allRefs)
+	}
+	if len(selection.Refs) > 0 && (selection.File != "" || selection.Range.To != "") {
+		return selection, errors.New("--ref and --all-refs cannot be combined with --commits or --to")
+	}
+	return selection, nil
+}
+
+// listCommits returns the selected commits of the repository. pipeline must be created for
+// the same repository and its Range must be already set to selection.Range.
+func (selection commitsSelection) listCommits(
+	pipeline *hercules.Pipeline, repository *git.Repository) ([]*object.Commit, error) {
+	if selection.File != "" {
+		return hercules.LoadCommitsFromFile(selection.File, repository)
+	}
+	if len(selection.Refs) > 0 {
+		return pipeline.CommitsFromRefs(selection.Refs, selection.FirstParent)
+	}
+	return pipeline.Commits(selection.FirstParent)
+}
+
+var cmdlineFacts map[string]interface{}
+var cmdlineDeployed map[string]*bool
+
+func init() {
+	loadPlugins()
+	rootFlags := rootCmd.Flags()
+	addCommitsFlags(rootCmd)
 	rootFlags.Bool("pb", false, "The output format will be Protocol Buffers instead of YAML.")
+	rootFlags.Bool("json", false, "The output format will be JSON instead of YAML.")
+	rootFlags.String("format", "yaml", "The output format: yaml, pb, json or openmetrics. "+
+		"The latter exports the numeric results of Burndown, Devs and FileHistory.")
 	rootFlags.Bool("quiet", !terminal.IsTerminal(int(os.Stdin.Fd())),
 		"Do not print status updates to stderr.")
+	rootFlags.String("progress", "bar", "The format of the status updates in stderr: "+
+		"\"bar\" or \"json\" (newline-delimited JSON events, ignores --quiet).")
 	rootFlags.Bool("profile", false, "Collect the profile to hercules.pprof.")
 	rootFlags.String("ssh-identity", "", "Path to SSH identity file (e.g., ~/.ssh/id_rsa) to clone from an SSH remote.")
-	err = rootCmd.MarkFlagFilename("ssh-identity")
+	err := rootCmd.MarkFlagFilename("ssh-identity")
 	if err != nil {
 		panic(err)
 	}
 	hercules.PathifyFlagValue(rootFlags.Lookup("ssh-identity"))
+	rootFlags.String("config", "", "Path to the YAML file with the analyses to run (\"leaves\"), "+
+		"the features (\"features\") and the configuration options (\"facts\"). "+
+		"The command line flags take precedence.")
+	err = rootCmd.MarkFlagFilename("config")
+	if err != nil {
+		panic(err)
+	}
+	hercules.PathifyFlagValue(rootFlags.Lookup("config"))
 	cmdlineFacts, cmdlineDeployed = hercules.Registry.AddFlags(rootFlags)
+	// batch.go init() may run before loadPlugins(), so the plugin leaves are added here
+	batchFacts, batchDeployed = hercules.Registry.AddFlags(batchCmd.Flags())
 	rootCmd.SetUsageFunc(formatUsage)
 	rootCmd.AddCommand(versionCmd)
 	versionCmd.SetUsageFunc(versionCmd.UsageFunc())
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/src-d/go-billy.v4/osfs"
 	"gopkg.in/src-d/go-git.v4"
@@ -48,3 +49,66 @@ func TestLoadRepository(t *testing.T) {
 	assert.Panics(t, func() { loadRepository(filepath.Dir(filename), "", true, "") })
 	assert.Panics(t, func() { loadRepository("/xxx", "", true, "") })
 }
+
+func TestLoadRepositoryCache(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "hercules-")
+	assert.Nil(t, err)
+	if err != nil {
+		assert.FailNow(t, "ioutil.TempDir")
+	}
+	defer os.RemoveAll(tempdir)
+	cachePath := filepath.Join(tempdir, "cache")
+	repo := loadRepository("https://github.com/src-d/hercules", cachePath, true, "")
+	head1, err := repo.Head()
+	assert.Nil(t, err)
+	marker := filepath.Join(cachePath, "marker")
+	assert.Nil(t, ioutil.WriteFile(marker, nil, 0666))
+
+	// the same remote - the cache is reused
+	repo = loadRepository("https://github.com/src-d/hercules", cachePath, true, "")
+	head2, err := repo.Head()
+	assert.Nil(t, err)
+	assert.Equal(t, head1.Hash(), head2.Hash())
+	_, err = os.Stat(marker)
+	assert.Nil(t, err)
+
+	// a different remote - the cache is wiped
+	repo = loadRepository("https://github.com/src-d/hercules.git", cachePath, true, "")
+	assert.NotNil(t, repo)
+	_, err = os.Stat(marker)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseCommitsFlags(t *testing.T) {
+	parse := func(args ...string) ([]string, commitsSelection, error) {
+		cmd := &cobra.Command{}
+		addCommitsFlags(cmd)
+		assert.Nil(t, cmd.ParseFlags(args))
+		selection, err := parseCommitsFlags(cmd.Flags())
+		return cmd.Flags().Args(), selection, err
+	}
+	// the pattern is a separate value and the repository stays positional
+	args, selection, err := parse("--all-refs", "release-*", "repo")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"repo"}, args)
+	assert.Equal(t, []string{"release-*"}, selection.Refs)
+	args, selection, err = parse("--ref", "v1", "--ref", "v2", "--all-refs", "*", "--first-parent",
+		"--since", "2018-01-01", "--from", "v0", "repo", "cache")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"repo", "cache"}, args)
+	assert.Equal(t, []string{"v1", "v2", "*"}, selection.Refs)
+	assert.True(t, selection.FirstParent)
+	assert.Equal(t, "v0", selection.Range.From)
+	assert.Equal(t, 2018, selection.Range.Since.Year())
+	assert.True(t, selection.Range.Until.IsZero())
+
+	for _, flags := range [][]string{
+		{"--since", "yesterday"},
+		{"--commits", "commits.txt", "--until", "2018-01-01"},
+		{"--commits", "commits.txt", "--ref", "v1"},
+		{"--to", "v1", "--all-refs", "*"},
+	} {
+		_, _, err = parse(append(flags, "repo")...)
+		assert.NotNil(t, err, flags)
+	}
+}
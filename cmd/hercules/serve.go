@@ -0,0 +1,601 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/hercules.v9"
+)
+
+// jobRequest is the body of POST /jobs.
+type jobRequest struct {
+	// Repository is the path or the URL of the analysed repository.
+	Repository string `json:"repository"`
+	// Analyses are the flags of the leaves to run, e.g. "burndown".
+	Analyses []string `json:"analyses"`
+	// Facts are the values of the configuration options, e.g. {"Burndown.Granularity": 30}.
+	Facts map[string]interface{} `json:"facts"`
+	// Features enable the items which depend on them, e.g. "uast".
+	Features []string `json:"features"`
+	// Format is the format of the results: "yaml" (default), "pb", "json" or "openmetrics".
+	Format string `json:"format"`
+	// FirstParent follows only the first parent in the commit history.
+	FirstParent bool `json:"first_parent"`
+}
+
+const (
+	jobQueued   = "queued"
+	jobRunning  = "running"
+	jobDone     = "done"
+	jobFailed   = "failed"
+	jobCanceled = "canceled"
+)
+
+// resultContentTypes maps the job result formats to HTTP Content-Type-s.
+var resultContentTypes = map[string]string{
+	"yaml":        "application/x-yaml",
+	"pb":          "application/octet-stream",
+	"json":        "application/json",
+	"openmetrics": openMetricsContentType,
+}
+
+// jobStatus is the body of GET /jobs/{id}.
+type jobStatus struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	Repository string    `json:"repository"`
+	Created    time.Time `json:"created"`
+	// Step, Total and Action are reported by Pipeline.OnProgress.
+	Step   int    `json:"step"`
+	Total  int    `json:"total"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// serveJob is the analysis job which is executed by jobServer.
+type serveJob struct {
+	request jobRequest
+	facts   map[string]interface{}
+	// leaves are the names of the deployed LeafPipelineItem-s.
+	leaves []string
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock   sync.Mutex
+	status jobStatus
+	result []byte
+	// metrics are exported at GET /metrics after the job is done.
+	metrics []hercules.MetricFamily
+}
+
+// Status returns the copy of the current job status.
+func (job *serveJob) Status() jobStatus {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.status
+}
+
+// start switches the job to running unless it was canceled while it was queued.
+func (job *serveJob) start() bool {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	if job.status.Status != jobQueued {
+		return false
+	}
+	job.status.Status = jobRunning
+	return true
+}
+
+func (job *serveJob) setProgress(step, total int, action string) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	job.status.Step = step
+	job.status.Total = total
+	job.status.Action = action
+}
+
+// finish records the outcome of the job.
+func (job *serveJob) finish(result []byte, metrics []hercules.MetricFamily, err error) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	switch {
+	case job.ctx.Err() != nil:
+		job.status.Status = jobCanceled
+	case err != nil:
+		job.status.Status = jobFailed
+		job.status.Error = err.Error()
+	default:
+		job.status.Status = jobDone
+		job.result = result
+		job.metrics = metrics
+	}
+	job.status.Action = ""
+}
+
+// jobServer accepts the analysis jobs over HTTP and executes them in a bounded queue.
+type jobServer struct {
+	// cacheDir is the directory where to keep the clones of the remote repositories.
+	cacheDir string
+	// keepJobs is the maximum number of the finished jobs to remember.
+	keepJobs int
+	queue    chan *serveJob
+	mux      *http.ServeMux
+
+	lock sync.Mutex
+	jobs map[string]*serveJob
+	// finished are the IDs of the finished jobs in the order of completion.
+	finished   []string
+	lastID     int
+	cacheLocks map[string]*sync.Mutex
+}
+
+// newJobServer creates a jobServer which runs at most `workers` jobs at the same time and
+// rejects new jobs if there are `queueSize` jobs waiting. Only `keepJobs` most recently
+// finished jobs are remembered, the older ones are forgotten together with their results.
+func newJobServer(workers, queueSize, keepJobs int, cacheDir string) *jobServer {
+	server := &jobServer{
+		cacheDir:   cacheDir,
+		keepJobs:   keepJobs,
+		queue:      make(chan *serveJob, queueSize),
+		mux:        http.NewServeMux(),
+		jobs:       map[string]*serveJob{},
+		cacheLocks: map[string]*sync.Mutex{},
+	}
+	server.mux.HandleFunc("/analyses", server.handleAnalyses)
+	server.mux.HandleFunc("/jobs", server.handleJobs)
+	server.mux.HandleFunc("/jobs/", server.handleJob)
+	server.mux.HandleFunc("/metrics", server.handleMetrics)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range server.queue {
+				server.run(job)
+			}
+		}()
+	}
+	return server
+}
+
+func (server *jobServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	server.mux.ServeHTTP(writer, request)
+}
+
+func writeJSON(writer http.ResponseWriter, code int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	json.NewEncoder(writer).Encode(value)
+}
+
+func writeError(writer http.ResponseWriter, code int, err error) {
+	writeJSON(writer, code, map[string]string{"error": err.Error()})
+}
+
+// handleAnalyses lists the available analyses and their configuration options.
+func (server *jobServer) handleAnalyses(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", request.Method))
+		return
+	}
+	type option struct {
+		Name        string      `json:"name"`
+		Type        string      `json:"type"`
+		Default     interface{} `json:"default"`
+		Description string      `json:"description"`
+	}
+	type analysis struct {
+		Flag    string   `json:"flag"`
+		Name    string   `json:"name"`
+		Options []option `json:"options"`
+	}
+	var analyses []analysis
+	for _, leaf := range hercules.Registry.GetLeaves() {
+		item := analysis{Flag: leaf.Flag(), Name: leaf.Name(), Options: []option{}}
+		for _, opt := range leaf.ListConfigurationOptions() {
+			item.Options = append(item.Options, option{
+				Name: opt.Name, Type: opt.Type.String(), Default: opt.Default,
+				Description: opt.Description,
+			})
+		}
+		analyses = append(analyses, item)
+	}
+	writeJSON(writer, http.StatusOK, analyses)
+}
+
+// handleJobs lists the jobs (GET) or submits a new job (POST).
+func (server *jobServer) handleJobs(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+		server.lock.Lock()
+		statuses := make([]jobStatus, 0, len(server.jobs))
+		for _, job := range server.jobs {
+			statuses = append(statuses, job.Status())
+		}
+		server.lock.Unlock()
+		sort.Slice(statuses, func(i, j int) bool {
+			a, _ := strconv.Atoi(statuses[i].ID)
+			b, _ := strconv.Atoi(statuses[j].ID)
+			return a < b
+		})
+		writeJSON(writer, http.StatusOK, statuses)
+	case http.MethodPost:
+		var jr jobRequest
+		if err := json.NewDecoder(request.Body).Decode(&jr); err != nil {
+			writeError(writer, http.StatusBadRequest, fmt.Errorf("invalid job: %v", err))
+			return
+		}
+		job, err := newServeJob(jr)
+		if err != nil {
+			writeError(writer, http.StatusBadRequest, err)
+			return
+		}
+		server.lock.Lock()
+		server.lastID++
+		job.status.ID = strconv.Itoa(server.lastID)
+		select {
+		case server.queue <- job:
+			server.jobs[job.status.ID] = job
+			server.lock.Unlock()
+			writeJSON(writer, http.StatusAccepted, job.Status())
+		default:
+			server.lock.Unlock()
+			job.cancel()
+			writeError(writer, http.StatusServiceUnavailable, fmt.Errorf("the queue is full"))
+		}
+	default:
+		writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", request.Method))
+	}
+}
+
+// handleJob serves GET /jobs/{id}, GET /jobs/{id}/result and DELETE /jobs/{id}.
+func (server *jobServer) handleJob(writer http.ResponseWriter, request *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(request.URL.Path, "/jobs/"), "/")
+	server.lock.Lock()
+	job := server.jobs[parts[0]]
+	server.lock.Unlock()
+	if job == nil || len(parts) > 2 || (len(parts) == 2 && parts[1] != "result") {
+		writeError(writer, http.StatusNotFound, fmt.Errorf("%s was not found", request.URL.Path))
+		return
+	}
+	if len(parts) == 2 {
+		if request.Method != http.MethodGet {
+			writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", request.Method))
+			return
+		}
+		job.lock.Lock()
+		status, result := job.status, job.result
+		job.lock.Unlock()
+		if status.Status != jobDone {
+			writeJSON(writer, http.StatusConflict, status)
+			return
+		}
+		writer.Header().Set("Content-Type", resultContentTypes[job.request.Format])
+		writer.Write(result)
+		return
+	}
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, job.Status())
+	case http.MethodDelete:
+		// cancel the unfinished job or forget the finished one
+		job.lock.Lock()
+		finished := false
+		switch job.status.Status {
+		case jobQueued:
+			job.status.Status = jobCanceled
+		case jobRunning:
+		default:
+			finished = true
+		}
+		job.lock.Unlock()
+		job.cancel()
+		if finished {
+			server.lock.Lock()
+			delete(server.jobs, parts[0])
+			server.lock.Unlock()
+		}
+		writeJSON(writer, http.StatusOK, job.Status())
+	default:
+		writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", request.Method))
+	}
+}
+
+// handleMetrics exports the metrics of the finished jobs in the OpenMetrics text format.
+// If several jobs analysed the same repository, the latest job wins for each metric.
+func (server *jobServer) handleMetrics(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", request.Method))
+		return
+	}
+	server.lock.Lock()
+	jobs := make([]*serveJob, 0, len(server.jobs))
+	for _, job := range server.jobs {
+		jobs = append(jobs, job)
+	}
+	server.lock.Unlock()
+	sort.Slice(jobs, func(i, j int) bool {
+		a, _ := strconv.Atoi(jobs[i].status.ID)
+		b, _ := strconv.Atoi(jobs[j].status.ID)
+		return a < b
+	})
+	type metricKey struct {
+		repository string
+		name       string
+	}
+	var order []metricKey
+	latest := map[metricKey]hercules.MetricFamily{}
+	for _, job := range jobs {
+		job.lock.Lock()
+		metrics := job.metrics
+		job.lock.Unlock()
+		for _, family := range metrics {
+			key := metricKey{job.request.Repository, family.Name}
+			if _, exists := latest[key]; !exists {
+				order = append(order, key)
+			}
+			latest[key] = family
+		}
+	}
+	families := make([]hercules.MetricFamily, 0, len(order))
+	for _, key := range order {
+		families = append(families, latest[key])
+	}
+	writer.Header().Set("Content-Type", openMetricsContentType)
+	hercules.WriteOpenMetrics(writer, families)
+}
+
+// newServeJob validates the request and creates the corresponding job.
+func newServeJob(request jobRequest) (*serveJob, error) {
+	if request.Repository == "" {
+		return nil, fmt.Errorf("the repository is not specified")
+	}
+	if request.Format == "" {
+		request.Format = "yaml"
+	}
+	if _, exists := resultContentTypes[request.Format]; !exists {
+		return nil, fmt.Errorf("unknown format %s, must be one of yaml, pb, json, openmetrics",
+			request.Format)
+	}
+	if len(request.Analyses) == 0 {
+		return nil, fmt.Errorf("no analyses were specified")
+	}
+	leaves := map[string]string{}
+	for _, leaf := range hercules.Registry.GetLeaves() {
+		leaves[leaf.Flag()] = leaf.Name()
+	}
+	job := &serveJob{request: request}
+	for _, flag := range request.Analyses {
+		name, exists := leaves[flag]
+		if !exists {
+			return nil, fmt.Errorf("unknown analysis %s", flag)
+		}
+		job.leaves = append(job.leaves, name)
+	}
+	features := hercules.Registry.GetFeaturedItems()
+	for _, feature := range request.Features {
+		if _, exists := features[feature]; !exists {
+			return nil, fmt.Errorf("unknown feature %s", feature)
+		}
+	}
+	var err error
+	job.facts, err = convertFacts(request.Facts)
+	if err != nil {
+		return nil, err
+	}
+	job.ctx, job.cancel = context.WithCancel(context.Background())
+	job.status = jobStatus{
+		Status: jobQueued, Repository: request.Repository, Created: time.Now(),
+	}
+	return job, nil
+}
+
+// convertFacts casts the JSON values of the facts to the types of the configuration options
+// and fills the missing facts with the default values, the same as the command line flags do.
+// The options of Pipeline itself are controlled by the server and cannot be set.
+func convertFacts(raw map[string]interface{}) (map[string]interface{}, error) {
+	for name := range raw {
+		if isPipelineOption(name) {
+			return nil, &hercules.UnknownConfigurationOptionError{Name: name}
+		}
+	}
+	facts, err := hercules.Registry.ConvertFacts(raw)
+	if err != nil {
+		return nil, err
+	}
+	for name, opt := range hercules.Registry.GetConfigurationOptions() {
+		if _, exists := facts[name]; !exists && !isPipelineOption(name) {
+			facts[name] = opt.Default
+		}
+	}
+	return facts, nil
+}
+
+func isPipelineOption(name string) bool {
+	return strings.HasPrefix(name, "Pipeline.")
+}
+
+// cacheLock returns the mutex which guards the specified clone cache directory.
+func (server *jobServer) cacheLock(cachePath string) *sync.Mutex {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	lock := server.cacheLocks[cachePath]
+	if lock == nil {
+		lock = &sync.Mutex{}
+		server.cacheLocks[cachePath] = lock
+	}
+	return lock
+}
+
+// runPipeline deploys the specified leaves and runs the pipeline over the commits from HEAD.
+// `facts` are modified in-place.
+func runPipeline(
+	ctx context.Context, pipeline *hercules.Pipeline, leaves []string,
+	facts map[string]interface{}, firstParent bool) (
+	[]hercules.LeafPipelineItem, map[hercules.LeafPipelineItem]interface{}, error) {
+	commits, err := pipeline.Commits(firstParent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the commits: %v", err)
+	}
+	if len(commits) == 0 {
+		return nil, nil, fmt.Errorf("there are no commits to analyse")
+	}
+	facts[hercules.ConfigPipelineCommits] = commits
+	var deployed []hercules.LeafPipelineItem
+	for _, name := range leaves {
+		item := pipeline.DeployItem(hercules.Registry.Summon(name)[0])
+		deployed = append(deployed, item.(hercules.LeafPipelineItem))
+	}
+	if err = pipeline.Initialize(facts); err != nil {
+		return nil, nil, err
+	}
+	results, err := pipeline.RunContext(ctx, commits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deployed, results, nil
+}
+
+// run executes the job. loadRepository() and the items panic on errors, so the panics are
+// converted to the job failures.
+func (server *jobServer) run(job *serveJob) {
+	defer server.retire(job)
+	if !job.start() {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			job.finish(nil, nil, fmt.Errorf("%v", r))
+		}
+	}()
+	uri := job.request.Repository
+	cachePath := cacheRepositoryPath(server.cacheDir, uri)
+	if cachePath != "" {
+		lock := server.cacheLock(cachePath)
+		lock.Lock()
+		defer lock.Unlock()
+	}
+	repository := loadRepository(uri, cachePath, true, "")
+	pipeline := hercules.NewPipeline(repository)
+	for _, feature := range job.request.Features {
+		pipeline.SetFeature(feature)
+	}
+	pipeline.OnProgress = job.setProgress
+	deployed, results, err := runPipeline(
+		job.ctx, pipeline, job.leaves, job.facts, job.request.FirstParent)
+	if err != nil {
+		job.finish(nil, nil, err)
+		return
+	}
+	buffer := &bytes.Buffer{}
+	switch job.request.Format {
+	case "pb":
+		protobufResults(buffer, uri, deployed, results)
+	case "json":
+		jsonResults(buffer, uri, deployed, results)
+	case "openmetrics":
+		openMetricsResults(buffer, uri, deployed, results)
+	default:
+		printResults(buffer, uri, deployed, results)
+	}
+	job.finish(buffer.Bytes(), openMetricsFamilies(uri, deployed, results), nil)
+}
+
+// retire records that the job is finished and forgets the oldest finished jobs beyond
+// server.keepJobs.
+func (server *jobServer) retire(job *serveJob) {
+	id := job.Status().ID
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	// the jobs which were deleted explicitly are already forgotten
+	finished := make([]string, 0, len(server.finished)+1)
+	for _, other := range append(server.finished, id) {
+		if _, exists := server.jobs[other]; exists {
+			finished = append(finished, other)
+		}
+	}
+	for len(finished) > server.keepJobs {
+		delete(server.jobs, finished[0])
+		finished = finished[1:]
+	}
+	server.finished = finished
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the HTTP server which executes the analysis jobs.",
+	Long: `Start the HTTP server with the JSON API to run the analyses. The jobs are executed in
+a bounded queue. The endpoints are:
+
+GET /analyses - list the available analyses and their options.
+POST /jobs - submit a new job, e.g.
+    {"repository": "https://github.com/src-d/go-git", "analyses": ["burndown"],
+     "facts": {"Burndown.Granularity": 30}, "features": [], "format": "yaml", "first_parent": false}
+  The format is one of yaml, pb, json and openmetrics. Responds with the job status.
+GET /jobs - list the statuses of all the jobs.
+GET /jobs/{id} - get the job status including the progress.
+GET /jobs/{id}/result - get the serialized results of the finished job.
+DELETE /jobs/{id} - cancel the unfinished job or forget the finished one.
+  Only --keep-jobs most recently finished jobs are remembered anyway.
+GET /metrics - export the results of the finished jobs in the OpenMetrics text format.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		address, err := flags.GetString("address")
+		if err != nil {
+			panic(err)
+		}
+		workers, err := flags.GetInt("jobs")
+		if err != nil {
+			panic(err)
+		}
+		queueSize, err := flags.GetInt("queue")
+		if err != nil {
+			panic(err)
+		}
+		keepJobs, err := flags.GetInt("keep-jobs")
+		if err != nil {
+			panic(err)
+		}
+		cacheDir, err := flags.GetString("cache")
+		if err != nil {
+			panic(err)
+		}
+		if workers < 1 {
+			log.Fatalf("--jobs must be positive, got %d", workers)
+		}
+		if keepJobs < 1 {
+			log.Fatalf("--keep-jobs must be positive, got %d", keepJobs)
+		}
+		server := newJobServer(workers, queueSize, keepJobs, cacheDir)
+		log.Printf("listening on %s", address)
+		log.Fatal(http.ListenAndServe(address, server))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.SetUsageFunc(serveCmd.UsageFunc())
+	serveFlags := serveCmd.Flags()
+	serveFlags.String("address", "localhost:8080", "Address to listen on.")
+	serveFlags.Int("jobs", 1, "Maximum number of concurrently running jobs.")
+	serveFlags.Int("queue", 100, "Maximum number of waiting jobs, the new jobs are rejected "+
+		"with 503 if it is exceeded.")
+	serveFlags.Int("keep-jobs", 100, "Maximum number of finished jobs to remember, the oldest "+
+		"are forgotten together with their results.")
+	serveFlags.String("cache", "", "Directory where to keep the clones of the remote repositories "+
+		"between the jobs. Empty means clone to memory every time.")
+	err := serveCmd.MarkFlagFilename("cache")
+	if err != nil {
+		panic(err)
+	}
+	hercules.PathifyFlagValue(serveFlags.Lookup("cache"))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRequest(server *jobServer, method, url, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, url, strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestServeAnalyses(t *testing.T) {
+	server := newJobServer(0, 1, 1, "")
+	response := serveRequest(server, http.MethodGet, "/analyses", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	var analyses []struct {
+		Flag string `json:"flag"`
+	}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &analyses))
+	flags := map[string]bool{}
+	for _, analysis := range analyses {
+		flags[analysis.Flag] = true
+	}
+	assert.True(t, flags["burndown"])
+	assert.True(t, flags["devs"])
+}
+
+func TestServeInvalidJobs(t *testing.T) {
+	server := newJobServer(0, 1, 1, "")
+	for _, body := range []string{
+		"{",
+		`{"analyses": ["devs"]}`,
+		`{"repository": "."}`,
+		`{"repository": ".", "analyses": ["xxx"]}`,
+		`{"repository": ".", "analyses": ["devs"], "format": "xml"}`,
+		`{"repository": ".", "analyses": ["devs"], "features": ["xxx"]}`,
+		`{"repository": ".", "analyses": ["devs"], "facts": {"xxx": 1}}`,
+		`{"repository": ".", "analyses": ["devs"], "facts": {"Pipeline.DryRun": true}}`,
+		`{"repository": ".", "analyses": ["burndown"], "facts": {"Burndown.Granularity": "30"}}`,
+		`{"repository": ".", "analyses": ["burndown"], "facts": {"Burndown.Granularity": 30.5}}`,
+	} {
+		response := serveRequest(server, http.MethodPost, "/jobs", body)
+		assert.Equal(t, http.StatusBadRequest, response.Code, body)
+	}
+	response := serveRequest(server, http.MethodGet, "/jobs/1", "")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestServeQueue(t *testing.T) {
+	// no workers - the jobs are never started
+	server := newJobServer(0, 1, 1, "")
+	body := `{"repository": ".", "analyses": ["burndown"], "facts": {"Burndown.Granularity": 30}}`
+	response := serveRequest(server, http.MethodPost, "/jobs", body)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	var status jobStatus
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &status))
+	assert.Equal(t, "1", status.ID)
+	assert.Equal(t, jobQueued, status.Status)
+	response = serveRequest(server, http.MethodPost, "/jobs", body)
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	response = serveRequest(server, http.MethodGet, "/jobs/1/result", "")
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = serveRequest(server, http.MethodDelete, "/jobs/1", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &status))
+	assert.Equal(t, jobCanceled, status.Status)
+	response = serveRequest(server, http.MethodGet, "/jobs", "")
+	var statuses []jobStatus
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &statuses))
+	assert.Len(t, statuses, 1)
+	// the canceled job is forgotten
+	serveRequest(server, http.MethodDelete, "/jobs/1", "")
+	response = serveRequest(server, http.MethodGet, "/jobs/1", "")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestServeKeepJobs(t *testing.T) {
+	// no workers - the jobs are run manually
+	server := newJobServer(0, 4, 2, "")
+	body := `{"repository": ".", "analyses": ["burndown"]}`
+	for i := 0; i < 3; i++ {
+		response := serveRequest(server, http.MethodPost, "/jobs", body)
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		// the canceled job finishes without running
+		response = serveRequest(server, http.MethodDelete, "/jobs/"+strconv.Itoa(i+1), "")
+		assert.Equal(t, http.StatusOK, response.Code)
+	}
+	listJobs := func() []string {
+		response := serveRequest(server, http.MethodGet, "/jobs", "")
+		var statuses []jobStatus
+		assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &statuses))
+		ids := []string{}
+		for _, status := range statuses {
+			ids = append(ids, status.ID)
+		}
+		return ids
+	}
+	for _, id := range []string{"2", "1", "3"} {
+		server.run(server.jobs[id])
+	}
+	// the job which finished first is forgotten
+	assert.Equal(t, []string{"1", "3"}, listJobs())
+	response := serveRequest(server, http.MethodGet, "/jobs/2", "")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	// the deleted job does not count
+	serveRequest(server, http.MethodDelete, "/jobs/1", "")
+	serveRequest(server, http.MethodPost, "/jobs", body)
+	serveRequest(server, http.MethodDelete, "/jobs/4", "")
+	server.run(server.jobs["4"])
+	assert.Equal(t, []string{"3", "4"}, listJobs())
+}
+
+func TestServeRun(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	sivafile := filepath.Join(filepath.Dir(filename), "test_data", "hercules.siva")
+	server := newJobServer(1, 1, 1, "")
+	body, _ := json.Marshal(jobRequest{
+		Repository: sivafile, Analyses: []string{"devs"}, Format: "json",
+		Facts: map[string]interface{}{"Devs.ConsiderEmptyCommits": true},
+	})
+	response := serveRequest(server, http.MethodPost, "/jobs", string(body))
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	var status jobStatus
+	for start := time.Now(); time.Since(start) < time.Minute; time.Sleep(100 * time.Millisecond) {
+		response = serveRequest(server, http.MethodGet, "/jobs/1", "")
+		assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &status))
+		if status.Status != jobQueued && status.Status != jobRunning {
+			break
+		}
+	}
+	assert.Equal(t, jobDone, status.Status, status.Error)
+	assert.True(t, status.Total > 0)
+	assert.Equal(t, status.Total, status.Step)
+	response = serveRequest(server, http.MethodGet, "/jobs/1/result", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	var result map[string]interface{}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Contains(t, result, "hercules")
+	assert.Contains(t, result, "Devs")
+	response = serveRequest(server, http.MethodGet, "/metrics", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, openMetricsContentType, response.Header().Get("Content-Type"))
+	metrics := response.Body.String()
+	assert.Contains(t, metrics, "# TYPE hercules_devs_commits counter\n")
+	assert.Contains(t, metrics, "hercules_commits{repository=\""+sivafile+"\"}")
+	assert.True(t, strings.HasSuffix(metrics, "# EOF\n"))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
@@ -193,10 +194,14 @@ func (churn *ChurnAnalysis) Finalize() interface{} {
 	return result
 }
 
-func (churn *ChurnAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+func (churn *ChurnAnalysis) Serialize(
+	result interface{}, format hercules.SerializationFormat, writer io.Writer) error {
 	burndownResult := result.(ChurnAnalysisResult)
-	if binary {
+	switch format {
+	case hercules.ProtobufFormat:
 		return churn.serializeBinary(&burndownResult, writer)
+	case hercules.JSONFormat:
+		return json.NewEncoder(writer).Encode(burndownResult)
 	}
 	churn.serializeText(&burndownResult, writer)
 	return nil
@@ -1,6 +1,8 @@
 package hercules
 
 import (
+	"io"
+
 	"github.com/spf13/pflag"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
@@ -26,6 +28,8 @@ const (
 	FloatConfigurationOption = core.FloatConfigurationOption
 	// StringsConfigurationOption reflects the array of strings value type.
 	StringsConfigurationOption = core.StringsConfigurationOption
+	// PathConfigurationOption reflects the file system path value type.
+	PathConfigurationOption = core.PathConfigurationOption
 	// MessageFinalize is the status text reported before calling LeafPipelineItem.Finalize()-s.
 	MessageFinalize = core.MessageFinalize
 )
@@ -39,12 +43,60 @@ type PipelineItem = core.PipelineItem
 // FeaturedPipelineItem enables switching the automatic insertion of pipeline items on or off.
 type FeaturedPipelineItem = core.FeaturedPipelineItem
 
+// SerializationFormat selects the encoding of the analysis results in LeafPipelineItem.Serialize().
+type SerializationFormat = core.SerializationFormat
+
+const (
+	// YAMLFormat is the human-readable YAML output.
+	YAMLFormat = core.YAMLFormat
+	// ProtobufFormat is the compact binary Protocol Buffers output.
+	ProtobufFormat = core.ProtobufFormat
+	// JSONFormat is the JSON output which can be consumed with standard parsers.
+	JSONFormat = core.JSONFormat
+)
+
 // LeafPipelineItem corresponds to the top level pipeline items which produce the end results.
 type LeafPipelineItem = core.LeafPipelineItem
 
 // ResultMergeablePipelineItem specifies the methods to combine several analysis results together.
 type ResultMergeablePipelineItem = core.ResultMergeablePipelineItem
 
+// TextDeserializablePipelineItem is the interface for ResultMergeablePipelineItem-s which are able
+// to read back their YAML and JSON output.
+type TextDeserializablePipelineItem = core.TextDeserializablePipelineItem
+
+// SequentialPipelineItem is the interface for pipeline items which must not Consume() commits
+// concurrently even if Pipeline.Workers allows that.
+type SequentialPipelineItem = core.SequentialPipelineItem
+
+// CheckpointablePipelineItem is the interface to allow pipeline items to save their intermediate
+// state on disk and to restore it later, so that Pipeline.Run() is able to resume after a crash.
+type CheckpointablePipelineItem = core.CheckpointablePipelineItem
+
+// MetricsPipelineItem is the interface for LeafPipelineItem-s which are able to export their
+// results as OpenMetrics.
+type MetricsPipelineItem = core.MetricsPipelineItem
+
+// MetricType is the OpenMetrics type of a MetricFamily.
+type MetricType = core.MetricType
+
+const (
+	// GaugeMetric is the value which can go up and down, e.g. the number of surviving lines.
+	GaugeMetric = core.GaugeMetric
+	// CounterMetric is the value which only grows, e.g. the number of commits.
+	CounterMetric = core.CounterMetric
+)
+
+// Metric is a single sample of a MetricFamily.
+type Metric = core.Metric
+
+// MetricFamily is the group of samples with the same name, help and type.
+type MetricFamily = core.MetricFamily
+
+// NoopCheckpointer provides empty SaveState() and LoadState() methods suitable for
+// PipelineItem-s which do not carry any state between commits.
+type NoopCheckpointer = core.NoopCheckpointer
+
 // CommonAnalysisResult holds the information which is always extracted at Pipeline.Run().
 type CommonAnalysisResult = core.CommonAnalysisResult
 
@@ -63,6 +115,61 @@ func MetadataToCommonAnalysisResult(meta *core.Metadata) *CommonAnalysisResult {
 // See the extended example of how a Pipeline works in doc.go
 type Pipeline = core.Pipeline
 
+// ProgressEventType is the kind of a ProgressEvent.
+type ProgressEventType = core.ProgressEventType
+
+const (
+	// ProgressCommitStarted is emitted before the commit is passed to the items.
+	ProgressCommitStarted = core.ProgressCommitStarted
+	// ProgressCommitFinished is emitted after all the items Consume()-d the commit.
+	ProgressCommitFinished = core.ProgressCommitFinished
+	// ProgressItemConsumed is emitted after each PipelineItem.Consume().
+	ProgressItemConsumed = core.ProgressItemConsumed
+	// ProgressFork is emitted after the branch is cloned.
+	ProgressFork = core.ProgressFork
+	// ProgressMerge is emitted after the branches are merged.
+	ProgressMerge = core.ProgressMerge
+	// ProgressHibernate is emitted after the items of the branches are hibernated.
+	ProgressHibernate = core.ProgressHibernate
+	// ProgressBoot is emitted after the items of the branches are booted.
+	ProgressBoot = core.ProgressBoot
+	// ProgressFinalize is emitted before calling LeafPipelineItem.Finalize()-s.
+	ProgressFinalize = core.ProgressFinalize
+	// ProgressItemFinalized is emitted after each LeafPipelineItem.Finalize().
+	ProgressItemFinalized = core.ProgressItemFinalized
+	// ProgressDone is emitted when the run ends.
+	ProgressDone = core.ProgressDone
+)
+
+// ProgressEvent describes a single step of Pipeline.Run(). It is delivered to Pipeline.OnEvent.
+type ProgressEvent = core.ProgressEvent
+
+// CommitsRange selects the part of the history which Pipeline.Commits() returns.
+type CommitsRange = core.CommitsRange
+
+// InterruptedError is returned by Pipeline.RunContext() when the context is done before all
+// the commits are analysed. The results returned together with it are partial.
+type InterruptedError = core.InterruptedError
+
+// UnknownConfigurationOptionError is returned when the facts contain a name which does not
+// belong to any ConfigurationOption.
+type UnknownConfigurationOptionError = core.UnknownConfigurationOptionError
+
+// ConfigurationOptionTypeError is returned when the value of a fact cannot be converted
+// to the type of the corresponding ConfigurationOption.
+type ConfigurationOptionTypeError = core.ConfigurationOptionTypeError
+
+// UnsatisfiedDependencyError is returned from Pipeline.Initialize() when none of the items
+// provides the key which another item requires.
+type UnsatisfiedDependencyError = core.UnsatisfiedDependencyError
+
+// AmbiguousProviderError is returned from Pipeline.Initialize() when too many items provide
+// the same key and the DAG cannot be resolved.
+type AmbiguousProviderError = core.AmbiguousProviderError
+
+// UnknownLeafError is returned when there is no LeafPipelineItem with the specified flag.
+type UnknownLeafError = core.UnknownLeafError
+
 const (
 	// ConfigPipelineDAGPath is the name of the Pipeline configuration option (Pipeline.Initialize())
 	// which enables saving the items DAG to the specified file.
@@ -78,6 +185,27 @@ const (
 	// ConfigPipelineCommits is the name of the Pipeline configuration option (Pipeline.Initialize())
 	// which allows to specify the custom commit sequence. By default, Pipeline.Commits() is used.
 	ConfigPipelineCommits = core.ConfigPipelineCommits
+	// ConfigPipelineWorkers is the name of the Pipeline configuration option (Pipeline.Initialize())
+	// which sets the maximum number of concurrent Consume() calls on independent branches.
+	// 0 and 1 mean the sequential execution.
+	ConfigPipelineWorkers = core.ConfigPipelineWorkers
+	// ConfigPipelineCheckpointDirectory is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which sets the directory where to periodically save the state.
+	ConfigPipelineCheckpointDirectory = core.ConfigPipelineCheckpointDirectory
+	// ConfigPipelineCheckpointInterval is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which sets the minimum number of commits between two checkpoints.
+	ConfigPipelineCheckpointInterval = core.ConfigPipelineCheckpointInterval
+	// ConfigPipelineResume is the name of the Pipeline configuration option (Pipeline.Initialize())
+	// which makes Run() continue from the checkpoint in the specified directory.
+	ConfigPipelineResume = core.ConfigPipelineResume
+	// ConfigPipelineIncremental is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which makes Run() analyse only the commits added since the state
+	// in the specified directory was saved, and update that state.
+	ConfigPipelineIncremental = core.ConfigPipelineIncremental
+	// FactPipelineSignatures is the name of the fact which PipelineItem-s insert in Configure()
+	// to describe the commit signatures which identify the developers, e.g. "author".
+	// Run() copies it to CommonAnalysisResult.Signatures.
+	FactPipelineSignatures = core.FactPipelineSignatures
 )
 
 // NewPipeline initializes a new instance of Pipeline struct.
@@ -108,18 +236,25 @@ type PipelineItemRegistry = core.PipelineItemRegistry
 var Registry = core.Registry
 
 const (
-	// DependencyCommit is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// DependencyCommit is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It corresponds to the currently analyzed commit.
 	DependencyCommit = core.DependencyCommit
-	// DependencyIndex is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// DependencyIndex is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It corresponds to the currently analyzed commit's index.
 	DependencyIndex = core.DependencyIndex
-	// DependencyIsMerge is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// DependencyIsMerge is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It indicates whether the analyzed commit is a merge commit.
 	// Checking the number of parents is not correct - we remove the back edges during the DAG simplification.
 	DependencyIsMerge = core.DependencyIsMerge
+	// DependencyContext is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
+	// which always exists. It is the context.Context of Pipeline.RunContext(), the items which
+	// make long calls should respect its cancellation.
+	DependencyContext = core.DependencyContext
 	// DependencyAuthor is the name of the dependency provided by identity.Detector.
 	DependencyAuthor = identity.DependencyAuthor
+	// DependencyAuthors is the name of the dependency provided by identity.Detector.
+	// It lists the commit author followed by the co-authors.
+	DependencyAuthors = identity.DependencyAuthors
 	// DependencyBlobCache identifies the dependency provided by BlobCache.
 	DependencyBlobCache = plumbing.DependencyBlobCache
 	// DependencyDay is the name of the dependency which DaysSinceStart provides - the number
@@ -147,6 +282,9 @@ const (
 	// identity.Detector.Configure(). It corresponds to identity.Detector.ReversedPeopleDict -
 	// the mapping from the author indices to the main signature.
 	FactIdentityDetectorReversedPeopleDict = identity.FactIdentityDetectorReversedPeopleDict
+	// FactIdentityDetectorBots is the name of the fact which is inserted in
+	// identity.Detector.Configure(). It maps the indices of the bot identities to true.
+	FactIdentityDetectorBots = identity.FactIdentityDetectorBots
 )
 
 // FileDiffData is the type of the dependency provided by plumbing.FileDiff.
@@ -161,11 +299,23 @@ func SafeYamlString(str string) string {
 	return yaml.SafeString(str)
 }
 
+// WriteOpenMetrics writes the metric families in the OpenMetrics text format.
+func WriteOpenMetrics(writer io.Writer, families []MetricFamily) error {
+	return core.WriteOpenMetrics(writer, families)
+}
+
 // PathifyFlagValue changes the type of a string command line argument to "path".
 func PathifyFlagValue(flag *pflag.Flag) {
 	core.PathifyFlagValue(flag)
 }
 
+// AddItemFlags inserts the cmdline options from ListConfigurationOptions() of a single
+// PipelineItem into the flag set. Returns the "facts" which can be fed into
+// PipelineItem.Configure().
+func AddItemFlags(flagSet *pflag.FlagSet, item PipelineItem) map[string]interface{} {
+	return core.AddItemFlags(flagSet, item)
+}
+
 // EnablePathFlagTypeMasquerade changes the type of all "path" command line arguments from "string"
 // to "path". This operation cannot be canceled and is intended to be used for better --help output.
 func EnablePathFlagTypeMasquerade() {
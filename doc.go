@@ -35,6 +35,9 @@ Finally extract the result:
 
   result := result[ba].(hercules.BurndownResult)
 
+Pipeline.RunContext() accepts a context.Context to interrupt the analysis. In that case
+it returns the partial results together with *hercules.InterruptedError.
+
 The actual usage example is cmd/hercules/root.go - the command line tool's code.
 
 Hercules depends heavily on https://github.com/src-d/go-git and leverages the
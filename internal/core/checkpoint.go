@@ -0,0 +1,228 @@
+package core
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// CheckpointFileName is the name of the file inside the checkpoint directory which
+	// stores the state of an interrupted Pipeline.Run().
+	CheckpointFileName = "checkpoint.gob"
+	// IncrementalStateFileName is the name of the file inside the incremental directory which
+	// stores the state of the pipeline after the last analysed commit.
+	IncrementalStateFileName = "state.gob"
+)
+
+// NoopCheckpointer provides empty SaveState() and LoadState() methods suitable for
+// PipelineItem-s which do not carry any state between commits.
+type NoopCheckpointer struct {
+}
+
+// SaveState does nothing.
+func (checkpointer *NoopCheckpointer) SaveState(writer io.Writer) error {
+	return nil
+}
+
+// LoadState does nothing.
+func (checkpointer *NoopCheckpointer) LoadState(reader io.Reader) error {
+	return nil
+}
+
+// checkpoint is the serialized state of Pipeline.Run() at the moment right before
+// executing the plan action at Position. Checkpoints are only taken when a single branch
+// is alive, so that the states of the forked items do not have to be reconciled.
+// The final state for the incremental analysis has Position equal to PlanLength and
+// Commit set to the last analysed commit.
+type checkpoint struct {
+	// Position is the index of the next action in the execution plan.
+	Position int
+	// PlanLength is the total number of actions in the execution plan.
+	PlanLength int
+	// Commit is the hash of the commit which is processed at Position.
+	Commit string
+	// BeginTime is the timestamp of the first analysed commit.
+	BeginTime int64
+	// CommitsNumber is the number of commits passed to Pipeline.Run().
+	CommitsNumber int
+	// Branch is the index of the only alive branch.
+	Branch int
+	// CommitIndex is the number of already processed commits.
+	CommitIndex int
+	// NewestTime is the maximum commit timestamp seen so far.
+	NewestTime int64
+	// RunTime is the accumulated duration of Pipeline.Run().
+	RunTime time.Duration
+	// RunTimePerItem is the accumulated time elapsed by each PipelineItem.
+	RunTimePerItem map[string]float64
+	// Items are the names of the items in the pipeline.
+	Items []string
+	// States are the results of CheckpointablePipelineItem.SaveState(), one per item.
+	States [][]byte
+	// Facts are the values of the items' configuration options.
+	Facts map[string]interface{}
+}
+
+// collectCheckpointFacts picks the values of the configuration options of the deployed items.
+func (pipeline *Pipeline) collectCheckpointFacts(facts map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, item := range pipeline.items {
+		for _, opt := range item.ListConfigurationOptions() {
+			if val, exists := facts[opt.Name]; exists {
+				switch val.(type) {
+				case bool, int, string, float32, float64, []string:
+					result[opt.Name] = val
+				}
+			}
+		}
+	}
+	return result
+}
+
+// checkCheckpointable returns an error if some of the items cannot save their state.
+func (pipeline *Pipeline) checkCheckpointable() error {
+	for _, item := range pipeline.items {
+		if _, ok := item.(CheckpointablePipelineItem); !ok {
+			return errors.Errorf("%s does not support checkpoints", item.Name())
+		}
+	}
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint from the specified file in the directory and validates
+// it against the current pipeline.
+func (pipeline *Pipeline) loadCheckpoint(dir, name string) (*checkpoint, error) {
+	file, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	state := &checkpoint{}
+	err = gob.NewDecoder(file).Decode(state)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the checkpoint in %s", dir)
+	}
+	if len(state.Items) != len(pipeline.items) || len(state.States) != len(pipeline.items) {
+		return nil, errors.Errorf("the checkpoint in %s was created with a different pipeline", dir)
+	}
+	for i, item := range pipeline.items {
+		if state.Items[i] != item.Name() {
+			return nil, errors.Errorf(
+				"the checkpoint in %s was created with a different pipeline: %s != %s",
+				dir, state.Items[i], item.Name())
+		}
+	}
+	return state, nil
+}
+
+// saveCheckpoint writes the checkpoint to the specified file in the directory. The file is
+// replaced atomically so that an interruption while saving never corrupts the previous checkpoint.
+func (pipeline *Pipeline) saveCheckpoint(
+	dir, name string, state *checkpoint, items []PipelineItem) error {
+	state.Items = make([]string, len(items))
+	state.States = make([][]byte, len(items))
+	for i, item := range items {
+		state.Items[i] = item.Name()
+		buffer := &bytes.Buffer{}
+		err := item.(CheckpointablePipelineItem).SaveState(buffer)
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to save the state", item.Name())
+		}
+		state.States[i] = buffer.Bytes()
+	}
+	err := os.MkdirAll(dir, 0777)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(dir, "*-"+name)
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(file).Encode(state)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(dir, name))
+}
+
+// restoreCheckpoint loads the saved states into the items.
+func (pipeline *Pipeline) restoreCheckpoint(state *checkpoint) error {
+	for i, item := range pipeline.items {
+		err := item.(CheckpointablePipelineItem).LoadState(bytes.NewReader(state.States[i]))
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to load the state", item.Name())
+		}
+	}
+	return nil
+}
+
+// newIncrementalCommits returns the commits which are not reachable from the last analysed one.
+// The result is the same as of the full analysis only if the new history continues from
+// the last analysed commit, otherwise the states at the older commits would be needed.
+func newIncrementalCommits(commits []*object.Commit, last string) ([]*object.Commit, error) {
+	lastHash := plumbing.NewHash(last)
+	hashes := map[plumbing.Hash]*object.Commit{}
+	for _, commit := range commits {
+		hashes[commit.Hash] = commit
+	}
+	if hashes[lastHash] == nil {
+		return nil, errors.Errorf("the last analysed commit %s was not found", last)
+	}
+	analysed := map[plumbing.Hash]bool{}
+	queue := []plumbing.Hash{lastHash}
+	for len(queue) > 0 {
+		hash := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		commit := hashes[hash]
+		if commit == nil || analysed[hash] {
+			continue
+		}
+		analysed[hash] = true
+		queue = append(queue, commit.ParentHashes...)
+	}
+	var result []*object.Commit
+	for _, commit := range commits {
+		if analysed[commit.Hash] {
+			continue
+		}
+		parents := 0
+		continues := false
+		for _, parent := range commit.ParentHashes {
+			if hashes[parent] == nil {
+				continue
+			}
+			parents++
+			if parent == lastHash {
+				continues = true
+			} else if analysed[parent] {
+				return nil, errors.Errorf(
+					"commit %s has parent %s which was analysed before %s; "+
+						"run the full analysis or use --first-parent",
+					commit.Hash.String(), parent.String(), last)
+			}
+		}
+		if continues && parents > 1 {
+			return nil, errors.Errorf(
+				"merge commit %s joins %s with the new commits; "+
+					"run the full analysis or use --first-parent", commit.Hash.String(), last)
+		}
+		result = append(result, commit)
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProgressEventType is the kind of a ProgressEvent.
+type ProgressEventType int
+
+const (
+	// ProgressCommitStarted is emitted before the commit is passed to the items.
+	ProgressCommitStarted ProgressEventType = iota
+	// ProgressCommitFinished is emitted after all the items Consume()-d the commit.
+	ProgressCommitFinished
+	// ProgressItemConsumed is emitted after each PipelineItem.Consume(). ProgressEvent.Elapsed
+	// is the time spent inside the call.
+	ProgressItemConsumed
+	// ProgressFork is emitted after the branch is cloned.
+	ProgressFork
+	// ProgressMerge is emitted after the branches are merged.
+	ProgressMerge
+	// ProgressHibernate is emitted after the items of the branches are hibernated.
+	ProgressHibernate
+	// ProgressBoot is emitted after the items of the branches are booted.
+	ProgressBoot
+	// ProgressFinalize is emitted before calling LeafPipelineItem.Finalize()-s.
+	ProgressFinalize
+	// ProgressItemFinalized is emitted after each LeafPipelineItem.Finalize().
+	ProgressItemFinalized
+	// ProgressDone is emitted when the run ends.
+	ProgressDone
+)
+
+var progressEventTypeNames = [...]string{
+	"commit_started", "commit_finished", "item_consumed", "fork", "merge", "hibernate", "boot",
+	"finalize", "item_finalized", "done",
+}
+
+// String returns the name of the event type which is used in JSON.
+func (t ProgressEventType) String() string {
+	if t < 0 || int(t) >= len(progressEventTypeNames) {
+		return fmt.Sprintf("ProgressEventType(%d)", int(t))
+	}
+	return progressEventTypeNames[t]
+}
+
+// MarshalText encodes the event type as its name, see String().
+func (t ProgressEventType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// ProgressEvent describes a single step of Pipeline.Run(). It is delivered to Pipeline.OnEvent.
+type ProgressEvent struct {
+	// Type is the kind of the event.
+	Type ProgressEventType `json:"type"`
+	// Time is the moment when the event was emitted.
+	Time time.Time `json:"time"`
+	// Step is the number of the current step in the execution plan, starting from 1.
+	Step int `json:"step"`
+	// Total is the overall number of steps, the same as in Pipeline.OnProgress.
+	Total int `json:"total"`
+	// Commit is the hash of the processed commit, if any.
+	Commit string `json:"commit,omitempty"`
+	// Branches are the indexes of the affected branches in the execution plan.
+	Branches []int `json:"branches,omitempty"`
+	// Item is the name of the PipelineItem for ProgressItemConsumed and ProgressItemFinalized.
+	Item string `json:"item,omitempty"`
+	// Elapsed is the duration of the action in seconds.
+	Elapsed float64 `json:"elapsed,omitempty"`
+}
+
+// eventEmitter returns the function which stamps the events and passes them to OnEvent.
+func (pipeline *Pipeline) eventEmitter() func(ProgressEvent) {
+	onEvent := pipeline.OnEvent
+	if onEvent == nil {
+		return func(ProgressEvent) {}
+	}
+	return func(event ProgressEvent) {
+		event.Time = time.Now()
+		onEvent(event)
+	}
+}
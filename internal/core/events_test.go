@@ -0,0 +1,38 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressEventTypeString(t *testing.T) {
+	assert.Equal(t, "commit_started", ProgressCommitStarted.String())
+	assert.Equal(t, "done", ProgressDone.String())
+	assert.Equal(t, "ProgressEventType(100)", ProgressEventType(100).String())
+}
+
+func TestProgressEventJSON(t *testing.T) {
+	event := ProgressEvent{
+		Type:     ProgressItemConsumed,
+		Time:     time.Unix(0, 0).UTC(),
+		Step:     2,
+		Total:    4,
+		Commit:   "af9ddc0db70f09f3f27b4b98e415592a7485171c",
+		Branches: []int{1},
+		Item:     "Test",
+		Elapsed:  0.5,
+	}
+	data, err := json.Marshal(event)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"item_consumed","time":"1970-01-01T00:00:00Z","step":2,"total":4,`+
+		`"commit":"af9ddc0db70f09f3f27b4b98e415592a7485171c","branches":[1],"item":"Test",`+
+		`"elapsed":0.5}`, string(data))
+	data, err = json.Marshal(ProgressEvent{Type: ProgressDone, Time: time.Unix(0, 0).UTC(),
+		Step: 4, Total: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"done","time":"1970-01-01T00:00:00Z","step":4,"total":4}`,
+		string(data))
+}
@@ -176,7 +176,7 @@ func prepareRunPlan(commits []*object.Commit, hibernationDistance int,
 	}
 	fmt.Printf("}\n")*/
 	plan := generatePlan(orderNodes, hashes, mergedDag, dag, mergedSeq)
-	plan = collectGarbage(plan)
+	plan = collectGarbage(plan, findMasterBranch(plan, dag))
 	if hibernationDistance > 0 {
 		plan = insertHibernateBoot(plan, hibernationDistance)
 	}
@@ -228,6 +228,77 @@ func getCommitParents(commit *object.Commit) []plumbing.Hash {
 	return result
 }
 
+// rewriteParents connects the selected commits through the skipped ones, the same way as
+// `git log --parents` does with a commit limiting option. "walked" contains all the traversed
+// commits, both selected and skipped. Each parent which was skipped is replaced with its nearest
+// selected ancestors, so that the result does not fall apart into disjoint components.
+// The parents which were not traversed or do not lead to any selected commit stay as they are;
+// buildDag() ignores them and the corresponding commits become the roots of the DAG.
+// The commits with the changed parents are shallow copies of the originals.
+func rewriteParents(
+	walked map[plumbing.Hash]*object.Commit, selected []*object.Commit) []*object.Commit {
+
+	isSelected := map[plumbing.Hash]bool{}
+	for _, commit := range selected {
+		isSelected[commit.Hash] = true
+	}
+	nearest := map[plumbing.Hash][]plumbing.Hash{}
+	var findNearest func(hash plumbing.Hash) []plumbing.Hash
+	findNearest = func(hash plumbing.Hash) []plumbing.Hash {
+		if result, exists := nearest[hash]; exists {
+			return result
+		}
+		// guard against visiting the same commit twice while the result is not ready
+		nearest[hash] = nil
+		var result []plumbing.Hash
+		seen := map[plumbing.Hash]bool{}
+		for _, parent := range getCommitParents(walked[hash]) {
+			var candidates []plumbing.Hash
+			if isSelected[parent] {
+				candidates = []plumbing.Hash{parent}
+			} else if _, exists := walked[parent]; exists {
+				candidates = findNearest(parent)
+			}
+			for _, candidate := range candidates {
+				if !seen[candidate] {
+					seen[candidate] = true
+					result = append(result, candidate)
+				}
+			}
+		}
+		nearest[hash] = result
+		return result
+	}
+	result := make([]*object.Commit, 0, len(selected))
+	for _, commit := range selected {
+		var parents []plumbing.Hash
+		seen := map[plumbing.Hash]bool{}
+		changed := false
+		for _, parent := range getCommitParents(commit) {
+			candidates := []plumbing.Hash{parent}
+			if _, exists := walked[parent]; exists && !isSelected[parent] {
+				if rewritten := findNearest(parent); len(rewritten) > 0 {
+					candidates = rewritten
+					changed = true
+				}
+			}
+			for _, candidate := range candidates {
+				if !seen[candidate] {
+					seen[candidate] = true
+					parents = append(parents, candidate)
+				}
+			}
+		}
+		if changed {
+			clone := *commit
+			clone.ParentHashes = parents
+			commit = &clone
+		}
+		result = append(result, commit)
+	}
+	return result
+}
+
 // buildDag generates the raw commit DAG and the commit hash map.
 func buildDag(commits []*object.Commit) (
 	map[string]*object.Commit, map[plumbing.Hash][]*object.Commit) {
@@ -592,7 +663,11 @@ func generatePlan(
 			// merge after the merge commit (the first in the sequence)
 			var items []int
 			minBranch := 1 << 31
-			for parent := range parents[commit.Hash] {
+			// follow the order of the parents, so that the plan is the same in every run
+			for _, parent := range commit.ParentHashes {
+				if !parents[commit.Hash][parent] {
+					continue
+				}
 				parentBranch := -1
 				if parents, exists := branchers[commit.Hash]; exists {
 					if inheritedBranch, exists := parents[parent]; exists {
@@ -675,8 +750,46 @@ func generatePlan(
 	return plan
 }
 
-// collectGarbage inserts `runActionDelete` disposal steps.
-func collectGarbage(plan []runAction) []runAction {
+// findMasterBranch returns the branch which must survive until the end of the plan when
+// the history has several heads: the one with the most recently committed head.
+// There is no way to merge the unrelated heads, so the rest of them are dropped after
+// they are analysed and logged. Returns 0 if there is only one head.
+func findMasterBranch(plan []runAction, dag map[plumbing.Hash][]*object.Commit) int {
+	var master *object.Commit
+	var heads []*object.Commit
+	for _, p := range plan {
+		if p.Action != runActionCommit || len(dag[p.Commit.Hash]) > 0 {
+			continue
+		}
+		heads = append(heads, p.Commit)
+		if master == nil || p.Commit.Committer.When.After(master.Committer.When) ||
+			(p.Commit.Committer.When.Equal(master.Committer.When) &&
+				p.Commit.Hash.String() > master.Hash.String()) {
+			master = p.Commit
+		}
+	}
+	if len(heads) <= 1 {
+		return 0
+	}
+	for _, head := range heads {
+		if head.Hash != master.Hash {
+			log.Printf("warning: dropped the final state of %s - unrelated to the master head %s",
+				head.Hash.String(), master.Hash.String())
+		}
+	}
+	branch := 0
+	for _, p := range plan {
+		if p.Action == runActionCommit && p.Commit.Hash == master.Hash {
+			branch = p.Items[0]
+		}
+	}
+	return branch
+}
+
+// collectGarbage inserts `runActionDelete` disposal steps. If `master` is not 0, that branch
+// is the only one which remains after the last step, otherwise the branches which are used
+// in the last step remain.
+func collectGarbage(plan []runAction, master int) []runAction {
 	// lastMentioned maps branch index to the index inside `plan` when that branch was last used
 	lastMentioned := map[int]int{}
 	for i, p := range plan {
@@ -701,7 +814,7 @@ func collectGarbage(plan []runAction) []runAction {
 	var garbageCollectedPlan []runAction
 	lastMentionedArr := make([][2]int, 0, len(lastMentioned)+1)
 	for key, val := range lastMentioned {
-		if val != len(plan)-1 {
+		if (master == 0 && val != len(plan)-1) || (master != 0 && key != master) {
 			lastMentionedArr = append(lastMentionedArr, [2]int{val, key})
 		}
 	}
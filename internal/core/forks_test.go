@@ -1,11 +1,17 @@
 package core
 
 import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/hercules.v9/internal/test"
 )
 
@@ -120,3 +126,89 @@ func TestRunActionString(t *testing.T) {
 	ra = runAction{runActionBoot, nil, nil}
 	assert.Equal(t, ra.String(), "boot")
 }
+
+func TestRewriteParents(t *testing.T) {
+	hash := func(name string) plumbing.Hash {
+		return plumbing.NewHash(strings.Repeat(name, 40))
+	}
+	commit := func(name string, parents ...string) *object.Commit {
+		c := &object.Commit{Hash: hash(name)}
+		for _, parent := range parents {
+			c.ParentHashes = append(c.ParentHashes, hash(parent))
+		}
+		return c
+	}
+	// 0 is outside of the traversal
+	a, b, c := commit("a", "0"), commit("b", "a"), commit("c", "b")
+	d, e, f := commit("d", "c", "b"), commit("e", "0"), commit("f", "e")
+	walked := map[plumbing.Hash]*object.Commit{}
+	for _, commit := range []*object.Commit{a, b, c, d, e, f} {
+		walked[commit.Hash] = commit
+	}
+	// b and e are skipped
+	result := rewriteParents(walked, []*object.Commit{a, c, d, f})
+	assert.Len(t, result, 4)
+	assert.True(t, result[0] == a)
+	assert.False(t, result[1] == c)
+	assert.Equal(t, c.Hash, result[1].Hash)
+	assert.Equal(t, []plumbing.Hash{hash("a")}, result[1].ParentHashes)
+	assert.Equal(t, []plumbing.Hash{hash("c"), hash("a")}, result[2].ParentHashes)
+	// e does not lead to any selected commit
+	assert.True(t, result[3] == f)
+	// the originals are intact
+	assert.Equal(t, []plumbing.Hash{hash("b")}, c.ParentHashes)
+	hashes, dag := buildDag(result)
+	leaveRootComponent(hashes, dag)
+	assert.Len(t, hashes, 3)
+	assert.Len(t, dag[hash("a")], 2)
+}
+
+func TestPrepareRunPlanSeveralHeads(t *testing.T) {
+	hash := func(name string) plumbing.Hash {
+		return plumbing.NewHash(strings.Repeat(name, 40))
+	}
+	commit := func(name string, when int64, parents ...string) *object.Commit {
+		c := &object.Commit{Hash: hash(name)}
+		c.Committer.When = time.Unix(when, 0)
+		for _, parent := range parents {
+			c.ParentHashes = append(c.ParentHashes, hash(parent))
+		}
+		return c
+	}
+	a, b, c := commit("a", 1), commit("b", 2, "a"), commit("c", 4, "b")
+	d := commit("d", 3, "b")
+	for _, heads := range [][]*object.Commit{{a, b, c, d}, {a, b, d, c}} {
+		myOutput := &bytes.Buffer{}
+		log.SetOutput(myOutput)
+		plan := prepareRunPlan(heads, 0, false)
+		log.SetOutput(os.Stderr)
+		// the dropped head is logged
+		assert.Contains(t, myOutput.String(), d.Hash.String())
+		assert.NotContains(t, myOutput.String(), "dropped the final state of "+c.Hash.String())
+		alive := map[int]bool{}
+		headBranch := 0
+		for _, p := range plan {
+			switch p.Action {
+			case runActionEmerge, runActionFork:
+				for _, item := range p.Items {
+					alive[item] = true
+				}
+			case runActionDelete:
+				delete(alive, p.Items[0])
+			case runActionCommit:
+				if p.Commit == c {
+					headBranch = p.Items[0]
+				}
+			}
+		}
+		// the newest head survives
+		assert.Equal(t, map[int]bool{headBranch: true}, alive)
+	}
+	// the legacy behavior with a single head
+	plan := prepareRunPlan([]*object.Commit{a, b, c}, 0, false)
+	for _, p := range plan {
+		assert.NotEqual(t, runActionDelete, p.Action)
+	}
+	assert.Equal(t, 0, findMasterBranch(plan, map[plumbing.Hash][]*object.Commit{
+		a.Hash: {b}, b.Hash: {c}, c.Hash: {}}))
+}
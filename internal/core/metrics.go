@@ -0,0 +1,114 @@
+package core
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetricType is the OpenMetrics type of a MetricFamily.
+type MetricType string
+
+const (
+	// GaugeMetric is the value which can go up and down, e.g. the number of surviving lines.
+	GaugeMetric MetricType = "gauge"
+	// CounterMetric is the value which only grows, e.g. the number of commits.
+	// The samples are written with the "_total" suffix.
+	CounterMetric MetricType = "counter"
+)
+
+// Metric is a single sample of a MetricFamily.
+type Metric struct {
+	// Labels distinguish the samples of the same family, e.g. {"developer": "..."}.
+	Labels map[string]string
+	// Value is the sampled value.
+	Value float64
+}
+
+// MetricFamily is the group of samples with the same name, help and type.
+type MetricFamily struct {
+	// Name is the metric name without the "_total" suffix, e.g. "hercules_devs_commits".
+	Name string
+	// Help is the human-readable description of the metric.
+	Help string
+	// Type is the OpenMetrics type of the metric.
+	Type MetricType
+	// Metrics are the samples.
+	Metrics []Metric
+}
+
+// MetricsPipelineItem is the interface for LeafPipelineItem-s which are able to export their
+// results as OpenMetrics, so that the repository health can be monitored without custom scrapers.
+type MetricsPipelineItem interface {
+	LeafPipelineItem
+
+	// Metrics converts the result returned by Finalize() to the metric families.
+	Metrics(result interface{}) []MetricFamily
+}
+
+// WriteOpenMetrics writes the metric families in the OpenMetrics text format, including
+// the terminating "# EOF". The families with the same name are joined together.
+func WriteOpenMetrics(writer io.Writer, families []MetricFamily) error {
+	var order []string
+	joined := map[string]*MetricFamily{}
+	for _, family := range families {
+		if existing := joined[family.Name]; existing != nil {
+			existing.Metrics = append(existing.Metrics, family.Metrics...)
+			continue
+		}
+		copied := family
+		copied.Metrics = append([]Metric{}, family.Metrics...)
+		joined[family.Name] = &copied
+		order = append(order, family.Name)
+	}
+	buffered := bufio.NewWriter(writer)
+	for _, name := range order {
+		family := joined[name]
+		buffered.WriteString("# TYPE " + name + " " + string(family.Type) + "\n")
+		if family.Help != "" {
+			buffered.WriteString("# HELP " + name + " " + escapeMetricHelp(family.Help) + "\n")
+		}
+		sampleName := name
+		if family.Type == CounterMetric {
+			sampleName += "_total"
+		}
+		for _, metric := range family.Metrics {
+			buffered.WriteString(sampleName)
+			buffered.WriteString(formatMetricLabels(metric.Labels))
+			buffered.WriteString(" " + strconv.FormatFloat(metric.Value, 'f', -1, 64) + "\n")
+		}
+	}
+	buffered.WriteString("# EOF\n")
+	return buffered.Flush()
+}
+
+// formatMetricLabels returns the labels sorted by name in the curly braces.
+func formatMetricLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=\"" + escapeMetricLabel(labels[name]) + "\""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeMetricLabel(value string) string {
+	return metricLabelReplacer.Replace(value)
+}
+
+var metricHelpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeMetricHelp(value string) string {
+	return metricHelpReplacer.Replace(value)
+}
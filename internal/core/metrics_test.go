@@ -0,0 +1,46 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := WriteOpenMetrics(buffer, []MetricFamily{{
+		Name: "hercules_test_commits",
+		Help: "The number of commits.\nSecond line.",
+		Type: CounterMetric,
+		Metrics: []Metric{
+			{Labels: map[string]string{"repository": "a", "developer": "Vadim \"vmarkovtsev\""}, Value: 10},
+		},
+	}, {
+		Name:    "hercules_test_lines",
+		Type:    GaugeMetric,
+		Metrics: []Metric{{Value: 1.5}},
+	}, {
+		Name: "hercules_test_commits",
+		Help: "The number of commits.",
+		Type: CounterMetric,
+		Metrics: []Metric{
+			{Labels: map[string]string{"repository": "b", "developer": "back\\slash"}, Value: 20},
+		},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, `# TYPE hercules_test_commits counter
+# HELP hercules_test_commits The number of commits.\nSecond line.
+hercules_test_commits_total{developer="Vadim \"vmarkovtsev\"",repository="a"} 10
+hercules_test_commits_total{developer="back\\slash",repository="b"} 20
+# TYPE hercules_test_lines gauge
+hercules_test_lines 1.5
+# EOF
+`, buffer.String())
+}
+
+func TestWriteOpenMetricsEmpty(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, WriteOpenMetrics(buffer, nil))
+	assert.Equal(t, "# EOF\n", buffer.String())
+}
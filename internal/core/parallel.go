@@ -0,0 +1,441 @@
+package core
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/storage"
+)
+
+// concurrentTask carries the state of a single commit through the items of a branch.
+type concurrentTask struct {
+	// ordinal is the position of the commit in the executed segment of the plan.
+	ordinal int
+	step    runAction
+	state   map[string]interface{}
+}
+
+// concurrentRunner executes a contiguous sequence of runActionCommit-s from the plan.
+// Each item of each branch is a separate stage which runs in its own goroutine, so the commits
+// on different branches as well as the consecutive commits of the same branch are processed
+// at the same time. The number of simultaneous Consume() calls is limited by Pipeline.Workers.
+// SequentialPipelineItem-s Consume() the commits in the plan order across all the branches.
+type concurrentRunner struct {
+	ctx            context.Context
+	segment        []runAction
+	planOffset     int
+	commitOffset   int
+	branches       map[int][]PipelineItem
+	isMerge        []bool
+	sequential     []bool
+	runTimePerItem map[string]float64
+	onProgress     func(int, int, string)
+	onEvent        func(ProgressEvent)
+	progressSteps  int
+
+	workers chan struct{}
+	lock    sync.Mutex
+	turn    *sync.Cond
+	// turns contains the ordinal of the next commit each sequential item may Consume().
+	turns    []int
+	done     int
+	failed   bool
+	errIndex int
+	err      error
+	// admitted is the ordinal of the latest commit fed to the stages plus one.
+	admitted int
+	// interrupted indicates that the context is done and no commits starting from cutoff
+	// are going to be fed.
+	interrupted bool
+	cutoff      int
+}
+
+// runConcurrently executes plan[planOffset:planOffset+len(segment)] which consists
+// solely of runActionCommit-s. It returns the number of processed commits, which is less than
+// len(segment) if the context is done, and the error from the earliest failed commit.
+func (pipeline *Pipeline) runConcurrently(
+	ctx context.Context, segment []runAction, planOffset, commitOffset int,
+	branches map[int][]PipelineItem, isMerge []bool, runTimePerItem map[string]float64,
+	onProgress func(int, int, string), onEvent func(ProgressEvent),
+	progressSteps int) (int, error) {
+	runner := &concurrentRunner{
+		ctx:            ctx,
+		segment:        segment,
+		planOffset:     planOffset,
+		commitOffset:   commitOffset,
+		branches:       branches,
+		isMerge:        isMerge,
+		sequential:     make([]bool, len(pipeline.items)),
+		runTimePerItem: runTimePerItem,
+		onProgress:     onProgress,
+		onEvent:        onEvent,
+		progressSteps:  progressSteps,
+		workers:        make(chan struct{}, pipeline.Workers),
+		turns:          make([]int, len(pipeline.items)),
+	}
+	runner.turn = sync.NewCond(&runner.lock)
+	for i, item := range pipeline.items {
+		if seq, ok := item.(SequentialPipelineItem); ok {
+			runner.sequential[i] = seq.Sequential()
+		}
+	}
+	return runner.run()
+}
+
+func (runner *concurrentRunner) run() (int, error) {
+	tasks := map[int][]*concurrentTask{}
+	var order []int
+	for i, step := range runner.segment {
+		branch := step.Items[0]
+		if _, exists := tasks[branch]; !exists {
+			order = append(order, branch)
+		}
+		tasks[branch] = append(tasks[branch], &concurrentTask{
+			ordinal: i,
+			step:    step,
+			state: map[string]interface{}{
+				DependencyCommit:  step.Commit,
+				DependencyIndex:   runner.commitOffset + i,
+				DependencyIsMerge: runner.isMerge[i],
+				DependencyContext: runner.ctx,
+			},
+		})
+	}
+	capacity := cap(runner.workers)
+	var wg sync.WaitGroup
+	for _, branch := range order {
+		items := runner.branches[branch]
+		feed := make(chan *concurrentTask, capacity)
+		go func(branchTasks []*concurrentTask) {
+			defer close(feed)
+			for _, task := range branchTasks {
+				if !runner.admit(task) {
+					return
+				}
+				feed <- task
+			}
+		}(tasks[branch])
+		var in <-chan *concurrentTask = feed
+		for i, item := range items {
+			out := make(chan *concurrentTask, capacity)
+			go runner.stage(i, item, in, out)
+			in = out
+		}
+		wg.Add(1)
+		go func(in <-chan *concurrentTask) {
+			defer wg.Done()
+			for task := range in {
+				runner.finish(task)
+			}
+		}(in)
+	}
+	wg.Wait()
+	if runner.failed {
+		return runner.errIndex, runner.err
+	}
+	if runner.interrupted {
+		return runner.cutoff, nil
+	}
+	return len(runner.segment), nil
+}
+
+// admit returns whether the commit should be fed to the stages.
+// When the context is done, only the commits before the earliest not yet admitted one
+// are processed, so that the processed commits form a prefix of the segment.
+func (runner *concurrentRunner) admit(task *concurrentTask) bool {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+	ordinal := task.ordinal
+	if !runner.interrupted && runner.ctx.Err() != nil {
+		runner.interrupted = true
+		runner.cutoff = runner.admitted
+	}
+	if runner.interrupted && ordinal >= runner.cutoff {
+		return false
+	}
+	if ordinal >= runner.admitted {
+		runner.admitted = ordinal + 1
+	}
+	runner.emit(ProgressCommitStarted, task, "", 0)
+	return true
+}
+
+// emit reports the event about the commit. The lock must be held.
+func (runner *concurrentRunner) emit(
+	kind ProgressEventType, task *concurrentTask, item string, elapsed float64) {
+	runner.onEvent(ProgressEvent{
+		Type:     kind,
+		Step:     runner.planOffset + task.ordinal + 1,
+		Total:    runner.progressSteps,
+		Commit:   task.step.Commit.Hash.String(),
+		Branches: task.step.Items,
+		Item:     item,
+		Elapsed:  elapsed,
+	})
+}
+
+// stage feeds the commits to a single item of a branch.
+func (runner *concurrentRunner) stage(
+	index int, item PipelineItem, in <-chan *concurrentTask, out chan<- *concurrentTask) {
+	defer close(out)
+	for task := range in {
+		if runner.sequential[index] {
+			runner.waitTurn(index, task.ordinal)
+		}
+		if !runner.isFailed() {
+			runner.consume(item, task)
+		}
+		if runner.sequential[index] {
+			runner.passTurn(index)
+		}
+		out <- task
+	}
+}
+
+func (runner *concurrentRunner) consume(item PipelineItem, task *concurrentTask) {
+	runner.workers <- struct{}{}
+	startTime := time.Now()
+	update, err := item.Consume(task.state)
+	elapsed := time.Now().Sub(startTime).Seconds()
+	<-runner.workers
+	runner.lock.Lock()
+	runner.runTimePerItem[item.Name()] += elapsed
+	runner.emit(ProgressItemConsumed, task, item.Name(), elapsed)
+	runner.lock.Unlock()
+	if err != nil {
+		log.Printf("%s failed on commit #%d (%d) %s\n",
+			item.Name(), runner.commitOffset+task.ordinal+1, runner.planOffset+task.ordinal+1,
+			task.step.Commit.Hash.String())
+		runner.fail(task.ordinal, err)
+		return
+	}
+	for _, key := range item.Provides() {
+		val, ok := update[key]
+		if !ok {
+			log.Panicf("%s: Consume() did not return %s", item.Name(), key)
+		}
+		task.state[key] = val
+	}
+}
+
+// waitTurn blocks until all the previous commits in the plan order have been consumed
+// by the sequential item with the specified index.
+func (runner *concurrentRunner) waitTurn(index, ordinal int) {
+	runner.lock.Lock()
+	for runner.turns[index] != ordinal && !runner.failed {
+		runner.turn.Wait()
+	}
+	runner.lock.Unlock()
+}
+
+func (runner *concurrentRunner) passTurn(index int) {
+	runner.lock.Lock()
+	runner.turns[index]++
+	runner.lock.Unlock()
+	runner.turn.Broadcast()
+}
+
+func (runner *concurrentRunner) isFailed() bool {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+	return runner.failed
+}
+
+// fail stops the execution. The remaining commits are passed through the stages
+// without Consume()-ing.
+func (runner *concurrentRunner) fail(ordinal int, err error) {
+	runner.lock.Lock()
+	if !runner.failed || ordinal < runner.errIndex {
+		runner.errIndex = ordinal
+		runner.err = err
+	}
+	runner.failed = true
+	runner.lock.Unlock()
+	runner.turn.Broadcast()
+}
+
+func (runner *concurrentRunner) finish(task *concurrentTask) {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+	if runner.failed {
+		return
+	}
+	runner.done++
+	runner.onProgress(runner.planOffset+runner.done, runner.progressSteps, task.step.String())
+	runner.emit(ProgressCommitFinished, task, "", 0)
+}
+
+// synchronizedStorer serializes the access to the objects and the references of the wrapped
+// storage. go-git storages are not safe for concurrent use, e.g. the filesystem storage
+// shares the packfile handles and the caches between all the readers.
+type synchronizedStorer struct {
+	storage.Storer
+	lock sync.Mutex
+}
+
+// synchronizeRepository returns a copy of the repository which can be used by several
+// concurrent Consume() calls. The objects are read completely while the lock is held,
+// so that their readers do not touch the storage afterwards.
+func synchronizeRepository(repository *git.Repository) *git.Repository {
+	if _, synchronized := repository.Storer.(*synchronizedStorer); synchronized {
+		return repository
+	}
+	clone := *repository
+	clone.Storer = &synchronizedStorer{Storer: repository.Storer}
+	return &clone
+}
+
+// shareCommits loads the commits from the repository returned by synchronizeRepository(),
+// so that the items read the trees and the blobs through the synchronized storage.
+// The parents are taken from the original commits because CommitsRange may have rewritten them.
+func shareCommits(repository *git.Repository, commits []*object.Commit) ([]*object.Commit, error) {
+	shared := make([]*object.Commit, len(commits))
+	for i, commit := range commits {
+		var err error
+		shared[i], err = object.GetCommit(repository.Storer, commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		shared[i].ParentHashes = commit.ParentHashes
+	}
+	return shared, nil
+}
+
+func (s *synchronizedStorer) SetEncodedObject(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.SetEncodedObject(obj)
+}
+
+func (s *synchronizedStorer) EncodedObject(
+	objType plumbing.ObjectType, hash plumbing.Hash) (plumbing.EncodedObject, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, err := s.Storer.EncodedObject(objType, hash)
+	if err != nil {
+		return nil, err
+	}
+	return loadObject(obj)
+}
+
+func (s *synchronizedStorer) IterEncodedObjects(
+	objType plumbing.ObjectType) (storer.EncodedObjectIter, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	iter, err := s.Storer.IterEncodedObjects(objType)
+	if err != nil {
+		return nil, err
+	}
+	return &synchronizedObjectIter{iter: iter, lock: &s.lock}, nil
+}
+
+func (s *synchronizedStorer) HasEncodedObject(hash plumbing.Hash) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.HasEncodedObject(hash)
+}
+
+func (s *synchronizedStorer) EncodedObjectSize(hash plumbing.Hash) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.EncodedObjectSize(hash)
+}
+
+func (s *synchronizedStorer) SetReference(ref *plumbing.Reference) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.SetReference(ref)
+}
+
+func (s *synchronizedStorer) CheckAndSetReference(ref, old *plumbing.Reference) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.CheckAndSetReference(ref, old)
+}
+
+func (s *synchronizedStorer) Reference(name plumbing.ReferenceName) (*plumbing.Reference, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.Reference(name)
+}
+
+func (s *synchronizedStorer) IterReferences() (storer.ReferenceIter, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	iter, err := s.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	// the references are few, so it is simpler to read them all at once
+	var refs []*plumbing.Reference
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storer.NewReferenceSliceIter(refs), nil
+}
+
+func (s *synchronizedStorer) RemoveReference(name plumbing.ReferenceName) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Storer.RemoveReference(name)
+}
+
+// synchronizedObjectIter reads the objects from the wrapped iterator while the storage lock
+// is held.
+type synchronizedObjectIter struct {
+	iter storer.EncodedObjectIter
+	lock *sync.Mutex
+}
+
+func (iter *synchronizedObjectIter) Next() (plumbing.EncodedObject, error) {
+	iter.lock.Lock()
+	defer iter.lock.Unlock()
+	obj, err := iter.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+	return loadObject(obj)
+}
+
+func (iter *synchronizedObjectIter) ForEach(cb func(plumbing.EncodedObject) error) error {
+	return storer.ForEachIterator(iter, cb)
+}
+
+func (iter *synchronizedObjectIter) Close() {
+	iter.lock.Lock()
+	defer iter.lock.Unlock()
+	iter.iter.Close()
+}
+
+// loadObject reads the contents of the object to memory unless they are already there.
+func loadObject(obj plumbing.EncodedObject) (plumbing.EncodedObject, error) {
+	if _, loaded := obj.(*plumbing.MemoryObject); loaded {
+		return obj, nil
+	}
+	reader, err := obj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	memObj := &plumbing.MemoryObject{}
+	memObj.SetType(obj.Type())
+	writer, err := memObj.Writer()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(writer, reader); err != nil {
+		return nil, err
+	}
+	return memObj, nil
+}
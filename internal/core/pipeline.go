@@ -2,11 +2,14 @@ package core
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime/debug"
 	"sort"
@@ -87,6 +90,90 @@ func (opt ConfigurationOption) FormatDefault() string {
 	return fmt.Sprintf("\"%s\"", opt.Default)
 }
 
+// ConvertValue casts the value decoded from JSON or YAML to the type of the configuration option.
+// JSON represents all the numbers as float64 and the lists as []interface{}, so they are
+// converted as long as no precision is lost. The returned error is *ConfigurationOptionTypeError.
+func (opt ConfigurationOption) ConvertValue(value interface{}) (interface{}, error) {
+	switch opt.Type {
+	case BoolConfigurationOption:
+		if val, ok := value.(bool); ok {
+			return val, nil
+		}
+	case IntConfigurationOption:
+		switch val := value.(type) {
+		case int:
+			return val, nil
+		case int64:
+			return int(val), nil
+		case float64:
+			if val == math.Trunc(val) {
+				return int(val), nil
+			}
+		}
+	case StringConfigurationOption, PathConfigurationOption:
+		if val, ok := value.(string); ok {
+			return val, nil
+		}
+	case FloatConfigurationOption:
+		switch val := value.(type) {
+		case float32:
+			return val, nil
+		case float64:
+			return float32(val), nil
+		case int:
+			return float32(val), nil
+		}
+	case StringsConfigurationOption:
+		switch val := value.(type) {
+		case []string:
+			return val, nil
+		case []interface{}:
+			strs := make([]string, len(val))
+			for i, item := range val {
+				str, ok := item.(string)
+				if !ok {
+					return nil, &ConfigurationOptionTypeError{Option: opt, Value: value}
+				}
+				strs[i] = str
+			}
+			return strs, nil
+		}
+	}
+	return nil, &ConfigurationOptionTypeError{Option: opt, Value: value}
+}
+
+// UnknownConfigurationOptionError is returned when the facts contain a name which does not
+// belong to any ConfigurationOption.
+type UnknownConfigurationOptionError struct {
+	// Name is the unknown ConfigurationOption.Name.
+	Name string
+}
+
+func (err *UnknownConfigurationOptionError) Error() string {
+	return fmt.Sprintf("unknown configuration option %s", err.Name)
+}
+
+// ConfigurationOptionTypeError is returned when the value of a fact cannot be converted
+// to the type of the corresponding ConfigurationOption.
+type ConfigurationOptionTypeError struct {
+	// Option is the configuration option which was assigned the wrong value.
+	Option ConfigurationOption
+	// Value is the rejected value.
+	Value interface{}
+}
+
+func (err *ConfigurationOptionTypeError) Error() string {
+	typeName := err.Option.Type.String()
+	switch err.Option.Type {
+	case BoolConfigurationOption:
+		typeName = "bool"
+	case StringsConfigurationOption:
+		typeName = "list of strings"
+	}
+	return fmt.Sprintf("configuration option %s must be of type %s, got %v (%T)",
+		err.Option.Name, typeName, err.Value, err.Value)
+}
+
 // PipelineItem is the interface for all the units in the Git commits analysis pipeline.
 type PipelineItem interface {
 	// Name returns the name of the analysis.
@@ -128,6 +215,31 @@ type FeaturedPipelineItem interface {
 	Features() []string
 }
 
+// SerializationFormat selects the encoding of the analysis results in LeafPipelineItem.Serialize().
+type SerializationFormat int
+
+const (
+	// YAMLFormat is the human-readable YAML output.
+	YAMLFormat SerializationFormat = iota
+	// ProtobufFormat is the compact binary Protocol Buffers output.
+	ProtobufFormat
+	// JSONFormat is the JSON output which can be consumed with standard parsers.
+	JSONFormat
+)
+
+// String returns the short name of the format.
+func (format SerializationFormat) String() string {
+	switch format {
+	case YAMLFormat:
+		return "yaml"
+	case ProtobufFormat:
+		return "pb"
+	case JSONFormat:
+		return "json"
+	}
+	return fmt.Sprintf("SerializationFormat(%d)", int(format))
+}
+
 // LeafPipelineItem corresponds to the top level pipeline items which produce the end results.
 type LeafPipelineItem interface {
 	PipelineItem
@@ -139,8 +251,8 @@ type LeafPipelineItem interface {
 	Description() string
 	// Finalize returns the result of the analysis.
 	Finalize() interface{}
-	// Serialize encodes the object returned by Finalize() to YAML or Protocol Buffers.
-	Serialize(result interface{}, binary bool, writer io.Writer) error
+	// Serialize encodes the object returned by Finalize() to YAML, JSON or Protocol Buffers.
+	Serialize(result interface{}, format SerializationFormat, writer io.Writer) error
 }
 
 // ResultMergeablePipelineItem specifies the methods to combine several analysis results together.
@@ -152,6 +264,15 @@ type ResultMergeablePipelineItem interface {
 	MergeResults(r1, r2 interface{}, c1, c2 *CommonAnalysisResult) interface{}
 }
 
+// TextDeserializablePipelineItem is the interface for ResultMergeablePipelineItem-s which are able
+// to read back their YAML and JSON output, so that the results can be converted between the formats.
+type TextDeserializablePipelineItem interface {
+	ResultMergeablePipelineItem
+	// DeserializeText loads the result from the text written by Serialize() with YAMLFormat or
+	// JSONFormat. The YAML text is the standalone document nested under the item's name.
+	DeserializeText(text []byte, format SerializationFormat) (interface{}, error)
+}
+
 // HibernateablePipelineItem is the interface to allow pipeline items to be frozen (compacted, unloaded)
 // while they are not needed in the hosting branch.
 type HibernateablePipelineItem interface {
@@ -162,6 +283,28 @@ type HibernateablePipelineItem interface {
 	Boot() error
 }
 
+// CheckpointablePipelineItem is the interface to allow pipeline items to save their intermediate
+// state on disk and to restore it later, so that Pipeline.Run() is able to resume after a crash.
+type CheckpointablePipelineItem interface {
+	PipelineItem
+	// SaveState writes the state which is sufficient to continue Consume()-ing the commits.
+	// The states are only taken when all the merge commits are fully consumed, so the transient
+	// per-merge state, e.g. OneShotMergeProcessor, does not have to be saved.
+	SaveState(writer io.Writer) error
+	// LoadState restores the state previously written by SaveState(). It is called after Initialize().
+	LoadState(reader io.Reader) error
+}
+
+// SequentialPipelineItem is the interface for pipeline items which must not Consume() commits
+// concurrently even if Pipeline.Workers allows that. Such items see the commits strictly in the
+// order of the execution plan. Typically, these are the items whose clones share the state, e.g.
+// forked with ForkSamePipelineItem().
+type SequentialPipelineItem interface {
+	PipelineItem
+	// Sequential returns true if Consume() calls must be serialized in the execution plan order.
+	Sequential() bool
+}
+
 // CommonAnalysisResult holds the information which is always extracted at Pipeline.Run().
 type CommonAnalysisResult struct {
 	// BeginTime is the time of the first commit in the analysed sequence.
@@ -174,6 +317,12 @@ type CommonAnalysisResult struct {
 	RunTime time.Duration
 	// RunTimePerItem is the time elapsed by each PipelineItem.
 	RunTimePerItem map[string]float64
+	// Repository is the name of the analysed repository. It is set only for the results which
+	// were loaded from Protocol Buffers.
+	Repository string
+	// Signatures describes the commit signatures which identify the developers,
+	// see FactPipelineSignatures. Empty means unknown.
+	Signatures string
 }
 
 // Copy produces a deep clone of the object.
@@ -198,7 +347,7 @@ func (car *CommonAnalysisResult) EndTimeAsTime() time.Time {
 
 // Merge combines the CommonAnalysisResult with an other one.
 // We choose the earlier BeginTime, the later EndTime, sum the number of commits and the
-// elapsed run times.
+// elapsed run times, and join the repository names and the signatures.
 func (car *CommonAnalysisResult) Merge(other *CommonAnalysisResult) {
 	if car.EndTime == 0 || other.BeginTime == 0 {
 		panic("Merging with an uninitialized CommonAnalysisResult")
@@ -214,6 +363,25 @@ func (car *CommonAnalysisResult) Merge(other *CommonAnalysisResult) {
 	for key, val := range other.RunTimePerItem {
 		car.RunTimePerItem[key] += val
 	}
+	car.Repository = joinNames(car.Repository, other.Repository)
+	car.Signatures = joinNames(car.Signatures, other.Signatures)
+}
+
+// joinNames appends the name to the list of the names separated with " & " unless it is
+// already there.
+func joinNames(names, name string) string {
+	if names == "" {
+		return name
+	}
+	if name == "" {
+		return names
+	}
+	for _, existing := range strings.Split(names, " & ") {
+		if existing == name {
+			return names
+		}
+	}
+	return names + " & " + name
 }
 
 // FillMetadata copies the data to a Protobuf message.
@@ -223,6 +391,7 @@ func (car *CommonAnalysisResult) FillMetadata(meta *pb.Metadata) *pb.Metadata {
 	meta.Commits = int32(car.CommitsNumber)
 	meta.RunTime = car.RunTime.Nanoseconds() / 1e6
 	meta.RunTimePerItem = car.RunTimePerItem
+	meta.Signatures = car.Signatures
 	return meta
 }
 
@@ -237,7 +406,35 @@ func MetadataToCommonAnalysisResult(meta *Metadata) *CommonAnalysisResult {
 		CommitsNumber:  int(meta.Commits),
 		RunTime:        time.Duration(meta.RunTime * 1e6),
 		RunTimePerItem: meta.RunTimePerItem,
+		Repository:     meta.Repository,
+		Signatures:     meta.Signatures,
+	}
+}
+
+// CommitsRange selects the part of the history which Pipeline.Commits() returns.
+// It mirrors `git log --since=Since --until=Until From..To`.
+type CommitsRange struct {
+	// From excludes the specified revision together with all its ancestors. Empty string
+	// does not exclude anything.
+	From string
+	// To is the revision to start the traversal from. Empty string means HEAD.
+	To string
+	// Since excludes the commits which were committed before this time. Zero disables.
+	Since time.Time
+	// Until excludes the commits which were committed after this time. Zero disables.
+	Until time.Time
+}
+
+// includes returns true if the commit's committer time is inside [Since, Until].
+func (cr CommitsRange) includes(commit *object.Commit) bool {
+	when := commit.Committer.When
+	if !cr.Since.IsZero() && when.Before(cr.Since) {
+		return false
+	}
+	if !cr.Until.IsZero() && when.After(cr.Until) {
+		return false
 	}
+	return true
 }
 
 // Pipeline is the core Hercules entity which carries several PipelineItems and executes them.
@@ -248,10 +445,33 @@ type Pipeline struct {
 	// second is the total number of steps and the third is some description of the current action.
 	OnProgress func(int, int, string)
 
+	// OnEvent is the callback which is invoked in Run() on every ProgressEvent. It is never
+	// called concurrently, even if Workers is greater than 1.
+	OnEvent func(ProgressEvent)
+
 	// HibernationDistance is the minimum number of actions between two sequential usages of
 	// a branch to activate the hibernation optimization (cpu-memory trade-off). 0 disables.
 	HibernationDistance int
 
+	// Workers is the maximum number of concurrent Consume() calls on independent branches.
+	// 0 and 1 mean the sequential execution. It must be set before Initialize().
+	Workers int
+
+	// CheckpointDirectory is the path to the directory where to periodically save the state of
+	// the pipeline. Empty string disables checkpoints.
+	CheckpointDirectory string
+
+	// CheckpointInterval is the minimum number of commits between two sequential checkpoints.
+	CheckpointInterval int
+
+	// IncrementalDirectory is the path to the directory with the state of the previous analysis.
+	// Run() continues from that state and saves the new state there when it finishes.
+	// Empty string disables the incremental analysis.
+	IncrementalDirectory string
+
+	// Range limits the commits returned by Commits(). The zero value selects the whole history.
+	Range CommitsRange
+
 	// DryRun indicates whether the items are not executed.
 	DryRun bool
 
@@ -264,6 +484,10 @@ type Pipeline struct {
 	// Repository points to the analysed Git repository struct from go-git.
 	repository *git.Repository
 
+	// The copy of repository with the synchronized storage which the items receive if Workers
+	// is greater than 1, nil otherwise.
+	sharedRepository *git.Repository
+
 	// Items are the registered building blocks in the pipeline. The order defines the
 	// execution sequence.
 	items []PipelineItem
@@ -273,6 +497,18 @@ type Pipeline struct {
 
 	// Feature flags which enable the corresponding items.
 	features map[string]bool
+
+	// The commit signatures which identify the developers, see FactPipelineSignatures.
+	signatures string
+
+	// The values of the items' configuration options which are saved in checkpoints.
+	checkpointFacts map[string]interface{}
+
+	// The checkpoint to resume Run() from.
+	resumeState *checkpoint
+
+	// The state of the previous analysis to continue from.
+	incrementalState *checkpoint
 }
 
 const (
@@ -297,20 +533,70 @@ const (
 	// ConfigPipelinePrintActions is the name of the Pipeline configuration option (Pipeline.Initialize())
 	// which enables printing the taken actions of the execution plan to stderr.
 	ConfigPipelinePrintActions = "Pipeline.PrintActions"
-	// DependencyCommit is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// ConfigPipelineWorkers is the name of the Pipeline configuration option (Pipeline.Initialize())
+	// which sets the maximum number of concurrent Consume() calls on independent branches.
+	// 0 and 1 mean the sequential execution.
+	ConfigPipelineWorkers = "Pipeline.Workers"
+	// ConfigPipelineCheckpointDirectory is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which sets the directory where to periodically save the state.
+	ConfigPipelineCheckpointDirectory = "Pipeline.CheckpointDirectory"
+	// ConfigPipelineCheckpointInterval is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which sets the minimum number of commits between two checkpoints.
+	ConfigPipelineCheckpointInterval = "Pipeline.CheckpointInterval"
+	// ConfigPipelineResume is the name of the Pipeline configuration option (Pipeline.Initialize())
+	// which makes Run() continue from the checkpoint in the specified directory.
+	ConfigPipelineResume = "Pipeline.Resume"
+	// ConfigPipelineIncremental is the name of the Pipeline configuration option
+	// (Pipeline.Initialize()) which makes Run() analyse only the commits added since the state
+	// in the specified directory was saved, and update that state.
+	ConfigPipelineIncremental = "Pipeline.Incremental"
+	// FactPipelineSignatures is the name of the fact which PipelineItem-s insert in Configure()
+	// to describe the commit signatures which identify the developers, e.g. "author".
+	// Run() copies it to CommonAnalysisResult.Signatures.
+	FactPipelineSignatures = "Pipeline.Signatures"
+	// DefaultCheckpointInterval is the default value of ConfigPipelineCheckpointInterval.
+	DefaultCheckpointInterval = 1000
+	// DependencyCommit is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It corresponds to the currently analyzed commit.
 	DependencyCommit = "commit"
-	// DependencyIndex is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// DependencyIndex is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It corresponds to the currently analyzed commit's index.
 	DependencyIndex = "index"
-	// DependencyIsMerge is the name of one of the three items in `deps` supplied to PipelineItem.Consume()
+	// DependencyIsMerge is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
 	// which always exists. It indicates whether the analyzed commit is a merge commit.
 	// Checking the number of parents is not correct - we remove the back edges during the DAG simplification.
 	DependencyIsMerge = "is_merge"
+	// DependencyContext is the name of one of the four items in `deps` supplied to PipelineItem.Consume()
+	// which always exists. It is the context.Context of Pipeline.RunContext(), the items which
+	// make long calls should respect its cancellation.
+	DependencyContext = "context"
 	// MessageFinalize is the status text reported before calling LeafPipelineItem.Finalize()-s.
 	MessageFinalize = "finalize"
 )
 
+// InterruptedError is returned by Pipeline.RunContext() when the context is done before all
+// the commits are analysed. The results returned together with it are partial.
+type InterruptedError struct {
+	// Err is the error of the context: context.Canceled or context.DeadlineExceeded.
+	Err error
+	// Commits is the number of analysed commits.
+	Commits int
+}
+
+func (err *InterruptedError) Error() string {
+	return fmt.Sprintf("the analysis was interrupted after %d commits: %v", err.Commits, err.Err)
+}
+
+// Cause returns the error of the context.
+func (err *InterruptedError) Cause() error {
+	return err.Err
+}
+
+// Unwrap returns the error of the context.
+func (err *InterruptedError) Unwrap() error {
+	return err.Err
+}
+
 // NewPipeline initializes a new instance of Pipeline struct.
 func NewPipeline(repository *git.Repository) *Pipeline {
 	return &Pipeline{
@@ -432,15 +718,194 @@ func (pipeline *Pipeline) Len() int {
 // Commits returns the list of commits from the history similar to `git log` over the HEAD.
 // `firstParent` specifies whether to leave only the first parent after each merge
 // (`git log --first-parent`) - effectively decreasing the accuracy but increasing performance.
+// Pipeline.Range limits the returned commits. If some commits are skipped by the time
+// limits, the parents of the remaining commits are rewritten to skip them as well, so that
+// the history stays connected.
 func (pipeline *Pipeline) Commits(firstParent bool) ([]*object.Commit, error) {
+	head, err := pipeline.resolveRangeEnd()
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.collectCommits([]plumbing.Hash{head}, firstParent)
+}
+
+// CommitsFromRefs is the same as Commits() but starts from the specified references instead
+// of HEAD. Each item in `refs` is either a revision, e.g. a branch or a tag name, or a glob
+// pattern which is matched against the full and the short reference names, e.g. "release-*" or
+// "refs/remotes/origin/*". "*" selects all the references, similar to `git log --all`.
+// The commits which are reachable from several references are returned once.
+// Pipeline.Range.To must be empty.
+func (pipeline *Pipeline) CommitsFromRefs(refs []string, firstParent bool) ([]*object.Commit, error) {
+	if pipeline.Range.To != "" {
+		return nil, errors.New("the references cannot be combined with Range.To")
+	}
+	heads, err := pipeline.resolveRefs(refs)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.collectCommits(heads, firstParent)
+}
+
+// collectCommits traverses the history from the specified heads and applies Pipeline.Range.
+func (pipeline *Pipeline) collectCommits(
+	heads []plumbing.Hash, firstParent bool) ([]*object.Commit, error) {
 	var result []*object.Commit
 	repository := pipeline.repository
+	// seen contains the commits which must not be traversed again
+	seen := map[plumbing.Hash]bool{}
+	if pipeline.Range.From != "" {
+		from, err := repository.ResolveRevision(plumbing.Revision(pipeline.Range.From))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve %s", pipeline.Range.From)
+		}
+		cit, err := repository.Log(&git.LogOptions{From: *from})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to collect the history of %s", pipeline.Range.From)
+		}
+		defer cit.Close()
+		err = cit.ForEach(func(commit *object.Commit) error {
+			seen[commit.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to collect the history of %s", pipeline.Range.From)
+		}
+	}
+
+	for _, head := range heads {
+		commit, err := repository.CommitObject(head)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to load commit %s", head.String())
+		}
+		if firstParent {
+			var chain []*object.Commit
+			// the first parent matches the head
+			for ; err != io.EOF; commit, err = commit.Parents().Next() {
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to collect the commit history")
+				}
+				if seen[commit.Hash] {
+					break
+				}
+				seen[commit.Hash] = true
+				chain = append(chain, commit)
+			}
+			// reverse the order
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+			result = append(result, chain...)
+			continue
+		}
+		// the same as repository.Log() but stops at the already traversed commits
+		cit := object.NewCommitPreorderIter(commit, seen, nil)
+		err = cit.ForEach(func(commit *object.Commit) error {
+			seen[commit.Hash] = true
+			result = append(result, commit)
+			return nil
+		})
+		cit.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to collect the commit history")
+		}
+	}
+	return pipeline.Range.selectTime(result), nil
+}
+
+// resolveRefs returns the commit hashes which the references point to.
+func (pipeline *Pipeline) resolveRefs(refs []string) ([]plumbing.Hash, error) {
+	repository := pipeline.repository
+	var heads []plumbing.Hash
+	added := map[plumbing.Hash]bool{}
+	addHead := func(hash plumbing.Hash) {
+		if !added[hash] {
+			added[hash] = true
+			heads = append(heads, hash)
+		}
+	}
+	for _, ref := range refs {
+		if !strings.ContainsAny(ref, "*?[") {
+			hash, err := repository.ResolveRevision(plumbing.Revision(ref))
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to resolve %s", ref)
+			}
+			addHead(*hash)
+			continue
+		}
+		iter, err := repository.References()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list the references")
+		}
+		matched := false
+		err = iter.ForEach(func(reference *plumbing.Reference) error {
+			if reference.Type() != plumbing.HashReference {
+				return nil
+			}
+			name := reference.Name()
+			if ref != "*" && !matchRef(ref, name.String()) && !matchRef(ref, name.Short()) {
+				return nil
+			}
+			hash, ok := peelToCommit(repository, reference.Hash())
+			if !ok {
+				// tags can point to trees and blobs
+				return nil
+			}
+			matched = true
+			addHead(hash)
+			return nil
+		})
+		iter.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list the references")
+		}
+		if !matched {
+			return nil, errors.Errorf("no references match %s", ref)
+		}
+	}
+	if len(heads) == 0 {
+		return nil, errors.New("no references were specified")
+	}
+	return heads, nil
+}
+
+// matchRef returns true if the reference name matches the glob pattern.
+func matchRef(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// peelToCommit returns the commit which the hash points to, dereferencing annotated tags.
+func peelToCommit(repository *git.Repository, hash plumbing.Hash) (plumbing.Hash, bool) {
+	if _, err := repository.CommitObject(hash); err == nil {
+		return hash, true
+	}
+	tag, err := repository.TagObject(hash)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	commit, err := tag.Commit()
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	return commit.Hash, true
+}
+
+// resolveRangeEnd returns the hash of the commit to start Commits() from.
+func (pipeline *Pipeline) resolveRangeEnd() (plumbing.Hash, error) {
+	repository := pipeline.repository
+	if pipeline.Range.To != "" {
+		to, err := repository.ResolveRevision(plumbing.Revision(pipeline.Range.To))
+		if err != nil {
+			return plumbing.ZeroHash, errors.Wrapf(err, "unable to resolve %s", pipeline.Range.To)
+		}
+		return *to, nil
+	}
 	head, err := repository.Head()
 	if err != nil {
 		if err == plumbing.ErrReferenceNotFound {
 			refs, errr := repository.References()
 			if errr != nil {
-				return nil, errors.Wrap(errr, "unable to list the references")
+				return plumbing.ZeroHash, errors.Wrap(errr, "unable to list the references")
 			}
 			refs.ForEach(func(ref *plumbing.Reference) error {
 				if strings.HasPrefix(ref.Name().String(), "refs/heads/HEAD/") {
@@ -451,38 +916,54 @@ func (pipeline *Pipeline) Commits(firstParent bool) ([]*object.Commit, error) {
 			})
 		}
 		if head == nil && err != nil {
-			return nil, errors.Wrap(err, "unable to collect the commit history")
+			return plumbing.ZeroHash, errors.Wrap(err, "unable to collect the commit history")
 		}
 	}
+	return head.Hash(), nil
+}
 
-	if firstParent {
-		commit, err := repository.CommitObject(head.Hash())
-		if err != nil {
-			panic(err)
-		}
-		// the first parent matches the head
-		for ; err != io.EOF; commit, err = commit.Parents().Next() {
-			if err != nil {
-				panic(err)
-			}
-			result = append(result, commit)
-		}
-		// reverse the order
-		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
-			result[i], result[j] = result[j], result[i]
-		}
-		return result, nil
+// selectTime leaves only the commits which were committed inside [Since, Until] and
+// reconnects them through the skipped ones.
+func (cr CommitsRange) selectTime(commits []*object.Commit) []*object.Commit {
+	if cr.Since.IsZero() && cr.Until.IsZero() {
+		return commits
 	}
-	cit, err := repository.Log(&git.LogOptions{From: head.Hash()})
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to collect the commit history")
+	walked := map[plumbing.Hash]*object.Commit{}
+	var selected []*object.Commit
+	for _, commit := range commits {
+		walked[commit.Hash] = commit
+		if cr.includes(commit) {
+			selected = append(selected, commit)
+		}
 	}
-	defer cit.Close()
-	cit.ForEach(func(commit *object.Commit) error {
-		result = append(result, commit)
-		return nil
-	})
-	return result, nil
+	return rewriteParents(walked, selected)
+}
+
+// UnsatisfiedDependencyError is returned from Pipeline.Initialize() when none of the items
+// provides the key which another item requires.
+type UnsatisfiedDependencyError struct {
+	// Item is the name of the PipelineItem which requires Key.
+	Item string
+	// Key is the missing entity, see PipelineItem.Requires().
+	Key string
+}
+
+func (err *UnsatisfiedDependencyError) Error() string {
+	return fmt.Sprintf("unsatisfied dependency: %s requires %s", err.Item, err.Key)
+}
+
+// AmbiguousProviderError is returned from Pipeline.Initialize() when too many items provide
+// the same key and the DAG cannot be resolved.
+type AmbiguousProviderError struct {
+	// Key is the entity which is provided several times, see PipelineItem.Provides().
+	Key string
+	// Candidates are the names of the nodes in the DAG which provide Key.
+	Candidates []string
+}
+
+func (err *AmbiguousProviderError) Error() string {
+	return fmt.Sprintf("ambiguous graph: %s is provided by %s",
+		err.Key, strings.Join(err.Candidates, ", "))
 }
 
 type sortablePipelineItems []PipelineItem
@@ -499,7 +980,7 @@ func (items sortablePipelineItems) Swap(i, j int) {
 	items[i], items[j] = items[j], items[i]
 }
 
-func (pipeline *Pipeline) resolve(dumpPath string) {
+func (pipeline *Pipeline) resolve(dumpPath string) error {
 	graph := toposort.NewGraph()
 	sort.Sort(sortablePipelineItems(pipeline.items))
 	name2item := map[string]PipelineItem{}
@@ -523,21 +1004,10 @@ func (pipeline *Pipeline) resolve(dumpPath string) {
 			graph.AddNode(key)
 			if graph.AddEdge(name, key) > 1 {
 				if ambiguousMap[key] != nil {
-					fmt.Fprintln(os.Stderr, "Pipeline:")
-					for _, item2 := range pipeline.items {
-						if item2 == item {
-							fmt.Fprint(os.Stderr, "> ")
-						}
-						fmt.Fprint(os.Stderr, item2.Name(), " [")
-						for i, key2 := range item2.Provides() {
-							fmt.Fprint(os.Stderr, key2)
-							if i < len(item.Provides())-1 {
-								fmt.Fprint(os.Stderr, ", ")
-							}
-						}
-						fmt.Fprintln(os.Stderr, "]")
-					}
-					panic("Failed to resolve pipeline dependencies: ambiguous graph.")
+					candidates := graph.FindParents(key)
+					sort.Strings(candidates)
+					return &AmbiguousProviderError{
+						Key: key[1 : len(key)-1], Candidates: candidates}
 				}
 				ambiguousMap[key] = graph.FindParents(key)
 			}
@@ -554,7 +1024,7 @@ func (pipeline *Pipeline) resolve(dumpPath string) {
 		for _, key := range item.Requires() {
 			key = "[" + key + "]"
 			if graph.AddEdge(key, name) == 0 {
-				log.Panicf("Unsatisfied dependency: %s -> %s", key, item.Name())
+				return &UnsatisfiedDependencyError{Item: item.Name(), Key: key[1 : len(key)-1]}
 			}
 		}
 	}
@@ -607,7 +1077,8 @@ func (pipeline *Pipeline) resolve(dumpPath string) {
 	}
 	strplan, ok := graph.Toposort()
 	if !ok {
-		panic("Failed to resolve pipeline dependencies: unable to topologically sort the items.")
+		return errors.New("failed to resolve pipeline dependencies: " +
+			"unable to topologically sort the items")
 	}
 	pipeline.items = make([]PipelineItem, 0, len(pipeline.items))
 	for _, key := range strplan {
@@ -618,15 +1089,21 @@ func (pipeline *Pipeline) resolve(dumpPath string) {
 	if dumpPath != "" {
 		// If there is a floating difference, uncomment this:
 		// fmt.Fprint(os.Stderr, graphCopy.DebugDump())
-		ioutil.WriteFile(dumpPath, []byte(graphCopy.Serialize(strplan)), 0666)
+		err := ioutil.WriteFile(dumpPath, []byte(graphCopy.Serialize(strplan)), 0666)
+		if err != nil {
+			return errors.Wrap(err, "failed to write the DAG")
+		}
 		absPath, _ := filepath.Abs(dumpPath)
 		log.Printf("Wrote the DAG to %s\n", absPath)
 	}
+	return nil
 }
 
 // Initialize prepares the pipeline for the execution (Run()). This function
 // resolves the execution DAG, Configure()-s and Initialize()-s the items in it in the
 // topological dependency order. `facts` are passed inside Configure(). They are mutable.
+// If the DAG cannot be resolved, the returned error is *UnsatisfiedDependencyError or
+// *AmbiguousProviderError.
 func (pipeline *Pipeline) Initialize(facts map[string]interface{}) error {
 	cleanReturn := false
 	defer func() {
@@ -644,18 +1121,99 @@ func (pipeline *Pipeline) Initialize(facts map[string]interface{}) error {
 		var err error
 		facts[ConfigPipelineCommits], err = pipeline.Commits(false)
 		if err != nil {
-			log.Panicf("failed to list the commits: %v", err)
+			cleanReturn = true
+			return errors.Wrap(err, "failed to list the commits")
 		}
 	}
 	pipeline.PrintActions, _ = facts[ConfigPipelinePrintActions].(bool)
 	if val, exists := facts[ConfigPipelineHibernationDistance].(int); exists {
 		if val < 0 {
-			log.Panicf("--hibernation-distance cannot be negative (got %d)", val)
+			cleanReturn = true
+			return errors.Errorf("--hibernation-distance cannot be negative (got %d)", val)
 		}
 		pipeline.HibernationDistance = val
 	}
+	if val, exists := facts[ConfigPipelineWorkers].(int); exists {
+		if val < 0 {
+			cleanReturn = true
+			return errors.Errorf("--workers cannot be negative (got %d)", val)
+		}
+		pipeline.Workers = val
+	}
+	if val, exists := facts[ConfigPipelineCheckpointDirectory].(string); exists {
+		pipeline.CheckpointDirectory = val
+	}
+	if val, exists := facts[ConfigPipelineCheckpointInterval].(int); exists {
+		if val < 0 {
+			cleanReturn = true
+			return errors.Errorf("--checkpoint-interval cannot be negative (got %d)", val)
+		}
+		pipeline.CheckpointInterval = val
+	}
+	if pipeline.CheckpointInterval == 0 {
+		pipeline.CheckpointInterval = DefaultCheckpointInterval
+	}
 	dumpPath, _ := facts[ConfigPipelineDAGPath].(string)
-	pipeline.resolve(dumpPath)
+	if err := pipeline.resolve(dumpPath); err != nil {
+		cleanReturn = true
+		return err
+	}
+	pipeline.resumeState = nil
+	if resumeDir, _ := facts[ConfigPipelineResume].(string); resumeDir != "" {
+		if err := pipeline.checkCheckpointable(); err != nil {
+			cleanReturn = true
+			return errors.Wrap(err, "cannot resume")
+		}
+		state, err := pipeline.loadCheckpoint(resumeDir, CheckpointFileName)
+		if err != nil {
+			cleanReturn = true
+			return err
+		}
+		for key, val := range state.Facts {
+			facts[key] = val
+		}
+		pipeline.resumeState = state
+		if pipeline.CheckpointDirectory == "" {
+			pipeline.CheckpointDirectory = resumeDir
+		}
+	}
+	if pipeline.CheckpointDirectory != "" {
+		if err := pipeline.checkCheckpointable(); err != nil {
+			cleanReturn = true
+			return errors.Wrap(err, "cannot save checkpoints")
+		}
+	}
+	if val, exists := facts[ConfigPipelineIncremental].(string); exists {
+		pipeline.IncrementalDirectory = val
+	}
+	pipeline.incrementalState = nil
+	if pipeline.IncrementalDirectory != "" {
+		if pipeline.CheckpointDirectory != "" {
+			cleanReturn = true
+			return errors.New("the incremental analysis cannot be combined with checkpoints")
+		}
+		if err := pipeline.checkCheckpointable(); err != nil {
+			cleanReturn = true
+			return errors.Wrap(err, "cannot analyse incrementally")
+		}
+		state, err := pipeline.loadCheckpoint(pipeline.IncrementalDirectory, IncrementalStateFileName)
+		if err != nil && !os.IsNotExist(err) {
+			cleanReturn = true
+			return err
+		}
+		// the first run starts from scratch
+		if err == nil {
+			if state.Position != state.PlanLength {
+				cleanReturn = true
+				return errors.Errorf("the state in %s is incomplete", pipeline.IncrementalDirectory)
+			}
+			for key, val := range state.Facts {
+				facts[key] = val
+			}
+			pipeline.incrementalState = state
+		}
+	}
+	pipeline.checkpointFacts = pipeline.collectCheckpointFacts(facts)
 	if dumpPlan, exists := facts[ConfigPipelineDumpPlan].(bool); exists {
 		pipeline.DumpPlan = dumpPlan
 	}
@@ -673,8 +1231,16 @@ func (pipeline *Pipeline) Initialize(facts map[string]interface{}) error {
 			return errors.Wrapf(err, "%s failed to configure", item.Name())
 		}
 	}
+	pipeline.signatures, _ = facts[FactPipelineSignatures].(string)
+	repository := pipeline.repository
+	pipeline.sharedRepository = nil
+	if pipeline.Workers > 1 {
+		// go-git repositories are not safe for concurrent use
+		pipeline.sharedRepository = synchronizeRepository(repository)
+		repository = pipeline.sharedRepository
+	}
 	for _, item := range pipeline.items {
-		err := item.Initialize(pipeline.repository)
+		err := item.Initialize(repository)
 		if err != nil {
 			cleanReturn = true
 			return errors.Wrapf(err, "%s failed to initialize", item.Name())
@@ -688,13 +1254,31 @@ func (pipeline *Pipeline) Initialize(facts map[string]interface{}) error {
 	return nil
 }
 
-// Run method executes the pipeline.
+// Run method executes the pipeline. It is the same as RunContext() which is never cancelled.
+func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]interface{}, error) {
+	return pipeline.RunContext(context.Background(), commits)
+}
+
+// RunContext method executes the pipeline.
+//
+// `ctx` is checked between the actions of the execution plan and is passed to the items
+// in `deps` as DependencyContext. If it is done before all the commits are analysed, the leaves
+// which can finalize return the partial results, CommonAnalysisResult reflects the analysed
+// commits and the returned error is *InterruptedError. The checkpoint is saved if enabled.
 //
 // `commits` is a slice with the git commits to analyse. Multiple branches are supported.
 //
+// If CheckpointDirectory is set, the state of the pipeline is periodically saved there and
+// a subsequent Run() with the same commits may continue from it, see ConfigPipelineResume.
+//
+// If IncrementalDirectory is set and contains the state of the previous analysis, only the
+// commits which are not reachable from the last analysed commit are consumed. The final state
+// is saved there.
+//
 // Returns the mapping from each LeafPipelineItem to the corresponding analysis result.
 // There is always a "nil" record with CommonAnalysisResult.
-func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]interface{}, error) {
+func (pipeline *Pipeline) RunContext(
+	ctx context.Context, commits []*object.Commit) (map[LeafPipelineItem]interface{}, error) {
 	startRunTime := time.Now()
 	cleanReturn := false
 	defer func() {
@@ -709,7 +1293,36 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 	if onProgress == nil {
 		onProgress = func(int, int, string) {}
 	}
-	plan := prepareRunPlan(commits, pipeline.HibernationDistance, pipeline.DumpPlan)
+	onEvent := pipeline.eventEmitter()
+	commitsNumber := len(commits)
+	incremental := pipeline.incrementalState
+	if pipeline.DryRun {
+		incremental = nil
+	}
+	if incremental != nil {
+		var err error
+		commits, err = newIncrementalCommits(commits, incremental.Commit)
+		if err != nil {
+			return nil, err
+		}
+		// the rest of the commits were counted in the previous run
+		commitsNumber = incremental.CommitsNumber + len(commits)
+		err = pipeline.restoreCheckpoint(incremental)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pipeline.sharedRepository != nil && !pipeline.DryRun {
+		var err error
+		commits, err = shareCommits(pipeline.sharedRepository, commits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var plan []runAction
+	if len(commits) > 0 {
+		plan = prepareRunPlan(commits, pipeline.HibernationDistance, pipeline.DumpPlan)
+	}
 	progressSteps := len(plan) + 2
 	branches := map[int][]PipelineItem{}
 	// we will need rootClone if there is more than one root branch
@@ -753,7 +1366,121 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 	}
 
 	commitIndex := 0
-	for index, step := range plan {
+	startIndex := 0
+	lastCheckpoint := 0
+	var previousRunTime time.Duration
+	if state := pipeline.resumeState; state != nil && !pipeline.DryRun {
+		if state.PlanLength != len(plan) || state.Position >= len(plan) ||
+			plan[state.Position].Action != runActionCommit ||
+			plan[state.Position].Commit.Hash.String() != state.Commit {
+			return nil, errors.New("the checkpoint does not match the analysed commits")
+		}
+		err := pipeline.restoreCheckpoint(state)
+		if err != nil {
+			return nil, err
+		}
+		branches[state.Branch] = pipeline.items
+		startIndex = state.Position
+		commitIndex = state.CommitIndex
+		lastCheckpoint = commitIndex
+		newestTime = state.NewestTime
+		previousRunTime = state.RunTime
+		for key, val := range state.RunTimePerItem {
+			runTimePerItem[key] = val
+		}
+	}
+	if incremental != nil {
+		commitIndex = incremental.CommitIndex
+		newestTime = incremental.NewestTime
+		previousRunTime = incremental.RunTime
+		for key, val := range incremental.RunTimePerItem {
+			runTimePerItem[key] = val
+		}
+	}
+	checkpointsEnabled := pipeline.CheckpointDirectory != "" && !pipeline.DryRun
+	saveRunCheckpoint := func(index int) error {
+		var branch int
+		var items []PipelineItem
+		for key, val := range branches {
+			branch, items = key, val
+		}
+		return pipeline.saveCheckpoint(pipeline.CheckpointDirectory, CheckpointFileName, &checkpoint{
+			Position:       index,
+			PlanLength:     len(plan),
+			Commit:         plan[index].Commit.Hash.String(),
+			BeginTime:      plan[0].Commit.Committer.When.Unix(),
+			CommitsNumber:  commitsNumber,
+			Branch:         branch,
+			CommitIndex:    commitIndex,
+			NewestTime:     newestTime,
+			RunTime:        previousRunTime + time.Since(startRunTime),
+			RunTimePerItem: runTimePerItem,
+			Facts:          pipeline.checkpointFacts,
+		}, items)
+	}
+
+	// interruption is the error of the done context
+	var interruption error
+	// dirty indicates that the interrupted commit was partially consumed
+	dirty := false
+	index := startIndex
+execution:
+	for ; index < len(plan); index++ {
+		if err := ctx.Err(); err != nil {
+			interruption = err
+			break
+		}
+		step := plan[index]
+		if checkpointsEnabled && step.Action == runActionCommit && len(branches) == 1 &&
+			commitIndex-lastCheckpoint >= pipeline.CheckpointInterval {
+			err := saveRunCheckpoint(index)
+			if err != nil {
+				return nil, err
+			}
+			lastCheckpoint = commitIndex
+		}
+		if pipeline.sharedRepository != nil && !pipeline.DryRun && step.Action == runActionCommit {
+			end := index + 1
+			for end < len(plan) && plan[end].Action == runActionCommit {
+				if checkpointsEnabled && len(branches) == 1 &&
+					commitIndex+end-index-lastCheckpoint >= pipeline.CheckpointInterval {
+					// stop at the next checkpoint
+					break
+				}
+				end++
+			}
+			if end-index > 1 {
+				segment := plan[index:end]
+				merges := make([]bool, len(segment))
+				for i, step := range segment {
+					if pipeline.PrintActions {
+						printAction(step)
+					}
+					merges[i] = isMerge(index+i, step.Commit.Hash)
+				}
+				processed, err := pipeline.runConcurrently(ctx, segment, index, commitIndex, branches,
+					merges, runTimePerItem, onProgress, onEvent, progressSteps)
+				for _, step := range segment[:processed] {
+					commitTime := step.Commit.Committer.When.Unix()
+					if commitTime > newestTime {
+						newestTime = commitTime
+					}
+				}
+				commitIndex += processed
+				if err != nil {
+					if ctx.Err() == nil {
+						return nil, err
+					}
+					interruption = ctx.Err()
+					dirty = true
+					index += processed
+					break
+				}
+				// fewer commits are processed if the context is done
+				index += processed - 1
+				continue
+			}
+		}
 		onProgress(index+1, progressSteps, step.String())
 		if pipeline.DryRun {
 			continue
@@ -771,12 +1498,26 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 				DependencyCommit:  step.Commit,
 				DependencyIndex:   commitIndex,
 				DependencyIsMerge: isMerge(index, step.Commit.Hash),
+				DependencyContext: ctx,
 			}
+			onEvent(ProgressEvent{
+				Type: ProgressCommitStarted, Step: index + 1, Total: progressSteps,
+				Commit: step.Commit.Hash.String(), Branches: step.Items})
 			for _, item := range branches[firstItem] {
 				startTime := time.Now()
 				update, err := item.Consume(state)
-				runTimePerItem[item.Name()] += time.Now().Sub(startTime).Seconds()
+				elapsed := time.Now().Sub(startTime).Seconds()
+				runTimePerItem[item.Name()] += elapsed
+				onEvent(ProgressEvent{
+					Type: ProgressItemConsumed, Step: index + 1, Total: progressSteps,
+					Commit: step.Commit.Hash.String(), Branches: step.Items,
+					Item: item.Name(), Elapsed: elapsed})
 				if err != nil {
+					if ctx.Err() != nil {
+						interruption = ctx.Err()
+						dirty = true
+						break execution
+					}
 					log.Printf("%s failed on commit #%d (%d) %s\n",
 						item.Name(), commitIndex+1, index+1, step.Commit.Hash.String())
 					return nil, err
@@ -794,12 +1535,19 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 				newestTime = commitTime
 			}
 			commitIndex++
+			onEvent(ProgressEvent{
+				Type: ProgressCommitFinished, Step: index + 1, Total: progressSteps,
+				Commit: step.Commit.Hash.String(), Branches: step.Items})
 		case runActionFork:
 			startTime := time.Now()
 			for i, clone := range cloneItems(branches[firstItem], len(step.Items)-1) {
 				branches[step.Items[i+1]] = clone
 			}
-			runTimePerItem["*.Fork"] += time.Now().Sub(startTime).Seconds()
+			elapsed := time.Now().Sub(startTime).Seconds()
+			runTimePerItem["*.Fork"] += elapsed
+			onEvent(ProgressEvent{
+				Type: ProgressFork, Step: index + 1, Total: progressSteps,
+				Branches: step.Items, Elapsed: elapsed})
 		case runActionMerge:
 			startTime := time.Now()
 			merged := make([][]PipelineItem, len(step.Items))
@@ -807,7 +1555,11 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 				merged[i] = branches[b]
 			}
 			mergeItems(merged)
-			runTimePerItem["*.Merge"] += time.Now().Sub(startTime).Seconds()
+			elapsed := time.Now().Sub(startTime).Seconds()
+			runTimePerItem["*.Merge"] += elapsed
+			onEvent(ProgressEvent{
+				Type: ProgressMerge, Step: index + 1, Total: progressSteps,
+				Branches: step.Items, Elapsed: elapsed})
 		case runActionEmerge:
 			if firstItem == rootBranchIndex {
 				branches[firstItem] = pipeline.items
@@ -817,6 +1569,7 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 		case runActionDelete:
 			delete(branches, firstItem)
 		case runActionHibernate:
+			actionStartTime := time.Now()
 			for _, item := range step.Items {
 				for _, item := range branches[item] {
 					if hi, ok := item.(HibernateablePipelineItem); ok {
@@ -829,7 +1582,11 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 					}
 				}
 			}
+			onEvent(ProgressEvent{
+				Type: ProgressHibernate, Step: index + 1, Total: progressSteps,
+				Branches: step.Items, Elapsed: time.Now().Sub(actionStartTime).Seconds()})
 		case runActionBoot:
+			actionStartTime := time.Now()
 			for _, item := range step.Items {
 				for _, item := range branches[item] {
 					if hi, ok := item.(HibernateablePipelineItem); ok {
@@ -842,29 +1599,117 @@ func (pipeline *Pipeline) Run(commits []*object.Commit) (map[LeafPipelineItem]in
 					}
 				}
 			}
+			onEvent(ProgressEvent{
+				Type: ProgressBoot, Step: index + 1, Total: progressSteps,
+				Branches: step.Items, Elapsed: time.Now().Sub(actionStartTime).Seconds()})
+		}
+	}
+	var beginTime int64
+	if incremental != nil {
+		beginTime = incremental.BeginTime
+	} else {
+		beginTime = plan[0].Commit.Committer.When.Unix()
+	}
+	if interruption != nil {
+		log.Printf("The pipeline was interrupted at step %d/%d: %v", index+1, len(plan), interruption)
+		if checkpointsEnabled && !dirty && len(branches) == 1 && plan[index].Action == runActionCommit {
+			err := saveRunCheckpoint(index)
+			if err != nil {
+				log.Printf("Failed to save the checkpoint: %v", err)
+			}
+		}
+		analysed := map[plumbing.Hash]bool{}
+		for _, step := range plan[:index] {
+			if step.Action == runActionCommit {
+				analysed[step.Commit.Hash] = true
+			}
+		}
+		commitsNumber = len(analysed)
+		if incremental != nil {
+			commitsNumber += incremental.CommitsNumber
+		}
+	} else if pipeline.IncrementalDirectory != "" && !pipeline.DryRun && len(plan) > 0 {
+		var lastCommit string
+		for i := len(plan) - 1; i >= 0 && lastCommit == ""; i-- {
+			if plan[i].Action == runActionCommit {
+				// skip the heads whose branches were deleted
+				if _, alive := branches[plan[i].Items[0]]; alive {
+					lastCommit = plan[i].Commit.Hash.String()
+				}
+			}
+		}
+		err := pipeline.saveCheckpoint(pipeline.IncrementalDirectory, IncrementalStateFileName,
+			&checkpoint{
+				Position:       len(plan),
+				PlanLength:     len(plan),
+				Commit:         lastCommit,
+				BeginTime:      beginTime,
+				CommitsNumber:  commitsNumber,
+				CommitIndex:    commitIndex,
+				NewestTime:     newestTime,
+				RunTime:        previousRunTime + time.Since(startRunTime),
+				RunTimePerItem: runTimePerItem,
+				Facts:          pipeline.checkpointFacts,
+			}, getMasterBranch(branches))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to save the incremental state")
 		}
 	}
 	onProgress(len(plan)+1, progressSteps, MessageFinalize)
 	result := map[LeafPipelineItem]interface{}{}
 	if !pipeline.DryRun {
-		for index, item := range getMasterBranch(branches) {
+		onEvent(ProgressEvent{Type: ProgressFinalize, Step: len(plan) + 1, Total: progressSteps})
+		master := getMasterBranch(branches)
+		if master == nil {
+			// no new commits since the incremental state
+			master = pipeline.items
+		}
+		for index, item := range master {
 			if casted, ok := item.(LeafPipelineItem); ok {
-				result[pipeline.items[index].(LeafPipelineItem)] = casted.Finalize()
+				startTime := time.Now()
+				if interruption == nil {
+					result[pipeline.items[index].(LeafPipelineItem)] = casted.Finalize()
+				} else if partial, err := finalizePartially(casted); err == nil {
+					result[pipeline.items[index].(LeafPipelineItem)] = partial
+				} else {
+					log.Printf("%s cannot finalize the partial result: %v", item.Name(), err)
+				}
+				onEvent(ProgressEvent{
+					Type: ProgressItemFinalized, Step: len(plan) + 1, Total: progressSteps,
+					Item: item.Name(), Elapsed: time.Now().Sub(startTime).Seconds()})
 			}
 		}
 	}
 	onProgress(progressSteps, progressSteps, "")
+	if !pipeline.DryRun {
+		onEvent(ProgressEvent{Type: ProgressDone, Step: progressSteps, Total: progressSteps})
+	}
 	result[nil] = &CommonAnalysisResult{
-		BeginTime:      plan[0].Commit.Committer.When.Unix(),
+		BeginTime:      beginTime,
 		EndTime:        newestTime,
-		CommitsNumber:  len(commits),
-		RunTime:        time.Since(startRunTime),
+		CommitsNumber:  commitsNumber,
+		RunTime:        previousRunTime + time.Since(startRunTime),
 		RunTimePerItem: runTimePerItem,
+		Signatures:     pipeline.signatures,
 	}
 	cleanReturn = true
+	if interruption != nil {
+		return result, &InterruptedError{Err: interruption, Commits: commitsNumber}
+	}
 	return result, nil
 }
 
+// finalizePartially calls Finalize() on the leaf which may be in an inconsistent state after
+// the interruption.
+func finalizePartially(leaf LeafPipelineItem) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+	return leaf.Finalize(), nil
+}
+
 // LoadCommitsFromFile reads the file by the specified FS path and generates the sequence of commits
 // by interpreting each line as a Git commit hash.
 func LoadCommitsFromFile(path string, repository *git.Repository) ([]*object.Commit, error) {
@@ -2,11 +2,15 @@ package core
 
 import (
 	"bytes"
+	"context"
+	"encoding/gob"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -135,7 +139,8 @@ func (item *testPipelineItem) Finalize() interface{} {
 	return item
 }
 
-func (item *testPipelineItem) Serialize(result interface{}, binary bool, writer io.Writer) error {
+func (item *testPipelineItem) Serialize(
+	result interface{}, format SerializationFormat, writer io.Writer) error {
 	return nil
 }
 
@@ -229,10 +234,18 @@ func (item *dependingTestPipelineItem) Finalize() interface{} {
 	return true
 }
 
-func (item *dependingTestPipelineItem) Serialize(result interface{}, binary bool, writer io.Writer) error {
+func (item *dependingTestPipelineItem) Serialize(
+	result interface{}, format SerializationFormat, writer io.Writer) error {
 	return nil
 }
 
+func TestSerializationFormatString(t *testing.T) {
+	assert.Equal(t, "yaml", YAMLFormat.String())
+	assert.Equal(t, "pb", ProtobufFormat.String())
+	assert.Equal(t, "json", JSONFormat.String())
+	assert.Equal(t, "SerializationFormat(10)", SerializationFormat(10).String())
+}
+
 func TestPipelineFacts(t *testing.T) {
 	pipeline := NewPipeline(test.Repository)
 	pipeline.SetFact("fact", "value")
@@ -309,12 +322,25 @@ func TestPipelineRun(t *testing.T) {
 	assert.Equal(t, 1, *item.MergeState)
 	assert.True(t, item.Forked)
 	assert.False(t, *item.Merged)
+	assert.Equal(t, "", common.Signatures)
 	pipeline.RemoveItem(item)
 	result, err = pipeline.Run(commits)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(result))
 }
 
+func TestPipelineRunSignatures(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&testPipelineItem{})
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{FactPipelineSignatures: "committer"}))
+	commits := make([]*object.Commit, 1)
+	commits[0], _ = test.Repository.CommitObject(plumbing.NewHash(
+		"af9ddc0db70f09f3f27b4b98e415592a7485171c"))
+	result, err := pipeline.Run(commits)
+	assert.Nil(t, err)
+	assert.Equal(t, "committer", result[nil].(*CommonAnalysisResult).Signatures)
+}
+
 func TestPipelineRunBranches(t *testing.T) {
 	pipeline := NewPipeline(test.Repository)
 	item := &testPipelineItem{}
@@ -347,6 +373,309 @@ func TestPipelineRunBranches(t *testing.T) {
 	assert.Equal(t, *item.MergeState, 8)
 }
 
+type sequentialTestPipelineItem struct {
+	NoopMerger
+	Consumed   *[]string
+	Fail       string
+	Concurrent bool
+}
+
+func (item *sequentialTestPipelineItem) Name() string {
+	return "Sequential"
+}
+
+func (item *sequentialTestPipelineItem) Provides() []string {
+	return []string{}
+}
+
+func (item *sequentialTestPipelineItem) Requires() []string {
+	return []string{}
+}
+
+func (item *sequentialTestPipelineItem) ListConfigurationOptions() []ConfigurationOption {
+	return []ConfigurationOption{}
+}
+
+func (item *sequentialTestPipelineItem) Configure(facts map[string]interface{}) error {
+	return nil
+}
+
+func (item *sequentialTestPipelineItem) Initialize(repository *git.Repository) error {
+	item.Consumed = &[]string{}
+	return nil
+}
+
+func (item *sequentialTestPipelineItem) Consume(deps map[string]interface{}) (map[string]interface{}, error) {
+	commit := deps[DependencyCommit].(*object.Commit)
+	if commit.Hash.String() == item.Fail {
+		return nil, errors.New("fail")
+	}
+	if item.Concurrent {
+		return map[string]interface{}{}, nil
+	}
+	*item.Consumed = append(*item.Consumed, fmt.Sprintf(
+		"%s %d %v", commit.Hash.String(), deps[DependencyIndex].(int), deps[DependencyIsMerge].(bool)))
+	return map[string]interface{}{}, nil
+}
+
+func (item *sequentialTestPipelineItem) Fork(n int) []PipelineItem {
+	return ForkSamePipelineItem(item, n)
+}
+
+func (item *sequentialTestPipelineItem) Sequential() bool {
+	return !item.Concurrent
+}
+
+func (item *sequentialTestPipelineItem) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(*item.Consumed)
+}
+
+func (item *sequentialTestPipelineItem) LoadState(reader io.Reader) error {
+	return gob.NewDecoder(reader).Decode(item.Consumed)
+}
+
+func TestPipelineRunWorkers(t *testing.T) {
+	run := func(workers int) []string {
+		pipeline := NewPipeline(test.Repository)
+		pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+		seq := &sequentialTestPipelineItem{}
+		pipeline.AddItem(seq)
+		pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+		assert.Nil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineWorkers: workers}))
+		assert.Equal(t, workers, pipeline.Workers)
+		commits, err := pipeline.Commits(false)
+		assert.Nil(t, err)
+		result, err := pipeline.Run(commits)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, len(commits), result[nil].(*CommonAnalysisResult).CommitsNumber)
+		return *seq.Consumed
+	}
+	consumed := run(0)
+	assert.True(t, len(consumed) > 100)
+	for _, workers := range []int{2, 4, 16} {
+		assert.Equal(t, consumed, run(workers), workers)
+	}
+}
+
+func TestPipelineRunWorkersError(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+	pipeline.AddItem(&sequentialTestPipelineItem{Fail: "f30daba81ff2bf0b3ba02a1e1441e74f8a4f6fee"})
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineWorkers: 4}))
+	hashes := []string{
+		"6db8065cdb9bb0758f36a7e75fc72ab95f9e8145",
+		"f30daba81ff2bf0b3ba02a1e1441e74f8a4f6fee",
+		"8a03b5620b1caa72ec9cb847ea88332621e2950a",
+		"dd9dd084d5851d7dc4399fc7dbf3d8292831ebc5",
+		"f4ed0405b14f006c0744029d87ddb3245607587a",
+	}
+	commits := make([]*object.Commit, len(hashes))
+	for i, h := range hashes {
+		var err error
+		commits[i], err = test.Repository.CommitObject(plumbing.NewHash(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := pipeline.Run(commits)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.NotNil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineWorkers: -1}))
+}
+
+func TestPipelineRunCheckpointResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hercules-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	run := func(facts map[string]interface{}, fail string) ([]string, error) {
+		pipeline := NewPipeline(test.Repository)
+		pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+		seq := &sequentialTestPipelineItem{Fail: fail}
+		pipeline.AddItem(seq)
+		assert.Nil(t, pipeline.Initialize(facts))
+		commits, err := pipeline.Commits(false)
+		assert.Nil(t, err)
+		_, err = pipeline.Run(commits)
+		return *seq.Consumed, err
+	}
+	consumed, err := run(map[string]interface{}{}, "")
+	assert.Nil(t, err)
+	partial, err := run(map[string]interface{}{
+		ConfigPipelineCheckpointDirectory: dir,
+		ConfigPipelineCheckpointInterval:  10,
+	}, consumed[len(consumed)/2][:40])
+	assert.NotNil(t, err)
+	assert.True(t, len(partial) < len(consumed))
+	_, err = os.Stat(filepath.Join(dir, CheckpointFileName))
+	assert.Nil(t, err)
+	for _, workers := range []int{0, 4} {
+		resumed, err := run(map[string]interface{}{
+			ConfigPipelineResume:  dir,
+			ConfigPipelineWorkers: workers,
+		}, "")
+		assert.Nil(t, err)
+		assert.Equal(t, consumed, resumed, workers)
+	}
+	// a different pipeline
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&sequentialTestPipelineItem{})
+	err = pipeline.Initialize(map[string]interface{}{ConfigPipelineResume: dir})
+	assert.NotNil(t, err)
+	// different commits
+	pipeline = NewPipeline(test.Repository)
+	pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+	pipeline.AddItem(&sequentialTestPipelineItem{})
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineResume: dir}))
+	commits := make([]*object.Commit, 1)
+	commits[0], _ = test.Repository.CommitObject(plumbing.NewHash(
+		"af9ddc0db70f09f3f27b4b98e415592a7485171c"))
+	_, err = pipeline.Run(commits)
+	assert.NotNil(t, err)
+	// items which do not support checkpoints
+	pipeline = NewPipeline(test.Repository)
+	pipeline.AddItem(&testPipelineItem{})
+	err = pipeline.Initialize(map[string]interface{}{ConfigPipelineCheckpointDirectory: dir})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "does not support checkpoints")
+}
+
+func TestPipelineRunContextCancel(t *testing.T) {
+	run := func(workers int, cancelAt int) ([]string, map[LeafPipelineItem]interface{}, error) {
+		pipeline := NewPipeline(test.Repository)
+		pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+		seq := &sequentialTestPipelineItem{}
+		pipeline.AddItem(seq)
+		assert.Nil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineWorkers: workers}))
+		commits, err := pipeline.Commits(false)
+		assert.Nil(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pipeline.OnProgress = func(step, total int, action string) {
+			if cancelAt > 0 && step >= cancelAt {
+				cancel()
+			}
+		}
+		result, err := pipeline.RunContext(ctx, commits)
+		return *seq.Consumed, result, err
+	}
+	full, _, err := run(0, 0)
+	assert.Nil(t, err)
+	for _, workers := range []int{0, 4} {
+		partial, result, err := run(workers, 10)
+		assert.NotNil(t, err)
+		interrupted, ok := err.(*InterruptedError)
+		assert.True(t, ok)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, context.Canceled, interrupted.Cause())
+		assert.True(t, len(partial) > 0)
+		assert.True(t, len(partial) < len(full))
+		assert.Equal(t, full[:len(partial)], partial)
+		common := result[nil].(*CommonAnalysisResult)
+		assert.Equal(t, interrupted.Commits, common.CommitsNumber)
+		assert.True(t, common.CommitsNumber > 0)
+	}
+	// the context is done before the start
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&testPipelineItem{})
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	commits := make([]*object.Commit, 1)
+	commits[0], _ = test.Repository.CommitObject(plumbing.NewHash(
+		"af9ddc0db70f09f3f27b4b98e415592a7485171c"))
+	result, err := pipeline.RunContext(ctx, commits)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, result[nil].(*CommonAnalysisResult).CommitsNumber)
+	assert.Len(t, result, 2)
+}
+
+func TestPipelineRunIncremental(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hercules-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pipeline := NewPipeline(test.Repository)
+	commits, err := pipeline.Commits(true)
+	assert.Nil(t, err)
+	run := func(facts map[string]interface{}, commits []*object.Commit) (
+		[]string, *CommonAnalysisResult, error) {
+		pipeline := NewPipeline(test.Repository)
+		pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+		seq := &sequentialTestPipelineItem{}
+		pipeline.AddItem(seq)
+		assert.Nil(t, pipeline.Initialize(facts))
+		result, err := pipeline.Run(commits)
+		if err != nil {
+			return nil, nil, err
+		}
+		return *seq.Consumed, result[nil].(*CommonAnalysisResult), nil
+	}
+	full, fullResult, err := run(map[string]interface{}{}, commits)
+	assert.Nil(t, err)
+	facts := map[string]interface{}{ConfigPipelineIncremental: dir}
+	half, _, err := run(facts, commits[:len(commits)/2])
+	assert.Nil(t, err)
+	assert.Equal(t, full[:len(half)], half)
+	_, err = os.Stat(filepath.Join(dir, IncrementalStateFileName))
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		consumed, result, err := run(facts, commits)
+		assert.Nil(t, err)
+		assert.Equal(t, full, consumed)
+		assert.Equal(t, fullResult.BeginTime, result.BeginTime)
+		assert.Equal(t, fullResult.EndTime, result.EndTime)
+		assert.Equal(t, fullResult.CommitsNumber, result.CommitsNumber)
+	}
+	// the last analysed commit disappeared
+	_, _, err = run(facts, commits[:1])
+	assert.NotNil(t, err)
+	// checkpoints are not supported
+	pipeline = NewPipeline(test.Repository)
+	pipeline.AddItem(&sequentialTestPipelineItem{})
+	assert.NotNil(t, pipeline.Initialize(map[string]interface{}{
+		ConfigPipelineIncremental:         dir,
+		ConfigPipelineCheckpointDirectory: dir,
+	}))
+}
+
+func TestNewIncrementalCommits(t *testing.T) {
+	commit := func(name string, parents ...string) *object.Commit {
+		hash := func(name string) plumbing.Hash {
+			return plumbing.NewHash(strings.Repeat(name, 40))
+		}
+		c := &object.Commit{Hash: hash(name)}
+		for _, parent := range parents {
+			c.ParentHashes = append(c.ParentHashes, hash(parent))
+		}
+		return c
+	}
+	a, b, c := commit("a"), commit("b", "a"), commit("c", "b")
+	d, e := commit("d", "c"), commit("e", "d")
+	result, err := newIncrementalCommits([]*object.Commit{a, b, c, d, e}, c.Hash.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []*object.Commit{d, e}, result)
+	result, err = newIncrementalCommits([]*object.Commit{a, b, c}, c.Hash.String())
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+	// the last analysed commit is missing
+	_, err = newIncrementalCommits([]*object.Commit{a, b}, c.Hash.String())
+	assert.NotNil(t, err)
+	// a branch from the analysed history
+	f := commit("f", "a")
+	_, err = newIncrementalCommits([]*object.Commit{a, b, c, f}, c.Hash.String())
+	assert.NotNil(t, err)
+	// a merge with the last analysed commit
+	m := commit("1", "c", "d")
+	_, err = newIncrementalCommits([]*object.Commit{a, b, c, d, m}, c.Hash.String())
+	assert.NotNil(t, err)
+}
+
 func TestPipelineOnProgress(t *testing.T) {
 	pipeline := NewPipeline(test.Repository)
 	progressOk := 0
@@ -376,6 +705,61 @@ func TestPipelineOnProgress(t *testing.T) {
 	assert.Equal(t, 4, progressOk)
 }
 
+func TestPipelineOnEvent(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	item := &testPipelineItem{}
+	pipeline.AddItem(item)
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{}))
+	var events []ProgressEvent
+	pipeline.OnEvent = func(event ProgressEvent) {
+		assert.False(t, event.Time.IsZero())
+		events = append(events, event)
+	}
+	commits := make([]*object.Commit, 1)
+	commits[0], _ = test.Repository.CommitObject(plumbing.NewHash(
+		"af9ddc0db70f09f3f27b4b98e415592a7485171c"))
+	_, err := pipeline.Run(commits)
+	assert.Nil(t, err)
+	types := make([]ProgressEventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+		assert.Equal(t, 4, event.Total)
+	}
+	assert.Equal(t, []ProgressEventType{
+		ProgressCommitStarted, ProgressItemConsumed, ProgressCommitFinished,
+		ProgressFinalize, ProgressItemFinalized, ProgressDone}, types)
+	for _, event := range events[:3] {
+		assert.Equal(t, 2, event.Step)
+		assert.Equal(t, "af9ddc0db70f09f3f27b4b98e415592a7485171c", event.Commit)
+	}
+	assert.Equal(t, item.Name(), events[1].Item)
+	assert.Equal(t, 3, events[3].Step)
+	assert.Equal(t, item.Name(), events[4].Item)
+	assert.Equal(t, 4, events[5].Step)
+}
+
+func TestPipelineOnEventWorkers(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&sequentialTestPipelineItem{Concurrent: true})
+	pipeline.AddItem(&sequentialTestPipelineItem{})
+	assert.Nil(t, pipeline.Initialize(map[string]interface{}{ConfigPipelineWorkers: 4}))
+	counts := map[ProgressEventType]int{}
+	pipeline.OnEvent = func(event ProgressEvent) {
+		counts[event.Type]++
+	}
+	commits, err := pipeline.Commits(false)
+	assert.Nil(t, err)
+	_, err = pipeline.Run(commits)
+	assert.Nil(t, err)
+	// merge commits are consumed once per merged branch
+	assert.True(t, counts[ProgressCommitStarted] >= len(commits))
+	assert.Equal(t, counts[ProgressCommitStarted], counts[ProgressCommitFinished])
+	assert.Equal(t, 2*counts[ProgressCommitStarted], counts[ProgressItemConsumed])
+	assert.True(t, counts[ProgressFork] > 0)
+	assert.True(t, counts[ProgressMerge] > 0)
+	assert.Equal(t, 1, counts[ProgressDone])
+}
+
 func TestPipelineCommitsFull(t *testing.T) {
 	pipeline := NewPipeline(test.Repository)
 	commits, err := pipeline.Commits(false)
@@ -408,6 +792,92 @@ func TestPipelineCommitsFirstParent(t *testing.T) {
 		"a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3"))
 }
 
+func TestPipelineCommitsRange(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.Range = CommitsRange{To: "a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3"}
+	commits, err := pipeline.Commits(false)
+	assert.Nil(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3", commits[0].Hash.String())
+	assert.Equal(t, "cce947b98a050c6d356bc6ba95030254914027b1", commits[1].Hash.String())
+	pipeline.Range.From = "cce947b98a050c6d356bc6ba95030254914027b1"
+	for _, firstParent := range []bool{false, true} {
+		commits, err = pipeline.Commits(firstParent)
+		assert.Nil(t, err)
+		assert.Len(t, commits, 1)
+		assert.Equal(t, "a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3", commits[0].Hash.String())
+	}
+	// the parent is outside of the range
+	plan := prepareRunPlan(commits, 0, false)
+	assert.Len(t, plan, 2)
+	assert.Equal(t, runActionEmerge, plan[0].Action)
+	assert.Equal(t, runActionCommit, plan[1].Action)
+	pipeline.Range = CommitsRange{From: "unknown"}
+	_, err = pipeline.Commits(false)
+	assert.NotNil(t, err)
+	pipeline.Range = CommitsRange{To: "unknown"}
+	_, err = pipeline.Commits(false)
+	assert.NotNil(t, err)
+}
+
+func TestPipelineCommitsFromRefs(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	commits, err := pipeline.CommitsFromRefs([]string{
+		"a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3",
+		"cce947b98a050c6d356bc6ba95030254914027b1"}, false)
+	assert.Nil(t, err)
+	assert.Len(t, commits, 2)
+	for _, firstParent := range []bool{false, true} {
+		full, err := pipeline.Commits(firstParent)
+		assert.Nil(t, err)
+		commits, err = pipeline.CommitsFromRefs([]string{"*"}, firstParent)
+		assert.Nil(t, err)
+		assert.True(t, len(commits) >= len(full))
+		hashMap := map[plumbing.Hash]bool{}
+		for _, c := range commits {
+			hashMap[c.Hash] = true
+		}
+		assert.Equal(t, len(commits), len(hashMap))
+		for _, c := range full {
+			assert.Contains(t, hashMap, c.Hash)
+		}
+	}
+	_, err = pipeline.CommitsFromRefs([]string{"does-not-exist-*"}, false)
+	assert.NotNil(t, err)
+	_, err = pipeline.CommitsFromRefs([]string{"does-not-exist"}, false)
+	assert.NotNil(t, err)
+	pipeline.Range.To = "HEAD"
+	_, err = pipeline.CommitsFromRefs([]string{"*"}, false)
+	assert.NotNil(t, err)
+}
+
+func TestPipelineCommitsTimeRange(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	full, err := pipeline.Commits(true)
+	assert.Nil(t, err)
+	since := full[len(full)/4].Committer.When
+	until := full[len(full)/2].Committer.When
+	pipeline.Range = CommitsRange{Since: since, Until: until}
+	commits, err := pipeline.Commits(true)
+	assert.Nil(t, err)
+	assert.True(t, len(commits) > 0)
+	for i, commit := range commits {
+		assert.False(t, commit.Committer.When.Before(since))
+		assert.False(t, commit.Committer.When.After(until))
+		if i > 0 {
+			// skipped commits are bridged over
+			assert.Equal(t, commits[i-1].Hash, commit.ParentHashes[0])
+		}
+	}
+	commits, err = pipeline.Commits(false)
+	assert.Nil(t, err)
+	assert.True(t, len(commits) > 0)
+	for _, commit := range commits {
+		assert.False(t, commit.Committer.When.Before(since))
+		assert.False(t, commit.Committer.When.After(until))
+	}
+}
+
 func TestLoadCommitsFromFile(t *testing.T) {
 	tmp, err := ioutil.TempFile("", "hercules-test-")
 	assert.Nil(t, err)
@@ -461,6 +931,29 @@ func TestPipelineDeps(t *testing.T) {
 	assert.Panics(t, func() { pipeline.Run(commits) })
 }
 
+func TestPipelineUnsatisfiedDependency(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&dependingTestPipelineItem{})
+	err := pipeline.Initialize(map[string]interface{}{})
+	assert.IsType(t, &UnsatisfiedDependencyError{}, err)
+	depErr := err.(*UnsatisfiedDependencyError)
+	assert.Equal(t, "Test2", depErr.Item)
+	assert.Equal(t, "test", depErr.Key)
+	assert.Contains(t, err.Error(), "unsatisfied dependency")
+}
+
+func TestPipelineAmbiguousProvider(t *testing.T) {
+	pipeline := NewPipeline(test.Repository)
+	pipeline.AddItem(&testPipelineItem{})
+	pipeline.AddItem(&testPipelineItem{})
+	pipeline.AddItem(&testPipelineItem{})
+	err := pipeline.Initialize(map[string]interface{}{})
+	assert.IsType(t, &AmbiguousProviderError{}, err)
+	ambErr := err.(*AmbiguousProviderError)
+	assert.Equal(t, "test", ambErr.Key)
+	assert.Equal(t, []string{"Test_1", "Test_2", "Test_3"}, ambErr.Candidates)
+}
+
 func TestPipelineDeployFeatures(t *testing.T) {
 	pipeline := NewPipeline(test.Repository)
 	pipeline.DeployItem(&testPipelineItem{})
@@ -575,21 +1068,39 @@ func TestCommonAnalysisResultMerge(t *testing.T) {
 	assert.Equal(t, c1.EndTimeAsTime().Unix(), int64(1513720635))
 	c2 := CommonAnalysisResult{
 		BeginTime: 1513620535, EndTime: 1513730635, CommitsNumber: 2, RunTime: 200,
-		RunTimePerItem: map[string]float64{"two": 4, "three": 8}}
+		RunTimePerItem: map[string]float64{"two": 4, "three": 8}, Repository: "two"}
 	c1.Merge(&c2)
 	assert.Equal(t, c1.BeginTime, int64(1513620535))
 	assert.Equal(t, c1.EndTime, int64(1513730635))
 	assert.Equal(t, c1.CommitsNumber, 3)
 	assert.Equal(t, c1.RunTime.Nanoseconds(), int64(300))
 	assert.Equal(t, c1.RunTimePerItem, map[string]float64{"one": 1, "two": 6, "three": 8})
+	assert.Equal(t, c1.Repository, "two")
+	c3 := CommonAnalysisResult{
+		BeginTime: 1513620535, EndTime: 1513730635, Repository: "three"}
+	c1.Merge(&c3)
+	assert.Equal(t, c1.Repository, "two & three")
+	c1.Merge(&c3)
+	assert.Equal(t, c1.Repository, "two & three")
+	assert.Equal(t, c1.Signatures, "")
+	c4 := CommonAnalysisResult{BeginTime: 1513620535, EndTime: 1513730635, Signatures: "author"}
+	c1.Merge(&c4)
+	assert.Equal(t, c1.Signatures, "author")
+	c4.Signatures = "committer"
+	c1.Merge(&c4)
+	assert.Equal(t, c1.Signatures, "author & committer")
+	c1.Merge(&c4)
+	assert.Equal(t, c1.Signatures, "author & committer")
 }
 
 func TestCommonAnalysisResultMetadata(t *testing.T) {
 	c1 := &CommonAnalysisResult{
 		BeginTime: 1513620635, EndTime: 1513720635, CommitsNumber: 1, RunTime: 100 * 1e6,
-		RunTimePerItem: map[string]float64{"one": 1, "two": 2}}
-	meta := &pb.Metadata{}
+		RunTimePerItem: map[string]float64{"one": 1, "two": 2}, Signatures: "both"}
+	meta := &pb.Metadata{Repository: "one"}
 	c1 = MetadataToCommonAnalysisResult(c1.FillMetadata(meta))
+	assert.Equal(t, c1.Repository, "one")
+	assert.Equal(t, c1.Signatures, "both")
 	assert.Equal(t, c1.BeginTimeAsTime().Unix(), int64(1513620635))
 	assert.Equal(t, c1.EndTimeAsTime().Unix(), int64(1513720635))
 	assert.Equal(t, c1.CommitsNumber, 1)
@@ -625,6 +1136,48 @@ func TestConfigurationOptionFormatDefault(t *testing.T) {
 	assert.Equal(t, opt.FormatDefault(), "0.5")
 }
 
+func TestConfigurationOptionConvertValue(t *testing.T) {
+	check := func(optType ConfigurationOptionType, value interface{}, expected interface{}) {
+		opt := ConfigurationOption{Name: "Test.Option", Type: optType}
+		converted, err := opt.ConvertValue(value)
+		if expected == nil {
+			assert.Nil(t, converted)
+			assert.IsType(t, &ConfigurationOptionTypeError{}, err)
+			assert.Equal(t, opt, err.(*ConfigurationOptionTypeError).Option)
+			assert.Equal(t, value, err.(*ConfigurationOptionTypeError).Value)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, expected, converted)
+		}
+	}
+	check(BoolConfigurationOption, true, true)
+	check(BoolConfigurationOption, "true", nil)
+	check(IntConfigurationOption, 7, 7)
+	check(IntConfigurationOption, int64(7), 7)
+	check(IntConfigurationOption, float64(7), 7)
+	check(IntConfigurationOption, 7.5, nil)
+	check(IntConfigurationOption, "7", nil)
+	check(StringConfigurationOption, "text", "text")
+	check(StringConfigurationOption, 7, nil)
+	check(PathConfigurationOption, "/tmp", "/tmp")
+	check(FloatConfigurationOption, 0.5, float32(0.5))
+	check(FloatConfigurationOption, float32(0.5), float32(0.5))
+	check(FloatConfigurationOption, 1, float32(1))
+	check(FloatConfigurationOption, "0.5", nil)
+	check(StringsConfigurationOption, []string{"a"}, []string{"a"})
+	check(StringsConfigurationOption, []interface{}{"a", "b"}, []string{"a", "b"})
+	check(StringsConfigurationOption, []interface{}{"a", 1}, nil)
+	check(StringsConfigurationOption, "a", nil)
+	err := &ConfigurationOptionTypeError{
+		Option: ConfigurationOption{Name: "Test.Option", Type: BoolConfigurationOption},
+		Value:  "yes",
+	}
+	assert.Equal(t, "configuration option Test.Option must be of type bool, got yes (string)",
+		err.Error())
+	assert.Equal(t, "unknown configuration option Test.Option",
+		(&UnknownConfigurationOptionError{Name: "Test.Option"}).Error())
+}
+
 func TestPrepareRunPlanTiny(t *testing.T) {
 	rootCommit, err := test.Repository.CommitObject(plumbing.NewHash(
 		"cce947b98a050c6d356bc6ba95030254914027b1"))
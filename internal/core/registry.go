@@ -179,6 +179,120 @@ func (acf *arrayFeatureFlags) Type() string {
 	return "string"
 }
 
+// pipelineConfigurationOptions returns the options of Pipeline itself, see Pipeline.Initialize().
+func pipelineConfigurationOptions() []ConfigurationOption {
+	options := [...]ConfigurationOption{{
+		Name:        ConfigPipelineDAGPath,
+		Description: "Write the pipeline DAG to a Graphviz file.",
+		Flag:        "dump-dag",
+		Type:        PathConfigurationOption,
+		Default:     ""}, {
+		Name: ConfigPipelineDryRun,
+		Description: "Do not run any analyses - only resolve the DAG. " +
+			"Useful for --dump-dag or --dump-plan.",
+		Flag:    "dry-run",
+		Type:    BoolConfigurationOption,
+		Default: false}, {
+		Name:        ConfigPipelineDumpPlan,
+		Description: "Print the pipeline execution plan to stderr.",
+		Flag:        "dump-plan",
+		Type:        BoolConfigurationOption,
+		Default:     false}, {
+		Name: ConfigPipelineHibernationDistance,
+		Description: "Minimum number of actions between two sequential usages of a branch to " +
+			"activate the hibernation optimization (cpu-memory trade-off). 0 disables.",
+		Flag:    "hibernation-distance",
+		Type:    IntConfigurationOption,
+		Default: 0}, {
+		Name:        ConfigPipelinePrintActions,
+		Description: "Print the executed actions to stderr.",
+		Flag:        "print-actions",
+		Type:        BoolConfigurationOption,
+		Default:     false}, {
+		Name: ConfigPipelineWorkers,
+		Description: "Maximum number of concurrent Consume() calls on independent branches. " +
+			"0 and 1 mean the sequential execution.",
+		Flag:    "workers",
+		Type:    IntConfigurationOption,
+		Default: 0}, {
+		Name: ConfigPipelineCheckpointDirectory,
+		Description: "Periodically save the state of the pipeline to this directory. " +
+			"Requires all the items to support checkpoints.",
+		Flag:    "checkpoint-dir",
+		Type:    PathConfigurationOption,
+		Default: ""}, {
+		Name:        ConfigPipelineCheckpointInterval,
+		Description: "Minimum number of commits between two sequential checkpoints.",
+		Flag:        "checkpoint-interval",
+		Type:        IntConfigurationOption,
+		Default:     DefaultCheckpointInterval}, {
+		Name: ConfigPipelineResume,
+		Description: "Continue the interrupted analysis from the checkpoint in this directory. " +
+			"The analysed repository and the commits must be the same.",
+		Flag:    "resume",
+		Type:    PathConfigurationOption,
+		Default: ""}, {
+		Name: ConfigPipelineIncremental,
+		Description: "Continue the previous analysis from the state in this directory, consume " +
+			"only the new commits and update the state. The directory is initialized by the first run.",
+		Flag:    "incremental",
+		Type:    PathConfigurationOption,
+		Default: ""},
+	}
+	return options[:]
+}
+
+// addConfigurationOptionFlag inserts the cmdline option which corresponds to the configuration
+// option into the flag set. Returns the "fact" which references the flag's value.
+func addConfigurationOptionFlag(flagSet *pflag.FlagSet, opt ConfigurationOption, help string) interface{} {
+	var iface interface{}
+	getPtr := func() unsafe.Pointer {
+		return unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface))
+	}
+	switch opt.Type {
+	case BoolConfigurationOption:
+		iface = interface{}(true)
+		ptr := (**bool)(getPtr())
+		*ptr = flagSet.Bool(opt.Flag, opt.Default.(bool), help)
+	case IntConfigurationOption:
+		iface = interface{}(0)
+		ptr := (**int)(getPtr())
+		*ptr = flagSet.Int(opt.Flag, opt.Default.(int), help)
+	case StringConfigurationOption, PathConfigurationOption:
+		iface = interface{}("")
+		ptr := (**string)(getPtr())
+		*ptr = flagSet.String(opt.Flag, opt.Default.(string), help)
+		if opt.Type == PathConfigurationOption {
+			err := cobra.MarkFlagFilename(flagSet, opt.Flag)
+			if err != nil {
+				panic(err)
+			}
+			PathifyFlagValue(flagSet.Lookup(opt.Flag))
+		}
+	case FloatConfigurationOption:
+		iface = interface{}(float32(0))
+		ptr := (**float32)(getPtr())
+		*ptr = flagSet.Float32(opt.Flag, opt.Default.(float32), help)
+	case StringsConfigurationOption:
+		iface = interface{}([]string{})
+		ptr := (**[]string)(getPtr())
+		*ptr = flagSet.StringSlice(opt.Flag, opt.Default.([]string), help)
+	}
+	return iface
+}
+
+// AddItemFlags inserts the cmdline options from ListConfigurationOptions() of a single
+// PipelineItem into the flag set. Returns the "facts" which can be fed into
+// PipelineItem.Configure(), the same as AddFlags() does for all the registered items.
+func AddItemFlags(flagSet *pflag.FlagSet, item PipelineItem) map[string]interface{} {
+	flags := map[string]interface{}{}
+	for _, opt := range item.ListConfigurationOptions() {
+		flags[opt.Name] = addConfigurationOptionFlag(
+			flagSet, opt, fmt.Sprintf("%s [%s]", opt.Description, item.Name()))
+	}
+	return flags
+}
+
 // AddFlags inserts the cmdline options from PipelineItem.ListConfigurationOptions(),
 // FeaturedPipelineItem().Features() and LeafPipelineItem.Flag() into the global "flag" parser
 // built into the Go runtime.
@@ -189,46 +303,10 @@ func (registry *PipelineItemRegistry) AddFlags(flagSet *pflag.FlagSet) (
 	map[string]interface{}, map[string]*bool) {
 	flags := map[string]interface{}{}
 	deployed := map[string]*bool{}
-	for name, it := range registry.registered {
-		formatHelp := func(desc string) string {
-			return fmt.Sprintf("%s [%s]", desc, name)
-		}
+	for _, it := range registry.registered {
 		itemIface := reflect.New(it.Elem()).Interface()
-		for _, opt := range itemIface.(PipelineItem).ListConfigurationOptions() {
-			var iface interface{}
-			getPtr := func() unsafe.Pointer {
-				return unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface))
-			}
-			switch opt.Type {
-			case BoolConfigurationOption:
-				iface = interface{}(true)
-				ptr := (**bool)(getPtr())
-				*ptr = flagSet.Bool(opt.Flag, opt.Default.(bool), formatHelp(opt.Description))
-			case IntConfigurationOption:
-				iface = interface{}(0)
-				ptr := (**int)(getPtr())
-				*ptr = flagSet.Int(opt.Flag, opt.Default.(int), formatHelp(opt.Description))
-			case StringConfigurationOption, PathConfigurationOption:
-				iface = interface{}("")
-				ptr := (**string)(getPtr())
-				*ptr = flagSet.String(opt.Flag, opt.Default.(string), formatHelp(opt.Description))
-				if opt.Type == PathConfigurationOption {
-					err := cobra.MarkFlagFilename(flagSet, opt.Flag)
-					if err != nil {
-						panic(err)
-					}
-					PathifyFlagValue(flagSet.Lookup(opt.Flag))
-				}
-			case FloatConfigurationOption:
-				iface = interface{}(float32(0))
-				ptr := (**float32)(getPtr())
-				*ptr = flagSet.Float32(opt.Flag, opt.Default.(float32), formatHelp(opt.Description))
-			case StringsConfigurationOption:
-				iface = interface{}([]string{})
-				ptr := (**[]string)(getPtr())
-				*ptr = flagSet.StringSlice(opt.Flag, opt.Default.([]string), formatHelp(opt.Description))
-			}
-			flags[opt.Name] = iface
+		for name, fact := range AddItemFlags(flagSet, itemIface.(PipelineItem)) {
+			flags[name] = fact
 		}
 		if fpi, ok := itemIface.(FeaturedPipelineItem); ok {
 			for _, f := range fpi.Features() {
@@ -240,32 +318,9 @@ func (registry *PipelineItemRegistry) AddFlags(flagSet *pflag.FlagSet) (
 				fpi.Flag(), false, fmt.Sprintf("Runs %s analysis.", fpi.Name()))
 		}
 	}
-	{
-		// Pipeline flags
-		iface := interface{}("")
-		ptr1 := (**string)(unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface)))
-		*ptr1 = flagSet.String("dump-dag", "", "Write the pipeline DAG to a Graphviz file.")
-		flags[ConfigPipelineDAGPath] = iface
-		PathifyFlagValue(flagSet.Lookup("dump-dag"))
-		iface = interface{}(true)
-		ptr2 := (**bool)(unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface)))
-		*ptr2 = flagSet.Bool("dry-run", false, "Do not run any analyses - only resolve the DAG. "+
-			"Useful for --dump-dag or --dump-plan.")
-		flags[ConfigPipelineDryRun] = iface
-		iface = interface{}(true)
-		ptr3 := (**bool)(unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface)))
-		*ptr3 = flagSet.Bool("dump-plan", false, "Print the pipeline execution plan to stderr.")
-		flags[ConfigPipelineDumpPlan] = iface
-		iface = interface{}(0)
-		ptr4 := (**int)(unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface)))
-		*ptr4 = flagSet.Int("hibernation-distance", 0,
-			"Minimum number of actions between two sequential usages of a branch to activate "+
-				"the hibernation optimization (cpu-memory trade-off). 0 disables.")
-		flags[ConfigPipelineHibernationDistance] = iface
-		iface = interface{}(true)
-		ptr5 := (**bool)(unsafe.Pointer(uintptr(unsafe.Pointer(&iface)) + unsafe.Sizeof(&iface)))
-		*ptr5 = flagSet.Bool("print-actions", false, "Print the executed actions to stderr.")
-		flags[ConfigPipelinePrintActions] = iface
+	// Pipeline flags
+	for _, opt := range pipelineConfigurationOptions() {
+		flags[opt.Name] = addConfigurationOptionFlag(flagSet, opt, opt.Description)
 	}
 	var features []string
 	for f := range registry.featureFlags.Choices {
@@ -278,6 +333,69 @@ func (registry *PipelineItemRegistry) AddFlags(flagSet *pflag.FlagSet) (
 	return flags, deployed
 }
 
+// GetConfigurationOptions returns the configuration options of all the registered
+// PipelineItem-s and of Pipeline itself indexed by ConfigurationOption.Name.
+func (registry *PipelineItemRegistry) GetConfigurationOptions() map[string]ConfigurationOption {
+	options := map[string]ConfigurationOption{}
+	for _, t := range registry.registered {
+		item := reflect.New(t.Elem()).Interface().(PipelineItem)
+		for _, opt := range item.ListConfigurationOptions() {
+			options[opt.Name] = opt
+		}
+	}
+	for _, opt := range pipelineConfigurationOptions() {
+		options[opt.Name] = opt
+	}
+	return options
+}
+
+// ConvertFacts validates the names of the facts against GetConfigurationOptions() and casts
+// the values to the types of the corresponding options, see ConfigurationOption.ConvertValue().
+// The returned error is either *UnknownConfigurationOptionError or *ConfigurationOptionTypeError.
+func (registry *PipelineItemRegistry) ConvertFacts(raw map[string]interface{}) (
+	map[string]interface{}, error) {
+	options := registry.GetConfigurationOptions()
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	// report the errors in a stable order
+	sort.Strings(names)
+	facts := map[string]interface{}{}
+	for _, name := range names {
+		opt, exists := options[name]
+		if !exists {
+			return nil, &UnknownConfigurationOptionError{Name: name}
+		}
+		value, err := opt.ConvertValue(raw[name])
+		if err != nil {
+			return nil, err
+		}
+		facts[name] = value
+	}
+	return facts, nil
+}
+
+// UnknownLeafError is returned when there is no LeafPipelineItem with the specified flag.
+type UnknownLeafError struct {
+	// Flag is the unknown LeafPipelineItem.Flag().
+	Flag string
+}
+
+func (err *UnknownLeafError) Error() string {
+	return fmt.Sprintf("unknown analysis %s", err.Flag)
+}
+
+// SummonLeaf materializes the LeafPipelineItem which is activated by the specified cmdline flag.
+// The returned error is *UnknownLeafError.
+func (registry *PipelineItemRegistry) SummonLeaf(flag string) (LeafPipelineItem, error) {
+	t, exists := registry.flags[flag]
+	if !exists {
+		return nil, &UnknownLeafError{Flag: flag}
+	}
+	return reflect.New(t.Elem()).Interface().(LeafPipelineItem), nil
+}
+
 // Registry contains all known pipeline item types.
 var Registry = &PipelineItemRegistry{
 	provided:     map[string][]reflect.Type{},
@@ -138,13 +138,18 @@ func TestRegistryAddFlags(t *testing.T) {
 		Run:   func(cmd *cobra.Command, args []string) {},
 	}
 	facts, deployed := reg.AddFlags(testCmd.Flags())
-	assert.Len(t, facts, 7)
+	assert.Len(t, facts, 12)
 	assert.IsType(t, 0, facts[(&testPipelineItem{}).ListConfigurationOptions()[0].Name])
 	assert.IsType(t, true, facts[(&dummyPipelineItem{}).ListConfigurationOptions()[0].Name])
 	assert.Contains(t, facts, ConfigPipelineDryRun)
 	assert.Contains(t, facts, ConfigPipelineDAGPath)
 	assert.Contains(t, facts, ConfigPipelineDumpPlan)
 	assert.Contains(t, facts, ConfigPipelineHibernationDistance)
+	assert.Contains(t, facts, ConfigPipelineWorkers)
+	assert.Contains(t, facts, ConfigPipelineCheckpointDirectory)
+	assert.Contains(t, facts, ConfigPipelineCheckpointInterval)
+	assert.Contains(t, facts, ConfigPipelineResume)
+	assert.Contains(t, facts, ConfigPipelineIncremental)
 	assert.Len(t, deployed, 1)
 	assert.Contains(t, deployed, (&testPipelineItem{}).Name())
 	assert.NotNil(t, testCmd.Flags().Lookup((&testPipelineItem{}).Flag()))
@@ -154,6 +159,11 @@ func TestRegistryAddFlags(t *testing.T) {
 	assert.NotNil(t, testCmd.Flags().Lookup("dry-run"))
 	assert.NotNil(t, testCmd.Flags().Lookup("hibernation-distance"))
 	assert.NotNil(t, testCmd.Flags().Lookup("print-actions"))
+	assert.NotNil(t, testCmd.Flags().Lookup("workers"))
+	assert.NotNil(t, testCmd.Flags().Lookup("checkpoint-dir"))
+	assert.NotNil(t, testCmd.Flags().Lookup("checkpoint-interval"))
+	assert.NotNil(t, testCmd.Flags().Lookup("resume"))
+	assert.NotNil(t, testCmd.Flags().Lookup("incremental"))
 	assert.NotNil(t, testCmd.Flags().Lookup(
 		(&testPipelineItem{}).ListConfigurationOptions()[0].Flag))
 	assert.NotNil(t, testCmd.Flags().Lookup(
@@ -161,6 +171,59 @@ func TestRegistryAddFlags(t *testing.T) {
 	testCmd.UsageString() // to test that nothing is broken
 }
 
+func TestAddItemFlags(t *testing.T) {
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Temporary command to test the stuff.",
+		Long:  ``,
+		Args:  cobra.MaximumNArgs(0),
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	facts := AddItemFlags(testCmd.Flags(), &testPipelineItem{})
+	assert.Len(t, facts, 1)
+	assert.Equal(t, 10, facts["TestOption"])
+	assert.Nil(t, testCmd.ParseFlags([]string{"--test-option", "7"}))
+	assert.Equal(t, 7, facts["TestOption"])
+	assert.Nil(t, testCmd.Flags().Lookup((&testPipelineItem{}).Flag()))
+	assert.Nil(t, testCmd.Flags().Lookup("feature"))
+	assert.Contains(t, testCmd.Flags().Lookup("test-option").Usage, "[Test]")
+}
+
+func TestRegistryConvertFacts(t *testing.T) {
+	reg := getRegistry()
+	reg.Register(&testPipelineItem{})
+	reg.Register(&dummyPipelineItem{})
+	options := reg.GetConfigurationOptions()
+	assert.Len(t, options, 12)
+	assert.Equal(t, "test-option", options["TestOption"].Flag)
+	assert.Equal(t, "workers", options[ConfigPipelineWorkers].Flag)
+	facts, err := reg.ConvertFacts(map[string]interface{}{
+		"TestOption": float64(5), "DummyOption": true, ConfigPipelineWorkers: 4,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"TestOption": 5, "DummyOption": true, ConfigPipelineWorkers: 4,
+	}, facts)
+	_, err = reg.ConvertFacts(map[string]interface{}{"TestOption": 5, "Unknown": 1})
+	assert.IsType(t, &UnknownConfigurationOptionError{}, err)
+	assert.Equal(t, "Unknown", err.(*UnknownConfigurationOptionError).Name)
+	_, err = reg.ConvertFacts(map[string]interface{}{"TestOption": "5"})
+	assert.IsType(t, &ConfigurationOptionTypeError{}, err)
+	assert.Equal(t, "TestOption", err.(*ConfigurationOptionTypeError).Option.Name)
+}
+
+func TestRegistrySummonLeaf(t *testing.T) {
+	reg := getRegistry()
+	reg.Register(&testPipelineItem{})
+	reg.Register(&dummyPipelineItem{})
+	leaf, err := reg.SummonLeaf("mytest")
+	assert.Nil(t, err)
+	assert.Equal(t, (&testPipelineItem{}).Name(), leaf.Name())
+	_, err = reg.SummonLeaf("dummy")
+	assert.IsType(t, &UnknownLeafError{}, err)
+	assert.Equal(t, "unknown analysis dummy", err.Error())
+}
+
 func TestRegistryFeatures(t *testing.T) {
 	reg := getRegistry()
 	reg.Register(&dummyPipelineItem{})
@@ -5,9 +5,15 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
 	"gopkg.in/src-d/hercules.v9/internal/core"
+	items "gopkg.in/src-d/hercules.v9/internal/plumbing"
 	uast_items "gopkg.in/src-d/hercules.v9/internal/plumbing/uast"
 	"gopkg.in/src-d/hercules.v9/internal/test"
 	"gopkg.in/src-d/hercules.v9/leaves"
@@ -27,29 +33,31 @@ func TestPipelineSerialize(t *testing.T) {
 	bdot, _ := ioutil.ReadFile(dotpath)
 	dot := string(bdot)
 	assert.Equal(t, `digraph Hercules {
-  "6 BlobCache" -> "7 [blob_cache]"
+  "7 BlobCache" -> "8 [blob_cache]"
   "0 DaysSinceStart" -> "3 [day]"
-  "9 FileDiff" -> "11 [file_diff]"
-  "15 FileDiffRefiner" -> "16 Burndown"
+  "10 FileDiff" -> "12 [file_diff]"
+  "16 FileDiffRefiner" -> "17 Burndown"
   "1 IdentityDetector" -> "4 [author]"
-  "8 RenameAnalysis" -> "16 Burndown"
-  "8 RenameAnalysis" -> "9 FileDiff"
-  "8 RenameAnalysis" -> "10 UAST"
-  "8 RenameAnalysis" -> "13 UASTChanges"
-  "2 TreeDiff" -> "5 [changes]"
-  "10 UAST" -> "12 [uasts]"
-  "13 UASTChanges" -> "14 [changed_uasts]"
-  "4 [author]" -> "16 Burndown"
-  "7 [blob_cache]" -> "16 Burndown"
-  "7 [blob_cache]" -> "9 FileDiff"
-  "7 [blob_cache]" -> "8 RenameAnalysis"
-  "7 [blob_cache]" -> "10 UAST"
-  "14 [changed_uasts]" -> "15 FileDiffRefiner"
-  "5 [changes]" -> "6 BlobCache"
-  "5 [changes]" -> "8 RenameAnalysis"
-  "3 [day]" -> "16 Burndown"
-  "11 [file_diff]" -> "15 FileDiffRefiner"
-  "12 [uasts]" -> "13 UASTChanges"
+  "1 IdentityDetector" -> "5 [authors]"
+  "9 RenameAnalysis" -> "17 Burndown"
+  "9 RenameAnalysis" -> "10 FileDiff"
+  "9 RenameAnalysis" -> "11 UAST"
+  "9 RenameAnalysis" -> "14 UASTChanges"
+  "2 TreeDiff" -> "6 [changes]"
+  "11 UAST" -> "13 [uasts]"
+  "14 UASTChanges" -> "15 [changed_uasts]"
+  "4 [author]" -> "17 Burndown"
+  "5 [authors]" -> "17 Burndown"
+  "8 [blob_cache]" -> "17 Burndown"
+  "8 [blob_cache]" -> "10 FileDiff"
+  "8 [blob_cache]" -> "9 RenameAnalysis"
+  "8 [blob_cache]" -> "11 UAST"
+  "15 [changed_uasts]" -> "16 FileDiffRefiner"
+  "6 [changes]" -> "7 BlobCache"
+  "6 [changes]" -> "9 RenameAnalysis"
+  "3 [day]" -> "17 Burndown"
+  "12 [file_diff]" -> "16 FileDiffRefiner"
+  "13 [uasts]" -> "14 UASTChanges"
 }`, dot)
 }
 
@@ -67,21 +75,23 @@ func TestPipelineSerializeNoUast(t *testing.T) {
 	bdot, _ := ioutil.ReadFile(dotpath)
 	dot := string(bdot)
 	assert.Equal(t, `digraph Hercules {
-  "6 BlobCache" -> "7 [blob_cache]"
+  "7 BlobCache" -> "8 [blob_cache]"
   "0 DaysSinceStart" -> "3 [day]"
-  "9 FileDiff" -> "10 [file_diff]"
+  "10 FileDiff" -> "11 [file_diff]"
   "1 IdentityDetector" -> "4 [author]"
-  "8 RenameAnalysis" -> "11 Burndown"
-  "8 RenameAnalysis" -> "9 FileDiff"
-  "2 TreeDiff" -> "5 [changes]"
-  "4 [author]" -> "11 Burndown"
-  "7 [blob_cache]" -> "11 Burndown"
-  "7 [blob_cache]" -> "9 FileDiff"
-  "7 [blob_cache]" -> "8 RenameAnalysis"
-  "5 [changes]" -> "6 BlobCache"
-  "5 [changes]" -> "8 RenameAnalysis"
-  "3 [day]" -> "11 Burndown"
-  "10 [file_diff]" -> "11 Burndown"
+  "1 IdentityDetector" -> "5 [authors]"
+  "9 RenameAnalysis" -> "12 Burndown"
+  "9 RenameAnalysis" -> "10 FileDiff"
+  "2 TreeDiff" -> "6 [changes]"
+  "4 [author]" -> "12 Burndown"
+  "5 [authors]" -> "12 Burndown"
+  "8 [blob_cache]" -> "12 Burndown"
+  "8 [blob_cache]" -> "10 FileDiff"
+  "8 [blob_cache]" -> "9 RenameAnalysis"
+  "6 [changes]" -> "7 BlobCache"
+  "6 [changes]" -> "9 RenameAnalysis"
+  "3 [day]" -> "12 Burndown"
+  "11 [file_diff]" -> "12 Burndown"
 }`, dot)
 }
 
@@ -91,3 +101,80 @@ func TestPipelineResolveIntegration(t *testing.T) {
 	pipeline.DeployItem(&leaves.CouplesAnalysis{})
 	pipeline.Initialize(nil)
 }
+
+func TestPipelineRunWorkersIntegration(t *testing.T) {
+	run := func(workers int) map[string]interface{} {
+		pipeline := core.NewPipeline(test.Repository)
+		pipeline.DeployItem(&leaves.BurndownAnalysis{})
+		pipeline.DeployItem(&leaves.DevsAnalysis{})
+		pipeline.DeployItem(&leaves.CouplesAnalysis{})
+		// RenameAnalysis takes the result of the faster of its two matching strategies,
+		// and they disagree about the ambiguous renames which appear in 2018
+		pipeline.Range.Until = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		commits, err := pipeline.Commits(false)
+		assert.Nil(t, err)
+		assert.Nil(t, pipeline.Initialize(map[string]interface{}{
+			core.ConfigPipelineCommits:                    commits,
+			core.ConfigPipelineWorkers:                    workers,
+			leaves.ConfigBurndownTrackPeople:              true,
+			leaves.ConfigBurndownTrackFiles:               true,
+			items.ConfigRenameAnalysisSimilarityThreshold: items.RenameAnalysisDefaultThreshold,
+		}))
+		result, err := pipeline.Run(commits)
+		assert.Nil(t, err)
+		named := map[string]interface{}{}
+		for item, value := range result {
+			if item != nil {
+				named[item.Name()] = value
+			}
+		}
+		assert.Len(t, named, 3)
+		return named
+	}
+	sequential := run(1)
+	assert.Equal(t, sequential, run(4))
+}
+
+func TestPipelineRunWorkersTimeRangeIntegration(t *testing.T) {
+	repository, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.Nil(t, err)
+	worktree, err := repository.Worktree()
+	assert.Nil(t, err)
+	// the commit times are not monotonic, so the second commit is skipped by Since and
+	// the parent of the third one is rewritten
+	for i, day := range []int{10, -21, 30} {
+		file, err := worktree.Filesystem.Create("main.go")
+		assert.Nil(t, err)
+		for j := 0; j <= i; j++ {
+			file.Write([]byte("package main\n"))
+		}
+		file.Close()
+		_, err = worktree.Add("main.go")
+		assert.Nil(t, err)
+		signature := &object.Signature{
+			Name: "Vadim", Email: "vadim@sourced.tech",
+			When: time.Date(2017, 6, day, 12, 0, 0, 0, time.UTC)}
+		_, err = worktree.Commit("commit", &git.CommitOptions{Author: signature})
+		assert.Nil(t, err)
+	}
+	run := func(workers int) map[int]map[int]*leaves.DevDay {
+		pipeline := core.NewPipeline(repository)
+		devs := pipeline.DeployItem(&leaves.DevsAnalysis{})
+		pipeline.Range.Since = time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+		commits, err := pipeline.Commits(false)
+		assert.Nil(t, err)
+		assert.Len(t, commits, 2)
+		assert.Nil(t, pipeline.Initialize(map[string]interface{}{
+			core.ConfigPipelineCommits: commits,
+			core.ConfigPipelineWorkers: workers,
+		}))
+		result, err := pipeline.Run(commits)
+		assert.Nil(t, err)
+		return result[devs.(core.LeafPipelineItem)].(leaves.DevsResult).Days
+	}
+	sequential := run(1)
+	assert.Len(t, sequential, 2)
+	assert.Contains(t, sequential, 0)
+	assert.Contains(t, sequential, 20)
+	assert.Equal(t, sequential, run(4))
+}
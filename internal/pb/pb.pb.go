@@ -68,6 +68,8 @@ type Metadata struct {
 	RunTime int64 `protobuf:"varint,7,opt,name=run_time,json=runTime,proto3" json:"run_time,omitempty"`
 	// time taken by each pipeline item in seconds
 	RunTimePerItem map[string]float64 `protobuf:"bytes,8,rep,name=run_time_per_item,json=runTimePerItem" json:"run_time_per_item,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// commit signatures which identify the developers: "author", "committer" or "both"
+	Signatures string `protobuf:"bytes,9,opt,name=signatures,proto3" json:"signatures,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -131,6 +133,13 @@ func (m *Metadata) GetRunTimePerItem() map[string]float64 {
 	return nil
 }
 
+func (m *Metadata) GetSignatures() string {
+	if m != nil {
+		return m.Signatures
+	}
+	return ""
+}
+
 type BurndownSparseMatrixRow struct {
 	// the first `len(column)` elements are stored,
 	// the rest `number_of_columns - len(column)` values are zeros
@@ -557,6 +566,10 @@ func (m *FileHistory) GetChangesByDeveloper() map[int32]*LineStats {
 
 type FileHistoryResultMessage struct {
 	Files map[string]*FileHistory `protobuf:"bytes,1,rep,name=files" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	// developer identities, the indexes in FileHistory.changes_by_developer point to them
+	DevIndex []string `protobuf:"bytes,2,rep,name=dev_index,json=devIndex" json:"dev_index,omitempty"`
+	// the file paths start with the repository names
+	RepositoryPrefixed bool `protobuf:"varint,3,opt,name=repository_prefixed,json=repositoryPrefixed,proto3" json:"repository_prefixed,omitempty"`
 }
 
 func (m *FileHistoryResultMessage) Reset()                    { *m = FileHistoryResultMessage{} }
@@ -571,6 +584,20 @@ func (m *FileHistoryResultMessage) GetFiles() map[string]*FileHistory {
 	return nil
 }
 
+func (m *FileHistoryResultMessage) GetDevIndex() []string {
+	if m != nil {
+		return m.DevIndex
+	}
+	return nil
+}
+
+func (m *FileHistoryResultMessage) GetRepositoryPrefixed() bool {
+	if m != nil {
+		return m.RepositoryPrefixed
+	}
+	return false
+}
+
 type LineStats struct {
 	Added   int32 `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
 	Removed int32 `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"`
@@ -760,6 +787,8 @@ type Commit struct {
 	WhenUnixTime int64         `protobuf:"varint,2,opt,name=when_unix_time,json=whenUnixTime,proto3" json:"when_unix_time,omitempty"`
 	Author       int32         `protobuf:"varint,3,opt,name=author,proto3" json:"author,omitempty"`
 	Files        []*CommitFile `protobuf:"bytes,4,rep,name=files" json:"files,omitempty"`
+	// the origin repository, set while merging the results
+	Repository string `protobuf:"bytes,5,opt,name=repository,proto3" json:"repository,omitempty"`
 }
 
 func (m *Commit) Reset()                    { *m = Commit{} }
@@ -795,6 +824,13 @@ func (m *Commit) GetFiles() []*CommitFile {
 	return nil
 }
 
+func (m *Commit) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
 type CommitsAnalysisResults struct {
 	Commits     []*Commit `protobuf:"bytes,1,rep,name=commits" json:"commits,omitempty"`
 	AuthorIndex []string  `protobuf:"bytes,2,rep,name=author_index,json=authorIndex" json:"author_index,omitempty"`
@@ -875,99 +911,101 @@ func init() {
 func init() { proto.RegisterFile("pb.proto", fileDescriptorPb) }
 
 var fileDescriptorPb = []byte{
-	// 1495 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xcb, 0x6e, 0xdb, 0x46,
-	0x17, 0x06, 0x75, 0xd7, 0x91, 0x2c, 0x27, 0x13, 0xff, 0x36, 0xa3, 0x1f, 0xce, 0xaf, 0x10, 0xfe,
-	0x03, 0xb7, 0x49, 0x99, 0xc0, 0xe9, 0x22, 0x4d, 0x37, 0xb1, 0xe5, 0x06, 0x31, 0x10, 0x37, 0x2d,
-	0x95, 0xa4, 0xbb, 0x08, 0x63, 0x71, 0x6c, 0xb1, 0x95, 0x86, 0xc4, 0x0c, 0x29, 0x59, 0x40, 0xfb,
-	0x2a, 0xdd, 0x75, 0xd1, 0x02, 0x5d, 0xf5, 0x05, 0xba, 0xe8, 0xa6, 0xdb, 0x3e, 0x44, 0x81, 0xbe,
-	0x45, 0x31, 0x37, 0x5e, 0x14, 0x3a, 0x4d, 0x77, 0x3c, 0xe7, 0x7c, 0x67, 0xe6, 0xcc, 0x77, 0x2e,
-	0x33, 0x84, 0x56, 0x74, 0xe6, 0x46, 0x2c, 0x8c, 0x43, 0xe7, 0xcf, 0x0a, 0xb4, 0x4e, 0x49, 0x8c,
-	0x7d, 0x1c, 0x63, 0x64, 0x43, 0x73, 0x41, 0x18, 0x0f, 0x42, 0x6a, 0x5b, 0x03, 0x6b, 0xbf, 0xee,
-	0x19, 0x11, 0x21, 0xa8, 0x4d, 0x31, 0x9f, 0xda, 0x95, 0x81, 0xb5, 0xdf, 0xf6, 0xe4, 0x37, 0xba,
-	0x05, 0xc0, 0x48, 0x14, 0xf2, 0x20, 0x0e, 0xd9, 0xca, 0xae, 0x4a, 0x4b, 0x4e, 0x83, 0xee, 0xc0,
-	0xe6, 0x19, 0xb9, 0x08, 0xe8, 0x38, 0xa1, 0xc1, 0xe5, 0x38, 0x0e, 0xe6, 0xc4, 0xae, 0x0d, 0xac,
-	0xfd, 0xaa, 0xb7, 0x21, 0xd5, 0xaf, 0x68, 0x70, 0xf9, 0x32, 0x98, 0x13, 0xe4, 0xc0, 0x06, 0xa1,
-	0x7e, 0x0e, 0x55, 0x97, 0xa8, 0x0e, 0xa1, 0x7e, 0x8a, 0xb1, 0xa1, 0x39, 0x09, 0xe7, 0xf3, 0x20,
-	0xe6, 0x76, 0x43, 0x45, 0xa6, 0x45, 0x74, 0x13, 0x5a, 0x2c, 0xa1, 0xca, 0xb1, 0x29, 0x1d, 0x9b,
-	0x2c, 0xa1, 0xd2, 0xe9, 0x19, 0x5c, 0x37, 0xa6, 0x71, 0x44, 0xd8, 0x38, 0x88, 0xc9, 0xdc, 0x6e,
-	0x0d, 0xaa, 0xfb, 0x9d, 0x83, 0x5d, 0xd7, 0x1c, 0xda, 0xf5, 0x14, 0xfa, 0x0b, 0xc2, 0x4e, 0x62,
-	0x32, 0xff, 0x8c, 0xc6, 0x6c, 0xe5, 0xf5, 0x58, 0x41, 0xd9, 0x3f, 0x84, 0x1b, 0x25, 0x30, 0x74,
-	0x0d, 0xaa, 0xdf, 0x90, 0x95, 0xe4, 0xaa, 0xed, 0x89, 0x4f, 0xb4, 0x05, 0xf5, 0x05, 0x9e, 0x25,
-	0x44, 0x12, 0x65, 0x79, 0x4a, 0x78, 0x5c, 0x79, 0x64, 0x39, 0x0f, 0x61, 0xe7, 0x28, 0x61, 0xd4,
-	0x0f, 0x97, 0x74, 0x14, 0x61, 0xc6, 0xc9, 0x29, 0x8e, 0x59, 0x70, 0xe9, 0x85, 0x4b, 0x75, 0xb8,
-	0x59, 0x32, 0xa7, 0xdc, 0xb6, 0x06, 0xd5, 0xfd, 0x0d, 0xcf, 0x88, 0xce, 0x4f, 0x16, 0x6c, 0x95,
-	0x79, 0x89, 0x7c, 0x50, 0x3c, 0x27, 0x7a, 0x6b, 0xf9, 0x8d, 0xf6, 0xa0, 0x47, 0x93, 0xf9, 0x19,
-	0x61, 0xe3, 0xf0, 0x7c, 0xcc, 0xc2, 0x25, 0x97, 0x41, 0xd4, 0xbd, 0xae, 0xd2, 0xbe, 0x38, 0xf7,
-	0xc2, 0x25, 0x47, 0x1f, 0xc2, 0xf5, 0x0c, 0x65, 0xb6, 0xad, 0x4a, 0xe0, 0xa6, 0x01, 0x0e, 0x95,
-	0x1a, 0xdd, 0x83, 0x9a, 0x5c, 0xa7, 0x26, 0x39, 0xb3, 0xdd, 0x2b, 0x0e, 0xe0, 0x49, 0x94, 0xf3,
-	0x2d, 0xf4, 0x9e, 0x06, 0x33, 0xc2, 0x5f, 0x2c, 0x29, 0x61, 0x7c, 0x1a, 0x44, 0xe8, 0x81, 0x61,
-	0xc3, 0x92, 0x0b, 0xf4, 0xdd, 0xa2, 0xdd, 0x7d, 0x2d, 0x8c, 0x8a, 0x71, 0x05, 0xec, 0x3f, 0x02,
-	0xc8, 0x94, 0x79, 0x7e, 0xeb, 0x25, 0xfc, 0xd6, 0xf3, 0xfc, 0xfe, 0x55, 0xc9, 0x08, 0x3e, 0xa4,
-	0x78, 0xb6, 0xe2, 0x01, 0xf7, 0x08, 0x4f, 0x66, 0x31, 0x47, 0x03, 0xe8, 0x5c, 0x30, 0x4c, 0x93,
-	0x19, 0x66, 0x41, 0x6c, 0xd6, 0xcb, 0xab, 0x50, 0x1f, 0x5a, 0x1c, 0xcf, 0xa3, 0x59, 0x40, 0x2f,
-	0xf4, 0xd2, 0xa9, 0x8c, 0xee, 0x43, 0x33, 0x62, 0xe1, 0xd7, 0x64, 0x12, 0x4b, 0x9e, 0x3a, 0x07,
-	0xff, 0x29, 0x27, 0xc2, 0xa0, 0xd0, 0x5d, 0xa8, 0x9f, 0x8b, 0x83, 0x6a, 0xde, 0xae, 0x80, 0x2b,
-	0x0c, 0xfa, 0x08, 0x1a, 0x11, 0x09, 0xa3, 0x99, 0x28, 0xfb, 0x77, 0xa0, 0x35, 0x08, 0x9d, 0x00,
-	0x52, 0x5f, 0xe3, 0x80, 0xc6, 0x84, 0xe1, 0x49, 0x2c, 0xba, 0xb5, 0x21, 0xe3, 0xea, 0xbb, 0xc3,
-	0x70, 0x1e, 0x31, 0xc2, 0x39, 0xf1, 0x95, 0xb3, 0x17, 0x2e, 0xb5, 0xff, 0x75, 0xe5, 0x75, 0x92,
-	0x39, 0xa1, 0x47, 0xb0, 0x29, 0x43, 0x18, 0x87, 0x26, 0x21, 0x76, 0x53, 0x86, 0xb0, 0xb9, 0x96,
-	0x27, 0xaf, 0x77, 0x5e, 0x90, 0x9d, 0x5f, 0x2c, 0xb8, 0x79, 0xe5, 0x56, 0x25, 0x75, 0x68, 0xbd,
-	0x6f, 0x1d, 0x56, 0xca, 0xeb, 0x10, 0x41, 0x4d, 0xb4, 0xaa, 0x5d, 0x1d, 0x54, 0xf7, 0xab, 0x5e,
-	0xcd, 0xcc, 0xaa, 0x80, 0xfa, 0xc1, 0x44, 0xd3, 0x5c, 0xf7, 0x8c, 0x88, 0xb6, 0xa1, 0x11, 0x50,
-	0x3f, 0x8a, 0x99, 0x64, 0xb4, 0xea, 0x69, 0xc9, 0x19, 0x41, 0x73, 0x18, 0x26, 0x91, 0x20, 0x7d,
-	0x0b, 0xea, 0x01, 0xf5, 0xc9, 0xa5, 0x2c, 0xcc, 0xb6, 0xa7, 0x04, 0x74, 0x00, 0x8d, 0xb9, 0x3c,
-	0x82, 0x8c, 0xe3, 0xdd, 0x7c, 0x6a, 0xa4, 0xb3, 0x07, 0xdd, 0x97, 0x61, 0x32, 0x99, 0x12, 0x5f,
-	0x72, 0x26, 0x56, 0x56, 0xb9, 0xb7, 0x64, 0x50, 0x4a, 0x70, 0x7e, 0xb7, 0x60, 0x5b, 0xef, 0xbd,
-	0x5e, 0x9b, 0x77, 0xa1, 0x2b, 0x30, 0xe3, 0x89, 0x32, 0xeb, 0x54, 0xb6, 0x5c, 0x0d, 0xf7, 0x3a,
-	0xc2, 0x6a, 0xe2, 0xbe, 0x0f, 0x3d, 0x9d, 0x7d, 0x03, 0x6f, 0xae, 0xc1, 0x37, 0x94, 0xdd, 0x38,
-	0x3c, 0x80, 0xae, 0x76, 0x50, 0x51, 0xa9, 0xe9, 0xb7, 0xe1, 0xe6, 0x63, 0xf6, 0x3a, 0x0a, 0xa2,
-	0x0e, 0xf0, 0x3f, 0xe8, 0xa8, 0xaa, 0x98, 0x05, 0x94, 0x70, 0xbb, 0x2d, 0x8f, 0x01, 0x52, 0xf5,
-	0x5c, 0x68, 0x9c, 0x1f, 0x2c, 0x80, 0x57, 0x87, 0xa3, 0x97, 0xc3, 0x29, 0xa6, 0x17, 0x04, 0xfd,
-	0x17, 0xda, 0x32, 0xfe, 0xdc, 0x38, 0x6a, 0x09, 0xc5, 0xe7, 0x62, 0x24, 0xed, 0x02, 0x70, 0x36,
-	0x19, 0x9f, 0x91, 0xf3, 0x90, 0x11, 0x7d, 0x79, 0xb4, 0x39, 0x9b, 0x1c, 0x49, 0x85, 0xf0, 0x15,
-	0x66, 0x7c, 0x1e, 0x13, 0xa6, 0x2f, 0x90, 0x16, 0x67, 0x93, 0x43, 0x21, 0x8b, 0x40, 0x12, 0xcc,
-	0x63, 0xe3, 0x5c, 0x53, 0xf7, 0x8b, 0x50, 0x69, 0xef, 0x5d, 0x90, 0x92, 0x76, 0xaf, 0xab, 0xc5,
-	0x85, 0x46, 0xfa, 0x3b, 0x4f, 0x60, 0x27, 0x0b, 0x93, 0x8f, 0xf0, 0x82, 0x30, 0xc3, 0xf9, 0xff,
-	0xa1, 0x39, 0x51, 0x6a, 0x3d, 0x99, 0x3a, 0x6e, 0x06, 0xf5, 0x8c, 0xcd, 0xf9, 0xcd, 0x82, 0xde,
-	0x68, 0x1a, 0xc6, 0x94, 0x70, 0xee, 0x91, 0x49, 0xc8, 0x7c, 0x51, 0x89, 0xf1, 0x2a, 0x4a, 0xe7,
-	0xae, 0xf8, 0x4e, 0x67, 0x71, 0x25, 0x37, 0x8b, 0x11, 0xd4, 0x04, 0x09, 0xfa, 0x50, 0xf2, 0x1b,
-	0x7d, 0x02, 0xad, 0x49, 0x98, 0x88, 0x06, 0x34, 0x93, 0x61, 0xd7, 0x2d, 0x2e, 0x2f, 0xb2, 0x28,
-	0xed, 0x6a, 0x26, 0xa6, 0xf0, 0xfe, 0xa7, 0xb0, 0x51, 0x30, 0xfd, 0xab, 0xc9, 0x78, 0x0c, 0x3b,
-	0x66, 0x9b, 0xf5, 0xe2, 0xfb, 0x00, 0x9a, 0x4c, 0xee, 0x6c, 0x88, 0xd8, 0x5c, 0x8b, 0xc8, 0x33,
-	0x76, 0xe7, 0x0f, 0x0b, 0x3a, 0xa2, 0x42, 0x9e, 0x05, 0x5c, 0xde, 0xee, 0xb9, 0x1b, 0x59, 0x35,
-	0x51, 0x7a, 0x23, 0xbf, 0x86, 0x2d, 0xcd, 0xe0, 0xf8, 0x6c, 0x35, 0xf6, 0xc9, 0x82, 0xcc, 0xc2,
-	0x88, 0x30, 0xbb, 0x22, 0x77, 0xd8, 0x73, 0x73, 0xab, 0xb8, 0x3a, 0x3b, 0x47, 0xab, 0x63, 0x03,
-	0x53, 0x47, 0x47, 0x93, 0xb7, 0x0c, 0xfd, 0x2f, 0x61, 0xe7, 0x0a, 0x78, 0x09, 0x1d, 0x83, 0x3c,
-	0x1d, 0x9d, 0x03, 0x70, 0x45, 0xf1, 0x8e, 0x62, 0x1c, 0xf3, 0x3c, 0x35, 0xdf, 0x5b, 0x60, 0xe7,
-	0xc2, 0x51, 0xb4, 0x9c, 0x12, 0xce, 0xf1, 0x05, 0x41, 0x8f, 0xf3, 0xad, 0xbc, 0x16, 0x78, 0x01,
-	0xa9, 0xc6, 0xa5, 0xbe, 0xc7, 0xa4, 0x4b, 0xff, 0x29, 0x40, 0xa6, 0x2c, 0x79, 0x27, 0x38, 0xc5,
-	0xf0, 0xba, 0x85, 0xb5, 0x73, 0x01, 0xbe, 0x82, 0x76, 0x1a, 0xb8, 0x48, 0x31, 0xf6, 0x7d, 0xe2,
-	0xeb, 0x73, 0x2a, 0x41, 0x24, 0x82, 0x91, 0x79, 0xb8, 0x20, 0xbe, 0x4e, 0xbd, 0x11, 0x65, 0x8a,
-	0x24, 0x61, 0xbe, 0xbe, 0xe0, 0x8d, 0x28, 0x2a, 0xbb, 0x71, 0x4c, 0x16, 0xc7, 0x78, 0x2d, 0x8f,
-	0x85, 0x97, 0xd5, 0x00, 0xea, 0x5c, 0xec, 0x5b, 0x46, 0xa1, 0x34, 0xa0, 0x8f, 0xa1, 0x3d, 0xc3,
-	0xf4, 0x22, 0xc1, 0xa2, 0x93, 0xaa, 0x92, 0xa5, 0x6d, 0x57, 0xad, 0xeb, 0x3e, 0x37, 0x06, 0xc5,
-	0x4b, 0x06, 0xec, 0x3f, 0x83, 0x5e, 0xd1, 0x58, 0xc2, 0xcf, 0xfb, 0xa5, 0x8f, 0x43, 0xf3, 0x18,
-	0x8b, 0x5a, 0xe0, 0xe8, 0x0e, 0xd4, 0x7c, 0xb2, 0x30, 0xb9, 0x42, 0xae, 0xd6, 0x8b, 0x68, 0x74,
-	0x04, 0xd2, 0xde, 0x7f, 0x02, 0xed, 0x54, 0x55, 0x52, 0x36, 0xbb, 0xc5, 0x7d, 0x9b, 0xfa, 0x34,
-	0xf9, 0x4d, 0x7f, 0xb4, 0xe0, 0x86, 0x58, 0x62, 0xbd, 0x97, 0x0e, 0xc4, 0x25, 0xb5, 0x32, 0x11,
-	0xdc, 0x72, 0x4b, 0x30, 0x22, 0xaa, 0x34, 0x1a, 0xbc, 0xe2, 0x62, 0x00, 0xfa, 0x64, 0x31, 0x56,
-	0x77, 0x51, 0x45, 0xb6, 0x51, 0xcb, 0x27, 0x8b, 0x13, 0x21, 0xf7, 0x0f, 0xa1, 0x9d, 0xe2, 0x4b,
-	0x42, 0xbd, 0x55, 0x0c, 0xb5, 0x65, 0x8e, 0x9c, 0x8f, 0xf5, 0x2b, 0x68, 0x8f, 0x08, 0x15, 0x0f,
-	0x60, 0x1a, 0x67, 0x13, 0x42, 0x2c, 0x52, 0xd1, 0x30, 0xf1, 0xf2, 0x11, 0x09, 0x27, 0x54, 0x26,
-	0x5a, 0x46, 0x60, 0xe4, 0x7c, 0x6d, 0x54, 0x0b, 0x3d, 0xee, 0xfc, 0x6a, 0xc1, 0xce, 0x50, 0xc1,
-	0xd2, 0x0d, 0x0c, 0x11, 0xaf, 0xe1, 0x1a, 0x37, 0x3a, 0x39, 0x01, 0xf0, 0x4a, 0x93, 0x72, 0xcf,
-	0xbd, 0xc2, 0xc7, 0x4d, 0x15, 0x47, 0xab, 0x63, 0xbc, 0xd2, 0x8f, 0x70, 0x5e, 0x50, 0xf6, 0x4f,
-	0xe1, 0x46, 0x09, 0xec, 0x7d, 0x7a, 0x3f, 0xdb, 0x2e, 0xc7, 0xcd, 0x1b, 0x80, 0xa1, 0x3c, 0x8d,
-	0x68, 0xbd, 0xd2, 0x07, 0x75, 0x1f, 0x5a, 0xa6, 0x6a, 0xcd, 0xed, 0x64, 0xe4, 0xac, 0x39, 0x6a,
-	0x57, 0x34, 0x87, 0xf3, 0x1d, 0x34, 0xd4, 0xfa, 0xe9, 0xcf, 0x93, 0x95, 0xfb, 0x79, 0xda, 0x83,
-	0xde, 0x72, 0x4a, 0xf2, 0xff, 0x46, 0x15, 0xf9, 0xf3, 0xd2, 0x15, 0xda, 0xf4, 0xb7, 0x67, 0x1b,
-	0x1a, 0x38, 0x89, 0xa7, 0x21, 0xd3, 0x0d, 0xac, 0x25, 0x74, 0xbb, 0xf8, 0xc2, 0xec, 0xb8, 0xd9,
-	0x49, 0xcc, 0x93, 0xe3, 0x8d, 0x78, 0x71, 0xc8, 0x64, 0xad, 0x17, 0xea, 0xed, 0xe2, 0xe4, 0x16,
-	0x55, 0xae, 0x90, 0x59, 0xeb, 0xdf, 0x86, 0xae, 0xda, 0xa9, 0x50, 0x9a, 0x1d, 0xa5, 0x93, 0xd5,
-	0xe9, 0xfc, 0x6c, 0xc1, 0xe6, 0xdb, 0x2b, 0x37, 0xa6, 0x04, 0xfb, 0x84, 0xc9, 0xa3, 0x76, 0x0e,
-	0xda, 0xe9, 0x5f, 0x96, 0xa7, 0x0d, 0xe8, 0xb1, 0x28, 0x37, 0x1a, 0xa7, 0xe5, 0x26, 0x3a, 0x65,
-	0xbd, 0x4b, 0x86, 0x1a, 0x90, 0xde, 0x82, 0x4a, 0x54, 0xb7, 0x60, 0xce, 0xf4, 0x4f, 0xff, 0x5f,
-	0xdd, 0x5c, 0xba, 0xcf, 0x1a, 0xf2, 0x7f, 0xf7, 0xe1, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x2e,
-	0x80, 0xba, 0x8e, 0xfb, 0x0e, 0x00, 0x00,
+	// 1526 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x95, 0x57, 0x4b, 0x73, 0x1b, 0x45,
+	0x10, 0xae, 0xd5, 0x5b, 0x2d, 0x59, 0x4e, 0xc6, 0xc1, 0x56, 0x44, 0x39, 0x38, 0x5b, 0x86, 0x0a,
+	0x24, 0x6c, 0x52, 0x0e, 0x87, 0x10, 0x2e, 0x71, 0x1c, 0x52, 0x71, 0x55, 0x9c, 0x84, 0x55, 0x12,
+	0x6e, 0xa8, 0xc6, 0xda, 0x91, 0xb5, 0x20, 0xed, 0xaa, 0x76, 0x76, 0x6d, 0xab, 0x8a, 0x33, 0xff,
+	0x82, 0x23, 0x07, 0xa8, 0xe2, 0xc4, 0x1f, 0xe0, 0xc0, 0x85, 0x2b, 0xff, 0x82, 0x2a, 0x7e, 0x04,
+	0x3d, 0xaf, 0x7d, 0x28, 0xeb, 0x10, 0x6e, 0xd3, 0x8f, 0x99, 0xee, 0xfe, 0xfa, 0x31, 0x33, 0xd0,
+	0x5a, 0x1c, 0x3b, 0x8b, 0x28, 0x8c, 0x43, 0xfb, 0x87, 0x2a, 0xb4, 0x8e, 0x58, 0x4c, 0x3d, 0x1a,
+	0x53, 0xd2, 0x87, 0xe6, 0x29, 0x8b, 0xb8, 0x1f, 0x06, 0x7d, 0x6b, 0xc7, 0xba, 0x51, 0x77, 0x0d,
+	0x49, 0x08, 0xd4, 0xa6, 0x94, 0x4f, 0xfb, 0x15, 0x64, 0xb7, 0x5d, 0xb9, 0x26, 0xd7, 0x00, 0x22,
+	0xb6, 0x08, 0xb9, 0x1f, 0x87, 0xd1, 0xb2, 0x5f, 0x95, 0x92, 0x1c, 0x87, 0x7c, 0x04, 0xeb, 0xc7,
+	0xec, 0xc4, 0x0f, 0x46, 0x49, 0xe0, 0x9f, 0x8f, 0x62, 0x7f, 0xce, 0xfa, 0x35, 0x54, 0xaa, 0xba,
+	0x6b, 0x92, 0xfd, 0x0a, 0xb9, 0x2f, 0x91, 0x49, 0x6c, 0x58, 0x63, 0x81, 0x97, 0xd3, 0xaa, 0x4b,
+	0xad, 0x0e, 0x32, 0x53, 0x1d, 0xf4, 0x6c, 0x1c, 0xce, 0xe7, 0x7e, 0xcc, 0xfb, 0x0d, 0xe5, 0x99,
+	0x26, 0xc9, 0x55, 0x68, 0x45, 0x49, 0xa0, 0x36, 0x36, 0xe5, 0xc6, 0x26, 0xd2, 0x72, 0xd3, 0x13,
+	0xb8, 0x6c, 0x44, 0xa3, 0x05, 0x8b, 0x46, 0x7e, 0xcc, 0xe6, 0xfd, 0xd6, 0x4e, 0xf5, 0x46, 0x67,
+	0x6f, 0xdb, 0x31, 0x41, 0x3b, 0xae, 0xd2, 0x7e, 0xc1, 0xa2, 0x43, 0x94, 0x7f, 0x19, 0xc4, 0xd1,
+	0xd2, 0xed, 0x45, 0x05, 0xa6, 0x08, 0x95, 0xfb, 0x27, 0x01, 0x8d, 0x93, 0x88, 0xf1, 0x7e, 0x5b,
+	0x85, 0x9a, 0x71, 0x06, 0xfb, 0xb0, 0x51, 0x72, 0x0c, 0xb9, 0x04, 0xd5, 0xef, 0xd8, 0x52, 0x62,
+	0xd9, 0x76, 0xc5, 0x92, 0x5c, 0x81, 0xfa, 0x29, 0x9d, 0x25, 0x4c, 0x02, 0x69, 0xb9, 0x8a, 0xb8,
+	0x5f, 0xb9, 0x67, 0xd9, 0x77, 0x61, 0xeb, 0x61, 0x12, 0x05, 0x5e, 0x78, 0x16, 0x0c, 0x17, 0x34,
+	0xe2, 0xec, 0x88, 0xc6, 0x91, 0x7f, 0xee, 0x86, 0x67, 0x2a, 0xf8, 0x59, 0x32, 0x0f, 0x38, 0x1e,
+	0x55, 0xbd, 0xb1, 0xe6, 0x1a, 0xd2, 0xfe, 0xc5, 0x82, 0x2b, 0x65, 0xbb, 0x44, 0xbe, 0x02, 0x8a,
+	0x88, 0x28, 0xd3, 0x72, 0x4d, 0x76, 0xa1, 0x17, 0x24, 0xf3, 0x63, 0x04, 0x22, 0x9c, 0x8c, 0xa2,
+	0xf0, 0x8c, 0x4b, 0x27, 0xea, 0x6e, 0x57, 0x71, 0x9f, 0x4f, 0xd0, 0x16, 0x27, 0x9f, 0xc0, 0xe5,
+	0x4c, 0xcb, 0x98, 0xad, 0x4a, 0xc5, 0x75, 0xa3, 0x78, 0xa0, 0xd8, 0xe4, 0x16, 0xd4, 0xe4, 0x39,
+	0x35, 0x89, 0x69, 0xdf, 0xb9, 0x20, 0x00, 0x57, 0x6a, 0xd9, 0xdf, 0x43, 0xef, 0xb1, 0x3f, 0x63,
+	0xfc, 0xf9, 0x59, 0x80, 0x55, 0x35, 0xf5, 0x17, 0xe4, 0x8e, 0x41, 0xc3, 0x92, 0x07, 0x0c, 0x9c,
+	0xa2, 0xdc, 0x79, 0x2d, 0x84, 0x2a, 0x23, 0x4a, 0x71, 0x70, 0x0f, 0x20, 0x63, 0xe6, 0xf1, 0xad,
+	0x97, 0xe0, 0x5b, 0xcf, 0xe3, 0xfb, 0x77, 0x25, 0x03, 0x78, 0x3f, 0xa0, 0xb3, 0x25, 0xf7, 0xb9,
+	0xcb, 0x78, 0x32, 0xc3, 0x1a, 0xda, 0x81, 0xce, 0x49, 0x44, 0x83, 0x64, 0x46, 0x23, 0x3f, 0x36,
+	0xe7, 0xe5, 0x59, 0x64, 0x00, 0x2d, 0x4e, 0xe7, 0x8b, 0x99, 0x1f, 0x9c, 0xe8, 0xa3, 0x53, 0x9a,
+	0xdc, 0x86, 0x26, 0xf6, 0xd2, 0xb7, 0x6c, 0x1c, 0x4b, 0x9c, 0x3a, 0x7b, 0xef, 0x95, 0x03, 0x61,
+	0xb4, 0xc8, 0x4d, 0xa8, 0x4f, 0x44, 0xa0, 0x1a, 0xb7, 0x0b, 0xd4, 0x95, 0x0e, 0xf9, 0x14, 0x1a,
+	0x0b, 0x16, 0x2e, 0x66, 0xa2, 0x2d, 0xde, 0xa2, 0xad, 0x95, 0xc8, 0x21, 0x10, 0xb5, 0x1a, 0xf9,
+	0x41, 0xcc, 0x22, 0x3a, 0x8e, 0x45, 0x37, 0x37, 0xa4, 0x5f, 0x03, 0xe7, 0x20, 0x9c, 0x2f, 0xb0,
+	0x5e, 0x39, 0xf3, 0xd4, 0x66, 0x4c, 0x8e, 0xde, 0x7f, 0x59, 0xed, 0x3a, 0xcc, 0x36, 0x91, 0x7b,
+	0xb0, 0x2e, 0x5d, 0x18, 0x85, 0x26, 0x21, 0xd8, 0x60, 0xc2, 0x85, 0xf5, 0x95, 0x3c, 0xb9, 0xbd,
+	0x49, 0x81, 0xb6, 0x7f, 0xb3, 0xe0, 0xea, 0x85, 0xa6, 0x4a, 0xea, 0xd0, 0x7a, 0xd7, 0x3a, 0xac,
+	0x94, 0xd7, 0x21, 0x56, 0xbb, 0x68, 0x65, 0x84, 0xbf, 0x8a, 0xfd, 0x5f, 0x33, 0xb3, 0xcc, 0x0f,
+	0x3c, 0x7f, 0xac, 0x61, 0xc6, 0x89, 0xa1, 0x49, 0xb2, 0x09, 0x0d, 0x5c, 0x2e, 0xe2, 0x48, 0x22,
+	0x5a, 0x75, 0x35, 0x65, 0x0f, 0xa1, 0x79, 0x10, 0x26, 0x0b, 0x01, 0x3a, 0x96, 0x11, 0x32, 0xd9,
+	0xb9, 0x2c, 0xcc, 0xb6, 0xab, 0x08, 0xb2, 0x07, 0x8d, 0xb9, 0x0c, 0x41, 0xfa, 0xf1, 0x76, 0x3c,
+	0xb5, 0xa6, 0xbd, 0x0b, 0xdd, 0x97, 0x61, 0x32, 0x9e, 0x32, 0x4f, 0x62, 0x26, 0x4e, 0x56, 0xb9,
+	0xb7, 0xa4, 0x53, 0x8a, 0xb0, 0xff, 0xb4, 0x60, 0x53, 0xdb, 0x5e, 0xad, 0xcd, 0x9b, 0xd0, 0x15,
+	0x3a, 0x08, 0x81, 0x14, 0xeb, 0x54, 0xb6, 0x1c, 0xad, 0xee, 0x76, 0x84, 0xd4, 0xf8, 0x7d, 0x1b,
+	0x7a, 0x3a, 0xfb, 0x46, 0xbd, 0xb9, 0xa2, 0xbe, 0xa6, 0xe4, 0x66, 0xc3, 0x1d, 0xe8, 0xea, 0x0d,
+	0xca, 0x2b, 0x35, 0x1d, 0xd7, 0x9c, 0xbc, 0xcf, 0x6e, 0x47, 0xa9, 0xa8, 0x00, 0x3e, 0x80, 0x8e,
+	0xaa, 0x0a, 0xac, 0x7d, 0x39, 0x0b, 0x45, 0x18, 0x20, 0x59, 0x4f, 0x05, 0xc7, 0xfe, 0xc9, 0x02,
+	0x78, 0xb5, 0x3f, 0x7c, 0x79, 0x30, 0xa5, 0xc1, 0x09, 0x23, 0xef, 0x43, 0x5b, 0xfa, 0x9f, 0x1b,
+	0x47, 0x2d, 0xc1, 0x78, 0x26, 0x46, 0xd2, 0x36, 0xce, 0xd5, 0x68, 0x3c, 0x3a, 0x66, 0x93, 0x30,
+	0x62, 0xfa, 0x72, 0x69, 0x23, 0xe7, 0xa1, 0x64, 0x88, 0xbd, 0x42, 0x4c, 0x27, 0x58, 0x94, 0xfa,
+	0x82, 0x69, 0x21, 0x63, 0x5f, 0xd0, 0xc2, 0x91, 0x84, 0xf2, 0xd8, 0x6c, 0xae, 0xa9, 0xa1, 0x2c,
+	0x58, 0x7a, 0x37, 0x1e, 0x2e, 0x15, 0xd4, 0xf6, 0xba, 0x3a, 0x5c, 0x70, 0xe4, 0x7e, 0xfb, 0x01,
+	0x6c, 0x65, 0x6e, 0xf2, 0x21, 0xc5, 0xab, 0xce, 0x60, 0xfe, 0x21, 0x0e, 0x5c, 0xc5, 0xd6, 0x93,
+	0xa9, 0xe3, 0x64, 0xaa, 0xae, 0x91, 0xd9, 0x7f, 0x58, 0xd0, 0x1b, 0x4e, 0xc3, 0x18, 0xa3, 0xc6,
+	0x74, 0x8d, 0xc3, 0xc8, 0x13, 0x95, 0x18, 0x2f, 0x17, 0xe9, 0xdc, 0x15, 0xeb, 0x74, 0x16, 0x57,
+	0x72, 0xb3, 0x18, 0x79, 0x02, 0x04, 0x1d, 0x94, 0x5c, 0x93, 0xcf, 0xa1, 0x85, 0x59, 0x13, 0x0d,
+	0x68, 0x26, 0xc3, 0xb6, 0x53, 0x3c, 0x5e, 0x64, 0x51, 0xca, 0xd5, 0x4c, 0x4c, 0xd5, 0x07, 0x5f,
+	0xc0, 0x5a, 0x41, 0xf4, 0xbf, 0x26, 0xe3, 0x23, 0xd8, 0x32, 0x66, 0x56, 0x8b, 0xef, 0x63, 0x68,
+	0x46, 0xd2, 0xb2, 0x01, 0x62, 0x7d, 0xc5, 0x23, 0xd7, 0xc8, 0xed, 0xbf, 0x2c, 0xe8, 0x88, 0x0a,
+	0x79, 0xe2, 0x73, 0x79, 0xfb, 0xe7, 0x6e, 0x6c, 0xd5, 0x44, 0xe9, 0x8d, 0xfd, 0x1a, 0xae, 0x68,
+	0x04, 0x47, 0xc7, 0xcb, 0x91, 0xc7, 0x4e, 0xd9, 0x2c, 0xc4, 0xeb, 0x19, 0x1d, 0x13, 0x16, 0x76,
+	0x9d, 0xdc, 0x29, 0x8e, 0xce, 0xce, 0xc3, 0xe5, 0x23, 0xa3, 0xa6, 0x42, 0x27, 0xe3, 0x37, 0x04,
+	0x83, 0xaf, 0x60, 0xeb, 0x02, 0xf5, 0x12, 0x38, 0x76, 0xf2, 0x70, 0x74, 0xf6, 0xc0, 0x11, 0xc5,
+	0x3b, 0x8c, 0x69, 0xcc, 0xf3, 0xd0, 0xfc, 0x63, 0x41, 0x3f, 0xe7, 0x8e, 0x82, 0xe5, 0x08, 0xa3,
+	0xa7, 0x58, 0xd9, 0xf7, 0xf3, 0xad, 0xbc, 0xe2, 0x78, 0x41, 0x53, 0x8d, 0x4b, 0x7d, 0x8f, 0xa9,
+	0xa9, 0x8e, 0x95, 0x8d, 0x81, 0x8f, 0xd4, 0x90, 0xa9, 0x48, 0x7c, 0x5a, 0xc8, 0x38, 0x94, 0x73,
+	0xe6, 0x36, 0x6c, 0x64, 0xcf, 0xa8, 0x11, 0x0e, 0x98, 0x89, 0x7f, 0xce, 0x3c, 0x59, 0x2b, 0x2d,
+	0x97, 0x64, 0xa2, 0x17, 0x5a, 0x32, 0x78, 0x0c, 0x90, 0x99, 0x28, 0x79, 0x75, 0xd8, 0xc5, 0x60,
+	0xbb, 0x05, 0x4f, 0x73, 0xe1, 0xbe, 0x82, 0x76, 0x0a, 0x83, 0x28, 0x18, 0xea, 0x79, 0x68, 0x57,
+	0xa1, 0xa6, 0x08, 0x91, 0xd6, 0x88, 0xcd, 0xc3, 0x53, 0xe4, 0xab, 0x42, 0x32, 0xa4, 0x4c, 0xb8,
+	0x84, 0xdf, 0xd3, 0xcf, 0x05, 0x43, 0x8a, 0x3e, 0x69, 0x60, 0x42, 0x1e, 0xd1, 0x95, 0xaa, 0x28,
+	0xbc, 0xe3, 0x30, 0x21, 0x5c, 0xd8, 0x2d, 0x4b, 0x88, 0x14, 0x90, 0xcf, 0xa0, 0x3d, 0xc3, 0x03,
+	0x13, 0x2a, 0xfa, 0xb2, 0x2a, 0x31, 0xdf, 0x74, 0xd4, 0xb9, 0xce, 0x53, 0x23, 0x50, 0x28, 0x67,
+	0x8a, 0x83, 0x27, 0xd0, 0x2b, 0x0a, 0x4b, 0xf0, 0x79, 0xb7, 0x62, 0xe0, 0xd0, 0x44, 0x53, 0x68,
+	0x90, 0xe3, 0xd3, 0xb6, 0x86, 0xd9, 0x32, 0x99, 0x27, 0x8e, 0xe6, 0x0b, 0x6f, 0xb4, 0x07, 0x52,
+	0x3e, 0x78, 0x00, 0xed, 0x94, 0x55, 0x52, 0x84, 0xdb, 0x45, 0xbb, 0x4d, 0x1d, 0x4d, 0xde, 0xe8,
+	0xcf, 0x16, 0x6c, 0x88, 0x23, 0x56, 0x3b, 0x73, 0x4f, 0x5c, 0x79, 0x4b, 0xe3, 0xc1, 0x35, 0xa7,
+	0x44, 0x47, 0x78, 0x95, 0x7a, 0x83, 0xcb, 0xb7, 0x16, 0x1d, 0x3e, 0x61, 0xdb, 0xa9, 0x7e, 0x89,
+	0xab, 0xd7, 0x8a, 0xae, 0xb6, 0x4c, 0xc8, 0x79, 0x5f, 0xbf, 0x86, 0xf6, 0x90, 0x05, 0xe2, 0xb9,
+	0x1d, 0xc4, 0xd9, 0xbc, 0x11, 0x87, 0x54, 0xb4, 0x9a, 0x78, 0x47, 0x89, 0x84, 0xa3, 0x02, 0x37,
+	0x1e, 0x18, 0x3a, 0x5f, 0x1b, 0xd5, 0xc2, 0xc4, 0xb0, 0x7f, 0xb7, 0xb0, 0xb5, 0x95, 0x5a, 0x6a,
+	0xc0, 0x00, 0xf1, 0x1a, 0x2e, 0x71, 0xc3, 0x93, 0xf3, 0x84, 0x2e, 0x35, 0x28, 0xb7, 0x9c, 0x0b,
+	0xf6, 0x38, 0x29, 0x03, 0x07, 0x05, 0x5d, 0xea, 0x27, 0x3f, 0x2f, 0x30, 0x07, 0x47, 0xb0, 0x51,
+	0xa2, 0xf6, 0x2e, 0x93, 0x24, 0x33, 0x97, 0xc3, 0xe6, 0x1b, 0x80, 0x03, 0x19, 0x8d, 0x68, 0xbd,
+	0xd2, 0xe7, 0x39, 0x42, 0x63, 0xaa, 0xd6, 0xdc, 0x75, 0x86, 0xce, 0x9a, 0xa3, 0x76, 0x41, 0x73,
+	0xd8, 0x3f, 0x62, 0x8f, 0x29, 0x03, 0xe9, 0x5f, 0xcd, 0xca, 0xfd, 0xd5, 0xf0, 0xcd, 0x75, 0x36,
+	0x65, 0xf9, 0xaf, 0x58, 0x45, 0xfe, 0x95, 0xba, 0x82, 0x9b, 0xfe, 0xb2, 0xf0, 0x65, 0x44, 0x93,
+	0x78, 0x1a, 0x46, 0xba, 0x83, 0x35, 0x45, 0xae, 0x17, 0x1f, 0xac, 0x1d, 0x27, 0x0b, 0xc5, 0x0c,
+	0xb4, 0xe2, 0x67, 0xb0, 0xbe, 0xfa, 0x19, 0xc4, 0xf8, 0x37, 0xd5, 0xa6, 0x37, 0x2a, 0xf9, 0x7a,
+	0xf1, 0xa2, 0x10, 0x6d, 0xa0, 0x34, 0xb3, 0xd9, 0x70, 0x1d, 0xba, 0xca, 0x93, 0x42, 0xed, 0x76,
+	0x14, 0x4f, 0x96, 0xaf, 0xfd, 0xab, 0x05, 0xeb, 0x6f, 0x9e, 0xdc, 0x98, 0x32, 0xea, 0xe1, 0xd5,
+	0x62, 0x49, 0xd8, 0xda, 0xe9, 0xa7, 0xcf, 0xd5, 0x02, 0x9c, 0xe1, 0x58, 0x7f, 0x78, 0x6f, 0x9a,
+	0x7a, 0x14, 0xad, 0xb4, 0xda, 0x46, 0x07, 0x5a, 0x21, 0xbd, 0x74, 0x15, 0xa9, 0x2e, 0xdd, 0x9c,
+	0xe8, 0xbf, 0xbe, 0x7b, 0xdd, 0x5c, 0x3d, 0x1c, 0x37, 0xe4, 0xf7, 0xfb, 0xee, 0xbf, 0xbb, 0xd5,
+	0x9d, 0x40, 0x8a, 0x0f, 0x00, 0x00,
 }
@@ -2,6 +2,7 @@ package plumbing
 
 import (
 	"bytes"
+	"encoding/gob"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -267,6 +268,45 @@ func (blobCache *BlobCache) Fork(n int) []core.PipelineItem {
 	return caches
 }
 
+// SaveState writes the hashes of the cached blobs. The blobs themselves are reloaded
+// from the repository in LoadState().
+func (blobCache *BlobCache) SaveState(writer io.Writer) error {
+	hashes := make([]plumbing.Hash, 0, len(blobCache.cache))
+	for hash := range blobCache.cache {
+		hashes = append(hashes, hash)
+	}
+	return gob.NewEncoder(writer).Encode(hashes)
+}
+
+// LoadState reads the hashes of the cached blobs and loads them from the repository.
+func (blobCache *BlobCache) LoadState(reader io.Reader) error {
+	var hashes []plumbing.Hash
+	err := gob.NewDecoder(reader).Decode(&hashes)
+	if err != nil {
+		return err
+	}
+	blobCache.cache = map[plumbing.Hash]*CachedBlob{}
+	for _, hash := range hashes {
+		blob, err := blobCache.repository.BlobObject(hash)
+		if err != nil {
+			if err.Error() != plumbing.ErrObjectNotFound.Error() {
+				return err
+			}
+			// submodules and other missing objects
+			blob, _ = internal.CreateDummyBlob(hash)
+			blobCache.cache[hash] = &CachedBlob{Blob: *blob}
+			continue
+		}
+		cb := &CachedBlob{Blob: *blob}
+		err = cb.Cache()
+		if err != nil {
+			return err
+		}
+		blobCache.cache[hash] = cb
+	}
+	return nil
+}
+
 // FileGetter defines a function which loads the Git file by
 // the specified path. The state can be arbitrary though here it always
 // corresponds to the currently processed commit.
@@ -1,6 +1,7 @@
 package plumbing
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -447,3 +448,27 @@ func TestBlobCacheFork(t *testing.T) {
 	// just for the sake of it
 	cache1.Merge([]core.PipelineItem{cache2})
 }
+
+func TestBlobCacheSaveLoadState(t *testing.T) {
+	cache := fixtureBlobCache()
+	hashes := []string{
+		"1cacfc1bf0f048eb2f31973750983ae5d8de647a",
+		"c872b8d2291a5224e2c9f6edd7f46039b96b4742",
+		"ffffffffffffffffffffffffffffffffffffffff",
+	}
+	for _, hash := range hashes[:2] {
+		AddHash(t, cache.cache, hash)
+	}
+	blob, _ := internal.CreateDummyBlob(plumbing.NewHash(hashes[2]))
+	cache.cache[plumbing.NewHash(hashes[2])] = &CachedBlob{Blob: *blob}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, cache.SaveState(buffer))
+	cache2 := fixtureBlobCache()
+	assert.Nil(t, cache2.LoadState(buffer))
+	assert.Len(t, cache2.cache, 3)
+	for _, hash := range hashes {
+		h := plumbing.NewHash(hash)
+		assert.Equal(t, cache.cache[h].Size, cache2.cache[h].Size)
+		assert.Equal(t, cache.cache[h].Data, cache2.cache[h].Data)
+	}
+}
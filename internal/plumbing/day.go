@@ -1,6 +1,8 @@
 package plumbing
 
 import (
+	"encoding/gob"
+	"io"
 	"log"
 	"time"
 
@@ -130,6 +132,47 @@ func (days *DaysSinceStart) Fork(n int) []core.PipelineItem {
 	return core.ForkCopyPipelineItem(days, n)
 }
 
+// Sequential returns true because the forked clones share the day index of the commits
+// and must fill it in the order of the execution plan.
+func (days *DaysSinceStart) Sequential() bool {
+	return true
+}
+
+// daysSinceStartState is the serialized state of DaysSinceStart.
+type daysSinceStartState struct {
+	Day0        time.Time
+	PreviousDay int
+	Commits     map[int][]plumbing.Hash
+}
+
+// SaveState writes the first day, the last day and the commits by day.
+func (days *DaysSinceStart) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(daysSinceStartState{
+		Day0:        *days.day0,
+		PreviousDay: days.previousDay,
+		Commits:     days.commits,
+	})
+}
+
+// LoadState restores the state written by SaveState(). The commits by day are loaded
+// in-place because they are shared with FactCommitsByDay.
+func (days *DaysSinceStart) LoadState(reader io.Reader) error {
+	state := daysSinceStartState{}
+	err := gob.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return err
+	}
+	*days.day0 = state.Day0
+	days.previousDay = state.PreviousDay
+	for key := range days.commits {
+		delete(days.commits, key)
+	}
+	for key, val := range state.Commits {
+		days.commits[key] = val
+	}
+	return nil
+}
+
 func init() {
 	core.Registry.Register(&DaysSinceStart{})
 }
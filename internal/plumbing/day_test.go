@@ -154,3 +154,31 @@ func TestDaysSinceStartConsumeZero(t *testing.T) {
 	assert.Equal(t, dss.day0.Minute(), 0)
 	assert.Equal(t, dss.day0.Second(), 0)
 }
+
+func TestDaysSinceStartSaveLoadState(t *testing.T) {
+	dss := fixtureDaysSinceStart()
+	deps := map[string]interface{}{}
+	commit, _ := test.Repository.CommitObject(plumbing.NewHash(
+		"cce947b98a050c6d356bc6ba95030254914027b1"))
+	deps[core.DependencyCommit] = commit
+	deps[core.DependencyIndex] = 0
+	_, err := dss.Consume(deps)
+	assert.Nil(t, err)
+	commit, _ = test.Repository.CommitObject(plumbing.NewHash(
+		"fc9ceecb6dabcb2aab60e8619d972e8d8208a7df"))
+	deps[core.DependencyCommit] = commit
+	deps[core.DependencyIndex] = 1
+	_, err = dss.Consume(deps)
+	assert.Nil(t, err)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, dss.SaveState(buffer))
+	dss2 := fixtureDaysSinceStart()
+	commits := dss2.commits
+	assert.Nil(t, dss2.LoadState(buffer))
+	assert.True(t, dss.day0.Equal(*dss2.day0))
+	assert.Equal(t, dss.previousDay, dss2.previousDay)
+	assert.Equal(t, dss.commits, dss2.commits)
+	// FactCommitsByDay must stay valid
+	assert.Equal(t, commits, dss2.commits)
+	assert.Len(t, commits, len(dss.commits))
+}
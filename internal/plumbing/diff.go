@@ -16,6 +16,7 @@ import (
 // It is a PipelineItem.
 type FileDiff struct {
 	core.NoopMerger
+	core.NoopCheckpointer
 	CleanupDisabled  bool
 	WhitespaceIgnore bool
 }
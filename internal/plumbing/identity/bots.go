@@ -0,0 +1,126 @@
+package identity
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BotsKeep treats the bots the same way as the other developers.
+	BotsKeep = "keep"
+	// BotsDrop excludes the commits of the bots from the people-aware analyses.
+	BotsDrop = "drop"
+	// BotsAggregate merges all the bots into a single identity named BotsName.
+	BotsAggregate = "aggregate"
+	// BotsName is the name of the synthetic identity which aggregates all the bots.
+	BotsName = "<bots>"
+
+	// peopleDictBotTag marks the bot identities in the people dict file.
+	peopleDictBotTag = "[bot] "
+)
+
+// DefaultBotPatterns are the regular expressions which match the names and the emails of
+// the popular bots and automation accounts: GitHub Apps, Dependabot, Renovate, release bots, etc.
+var DefaultBotPatterns = []string{
+	`\[bot\]`,
+	`\bbot\b`,
+	`^(dependabot|renovate|greenkeeper|github-actions|snyk-bot|semantic-release-bot)\b`,
+}
+
+// checkBotsMode validates the value of ConfigIdentityDetectorBots.
+// The empty value is the same as BotsKeep.
+func checkBotsMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return BotsKeep, nil
+	case BotsKeep, BotsDrop, BotsAggregate:
+		return mode, nil
+	}
+	return "", errors.Errorf("unknown bots mode %q, must be one of %s, %s, %s",
+		mode, BotsKeep, BotsDrop, BotsAggregate)
+}
+
+// DetectBots classifies the first `peopleCount` identities in PeopleDict: an identity is a bot
+// if any of its names or emails matches any of BotPatterns (DefaultBotPatterns if nil).
+// The identities marked in the people dict file are always bots. The result maps the indices
+// of the bots to true.
+func (detector *Detector) DetectBots(peopleCount int) (map[int]bool, error) {
+	patterns := detector.BotPatterns
+	if patterns == nil {
+		patterns = DefaultBotPatterns
+	}
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, errors.Errorf("invalid bot pattern %q: %v", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	bots := map[int]bool{}
+	for id := range detector.botAnnotations {
+		if id < peopleCount {
+			bots[id] = true
+		}
+	}
+	for key, id := range detector.PeopleDict {
+		if id >= peopleCount || bots[id] {
+			continue
+		}
+		for _, re := range regexps {
+			if re.MatchString(key) {
+				bots[id] = true
+				break
+			}
+		}
+	}
+	return bots, nil
+}
+
+// AggregateBots merges the specified bot identities into a single identity named BotsName
+// which follows the rest of the first `peopleCount` identities. It returns the new number of
+// identities and the index of the merged identity.
+func (detector *Detector) AggregateBots(bots map[int]bool, peopleCount int) (int, int) {
+	mapping := make([]int, peopleCount)
+	var reversedPeopleDict []string
+	for id := 0; id < peopleCount; id++ {
+		if !bots[id] {
+			mapping[id] = len(reversedPeopleDict)
+			reversedPeopleDict = append(reversedPeopleDict, detector.ReversedPeopleDict[id])
+		}
+	}
+	botsID := len(reversedPeopleDict)
+	reversedPeopleDict = append(reversedPeopleDict, BotsName)
+	// keep the trailing AuthorMissingName of the loaded people dict
+	reversedPeopleDict = append(reversedPeopleDict, detector.ReversedPeopleDict[peopleCount:]...)
+	for id := range bots {
+		mapping[id] = botsID
+	}
+	peopleDict := make(map[string]int, len(detector.PeopleDict))
+	for key, id := range detector.PeopleDict {
+		if id < peopleCount {
+			id = mapping[id]
+		}
+		peopleDict[key] = id
+	}
+	detector.PeopleDict = peopleDict
+	detector.ReversedPeopleDict = reversedPeopleDict
+	if len(detector.botAnnotations) > 0 {
+		detector.botAnnotations = map[int]bool{botsID: true}
+	}
+	return botsID + 1, botsID
+}
+
+// parseBotTag removes the bot mark from the line of the people dict file and reports whether
+// it was present.
+func parseBotTag(line string) (string, bool) {
+	if strings.HasPrefix(line, peopleDictBotTag) {
+		return strings.TrimSpace(line[len(peopleDictBotTag):]), true
+	}
+	return line, false
+}
@@ -0,0 +1,129 @@
+package identity
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixtureBotsDetector() *Detector {
+	return &Detector{
+		PeopleDict: map[string]int{
+			"vadim":              0,
+			"vadim@sourced.tech": 0,
+			"dependabot[bot]":    1,
+			"49699333+dependabot[bot]@users.noreply.github.com": 1,
+			"egor":                2,
+			"egor@sourced.tech":   2,
+			"renovate bot":        3,
+			"bot@renovateapp.com": 3,
+			"abbot":               4,
+			"abbot@sourced.tech":  4,
+		},
+		ReversedPeopleDict: []string{
+			"vadim|vadim@sourced.tech",
+			"dependabot[bot]|49699333+dependabot[bot]@users.noreply.github.com",
+			"egor|egor@sourced.tech",
+			"renovate bot|bot@renovateapp.com",
+			"abbot|abbot@sourced.tech",
+		},
+	}
+}
+
+func TestDetectBots(t *testing.T) {
+	id := fixtureBotsDetector()
+	bots, err := id.DetectBots(5)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{1: true, 3: true}, bots)
+	bots, err = id.DetectBots(2)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{1: true}, bots)
+	id.BotPatterns = []string{"", "^EGOR$"}
+	bots, err = id.DetectBots(5)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{2: true}, bots)
+	id.BotPatterns = []string{}
+	bots, err = id.DetectBots(5)
+	assert.Nil(t, err)
+	assert.Len(t, bots, 0)
+	id.botAnnotations = map[int]bool{4: true}
+	bots, err = id.DetectBots(5)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{4: true}, bots)
+	id.BotPatterns = []string{"("}
+	_, err = id.DetectBots(5)
+	assert.NotNil(t, err)
+}
+
+func TestAggregateBots(t *testing.T) {
+	id := fixtureBotsDetector()
+	id.ReversedPeopleDict = append(id.ReversedPeopleDict, AuthorMissingName)
+	count, botsID := id.AggregateBots(map[int]bool{1: true, 3: true}, 5)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 3, botsID)
+	assert.Equal(t, []string{
+		"vadim|vadim@sourced.tech", "egor|egor@sourced.tech", "abbot|abbot@sourced.tech",
+		BotsName, AuthorMissingName}, id.ReversedPeopleDict)
+	assert.Equal(t, 0, id.PeopleDict["vadim"])
+	assert.Equal(t, 3, id.PeopleDict["dependabot[bot]"])
+	assert.Equal(t, 1, id.PeopleDict["egor@sourced.tech"])
+	assert.Equal(t, 3, id.PeopleDict["bot@renovateapp.com"])
+	assert.Equal(t, 2, id.PeopleDict["abbot"])
+	// idempotent
+	bots, err := id.DetectBots(count)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{3: true}, bots)
+	count, botsID = id.AggregateBots(bots, count)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 3, botsID)
+	assert.Len(t, id.ReversedPeopleDict, 5)
+}
+
+func TestIdentityDetectorConfigureBots(t *testing.T) {
+	for _, mode := range []string{BotsKeep, BotsDrop, BotsAggregate} {
+		id := fixtureBotsDetector()
+		facts := map[string]interface{}{ConfigIdentityDetectorBots: mode}
+		assert.Nil(t, id.Configure(facts))
+		assert.Equal(t, mode, id.BotsMode)
+		if mode == BotsAggregate {
+			assert.Equal(t, 4, facts[FactIdentityDetectorPeopleCount])
+			assert.Equal(t, map[int]bool{3: true}, facts[FactIdentityDetectorBots])
+			assert.Equal(t, BotsName, id.ReversedPeopleDict[3])
+		} else {
+			assert.Equal(t, 5, facts[FactIdentityDetectorPeopleCount])
+			assert.Equal(t, map[int]bool{1: true, 3: true}, facts[FactIdentityDetectorBots])
+		}
+		assert.Equal(t, id.ReversedPeopleDict, facts[FactIdentityDetectorReversedPeopleDict])
+	}
+	id := fixtureBotsDetector()
+	assert.NotNil(t, id.Configure(map[string]interface{}{ConfigIdentityDetectorBots: "ignore"}))
+	assert.NotNil(t, id.Configure(map[string]interface{}{
+		ConfigIdentityDetectorBotPatterns: []string{"["}}))
+}
+
+func TestIdentityDetectorLoadPeopleDictBots(t *testing.T) {
+	tmpf, err := ioutil.TempFile("", "hercules-test-")
+	assert.Nil(t, err)
+	defer os.Remove(tmpf.Name())
+	_, err = tmpf.WriteString(`Egor|egor@sourced.tech
+[bot] CI|ci@sourced.tech
+Vadim|vadim@sourced.tech`)
+	assert.Nil(t, err)
+	assert.Nil(t, tmpf.Close())
+	id := Detector{}
+	assert.Nil(t, id.LoadPeopleDict(tmpf.Name()))
+	assert.Equal(t, []string{"Egor", "CI", "Vadim", AuthorMissingName}, id.ReversedPeopleDict)
+	assert.Equal(t, 1, id.PeopleDict["ci@sourced.tech"])
+	facts := map[string]interface{}{
+		ConfigIdentityDetectorPeopleDictPath: tmpf.Name(),
+		ConfigIdentityDetectorBots:           BotsAggregate,
+	}
+	id = Detector{}
+	assert.Nil(t, id.Configure(facts))
+	assert.Equal(t, map[int]bool{2: true}, facts[FactIdentityDetectorBots])
+	assert.Equal(t, 3, facts[FactIdentityDetectorPeopleCount])
+	assert.Equal(t, []string{"Egor", "Vadim", BotsName, AuthorMissingName}, id.ReversedPeopleDict)
+	assert.Equal(t, 2, id.PeopleDict["ci"])
+}
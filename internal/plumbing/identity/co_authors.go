@@ -0,0 +1,30 @@
+package identity
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// coAuthoredByTrailer is the commit message trailer which GitHub and GitLab use
+// to mention the co-authors of the commit.
+const coAuthoredByTrailer = "co-authored-by:"
+
+// ParseCoAuthors extracts the signatures from the "Co-authored-by: Name <email>" trailers
+// in the commit message. The trailer key is case-insensitive, the malformed trailers are skipped.
+func ParseCoAuthors(message string) []object.Signature {
+	var coAuthors []object.Signature
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(coAuthoredByTrailer) ||
+			!strings.EqualFold(line[:len(coAuthoredByTrailer)], coAuthoredByTrailer) {
+			continue
+		}
+		name, email, _, ok := parseNameAndEmail(line[len(coAuthoredByTrailer):])
+		if !ok || (name == "" && email == "") {
+			continue
+		}
+		coAuthors = append(coAuthors, object.Signature{Name: name, Email: email})
+	}
+	return coAuthors
+}
@@ -0,0 +1,23 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	coAuthors := ParseCoAuthors(`Fix the bug
+
+Co-authored-by: Jane Doe <jane@example.com>
+co-authored-by: Bob <bob@example.com>
+Co-authored-by: broken
+Signed-off-by: Joe <joe@example.com>
+`)
+	assert.Equal(t, []object.Signature{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "Bob", Email: "bob@example.com"},
+	}, coAuthors)
+	assert.Nil(t, ParseCoAuthors("Fix the bug\n\nSigned-off-by: Joe <joe@example.com>"))
+}
@@ -2,6 +2,9 @@ package identity
 
 import (
 	"bufio"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
 	"os"
 	"sort"
 	"strings"
@@ -21,6 +24,21 @@ type Detector struct {
 	PeopleDict map[string]int
 	// ReversedPeopleDict maps developer id -> description
 	ReversedPeopleDict []string
+	// Mailmap resolves the commit signatures before they are matched with PeopleDict
+	Mailmap *Mailmap
+	// CoAuthors indicates whether the "Co-authored-by:" trailers should be taken into account
+	CoAuthors bool
+	// BotPatterns are the regular expressions which classify the identities as bots,
+	// DefaultBotPatterns if nil
+	BotPatterns []string
+	// BotsMode defines what happens to the bots: BotsKeep, BotsDrop or BotsAggregate
+	BotsMode string
+	// Signatures chooses the commit signatures which identify the developers:
+	// SignaturesAuthor, SignaturesCommitter or SignaturesBoth
+	Signatures string
+
+	// botAnnotations are the identities which are marked as bots in the people dict file
+	botAnnotations map[int]bool
 }
 
 const (
@@ -45,9 +63,37 @@ const (
 	// Detector.Configure(). It is equal to the overall number of unique authors
 	// (the length of ReversedPeopleDict).
 	FactIdentityDetectorPeopleCount = "IdentityDetector.PeopleCount"
+	// ConfigIdentityDetectorMailmapPath is the name of the configuration option
+	// (Detector.Configure()) which allows to apply the mailmap file in addition to .mailmap
+	// in the repository.
+	ConfigIdentityDetectorMailmapPath = "IdentityDetector.MailmapPath"
+	// ConfigIdentityDetectorCoAuthors is the name of the configuration option
+	// (Detector.Configure()) which enables the detection of the co-authors in
+	// the "Co-authored-by:" commit message trailers.
+	ConfigIdentityDetectorCoAuthors = "IdentityDetector.CoAuthors"
+	// ConfigIdentityDetectorBotPatterns is the name of the configuration option
+	// (Detector.Configure()) which sets the regular expressions to detect the bots.
+	ConfigIdentityDetectorBotPatterns = "IdentityDetector.BotPatterns"
+	// ConfigIdentityDetectorBots is the name of the configuration option
+	// (Detector.Configure()) which chooses what to do with the bots: BotsKeep, BotsDrop or
+	// BotsAggregate. The leaves read it to exclude the commits of the bots.
+	ConfigIdentityDetectorBots = "IdentityDetector.Bots"
+	// FactIdentityDetectorBots is the name of the fact which is inserted in
+	// Detector.Configure(). It maps the indices of the bot identities to true.
+	FactIdentityDetectorBots = "IdentityDetector.BotIdentities"
+	// ConfigIdentityDetectorSignatures is the name of the configuration option
+	// (Detector.Configure()) which chooses the commit signatures which identify the developers:
+	// SignaturesAuthor, SignaturesCommitter or SignaturesBoth.
+	ConfigIdentityDetectorSignatures = "IdentityDetector.Signatures"
 
 	// DependencyAuthor is the name of the dependency provided by Detector.
 	DependencyAuthor = "author"
+	// DependencyAuthors is the name of the dependency provided by Detector. It is the list
+	// of the commit author indices: the author goes first, then the committer if
+	// ConfigIdentityDetectorSignatures is SignaturesBoth, and then the identified co-authors.
+	// There is only the author unless ConfigIdentityDetectorCoAuthors is enabled or both
+	// signatures are taken into account.
+	DependencyAuthors = "authors"
 )
 
 // Name of this PipelineItem. Uniquely identifies the type, used for mapping keys, etc.
@@ -59,7 +105,7 @@ func (detector *Detector) Name() string {
 // Each produced entity will be inserted into `deps` of dependent Consume()-s according
 // to this list. Also used by core.Registry to build the global map of providers.
 func (detector *Detector) Provides() []string {
-	arr := [...]string{DependencyAuthor}
+	arr := [...]string{DependencyAuthor, DependencyAuthors}
 	return arr[:]
 }
 
@@ -77,19 +123,81 @@ func (detector *Detector) ListConfigurationOptions() []core.ConfigurationOption
 		Description: "Path to the file with developer -> name|email associations.",
 		Flag:        "people-dict",
 		Type:        core.PathConfigurationOption,
-		Default:     ""},
+		Default:     ""}, {
+		Name: ConfigIdentityDetectorMailmapPath,
+		Description: "Path to the file in the .mailmap format which is applied after .mailmap " +
+			"in the repository.",
+		Flag:    "mailmap",
+		Type:    core.PathConfigurationOption,
+		Default: ""}, {
+		Name: ConfigIdentityDetectorCoAuthors,
+		Description: "Detect the co-authors in the \"Co-authored-by:\" commit message trailers. " +
+			"The analyses credit them according to their own co-authors options.",
+		Flag:    "co-authors",
+		Type:    core.BoolConfigurationOption,
+		Default: false}, {
+		Name: ConfigIdentityDetectorBotPatterns,
+		Description: "Case-insensitive regular expressions which detect the bots by any of " +
+			"the names or emails. Separated with commas \",\".",
+		Flag:    "bot-patterns",
+		Type:    core.StringsConfigurationOption,
+		Default: DefaultBotPatterns}, {
+		Name: ConfigIdentityDetectorBots,
+		Description: "What to do with the bots: \"keep\" them as developers, \"drop\" their " +
+			"commits from the people statistics or \"aggregate\" them as a single <bots> identity.",
+		Flag:    "bots",
+		Type:    core.StringConfigurationOption,
+		Default: BotsKeep}, {
+		Name: ConfigIdentityDetectorSignatures,
+		Description: "Which commit signatures identify the developers: \"author\", \"committer\" " +
+			"or \"both\". The analyses credit the committers in the latter case according to " +
+			"their co-authors options.",
+		Flag:    "signatures",
+		Type:    core.StringConfigurationOption,
+		Default: SignaturesAuthor},
 	}
 	return options[:]
 }
 
 // Configure sets the properties previously published by ListConfigurationOptions().
 func (detector *Detector) Configure(facts map[string]interface{}) error {
+	if val, exists := facts[ConfigIdentityDetectorCoAuthors].(bool); exists {
+		detector.CoAuthors = val
+	}
+	if val, exists := facts[ConfigIdentityDetectorBotPatterns].([]string); exists {
+		detector.BotPatterns = val
+	}
+	if val, exists := facts[ConfigIdentityDetectorBots].(string); exists {
+		mode, err := checkBotsMode(val)
+		if err != nil {
+			return err
+		}
+		detector.BotsMode = mode
+	}
+	if val, exists := facts[ConfigIdentityDetectorSignatures].(string); exists {
+		detector.Signatures = val
+	}
+	signatures, err := checkSignatures(detector.Signatures)
+	if err != nil {
+		return err
+	}
+	detector.Signatures = signatures
 	if val, exists := facts[FactIdentityDetectorPeopleDict].(map[string]int); exists {
 		detector.PeopleDict = val
 	}
 	if val, exists := facts[FactIdentityDetectorReversedPeopleDict].([]string); exists {
 		detector.ReversedPeopleDict = val
 	}
+	commits, _ := facts[core.ConfigPipelineCommits].([]*object.Commit)
+	var head *object.Commit
+	if len(commits) > 0 {
+		head = commits[len(commits)-1]
+	}
+	mailmapPath, _ := facts[ConfigIdentityDetectorMailmapPath].(string)
+	if err := detector.LoadMailmap(head, mailmapPath); err != nil {
+		return errors.Errorf("failed to load %s: %v", mailmapPath, err)
+	}
+	var peopleCount int
 	if detector.PeopleDict == nil || detector.ReversedPeopleDict == nil {
 		peopleDictPath, _ := facts[ConfigIdentityDetectorPeopleDictPath].(string)
 		if peopleDictPath != "" {
@@ -97,19 +205,31 @@ func (detector *Detector) Configure(facts map[string]interface{}) error {
 			if err != nil {
 				return errors.Errorf("failed to load %s: %v", peopleDictPath, err)
 			}
-			facts[FactIdentityDetectorPeopleCount] = len(detector.ReversedPeopleDict) - 1
+			peopleCount = len(detector.ReversedPeopleDict) - 1
 		} else {
-			if _, exists := facts[core.ConfigPipelineCommits]; !exists {
-				panic("IdentityDetector needs a list of commits to initialize.")
+			if len(commits) == 0 {
+				return errors.New("IdentityDetector needs a list of commits to initialize")
 			}
-			detector.GeneratePeopleDict(facts[core.ConfigPipelineCommits].([]*object.Commit))
-			facts[FactIdentityDetectorPeopleCount] = len(detector.ReversedPeopleDict)
+			detector.GeneratePeopleDict(commits)
+			peopleCount = len(detector.ReversedPeopleDict)
 		}
 	} else {
-		facts[FactIdentityDetectorPeopleCount] = len(detector.ReversedPeopleDict)
+		peopleCount = len(detector.ReversedPeopleDict)
+	}
+	bots, err := detector.DetectBots(peopleCount)
+	if err != nil {
+		return err
+	}
+	if detector.BotsMode == BotsAggregate && len(bots) > 0 {
+		var botsID int
+		peopleCount, botsID = detector.AggregateBots(bots, peopleCount)
+		bots = map[int]bool{botsID: true}
 	}
+	facts[FactIdentityDetectorPeopleCount] = peopleCount
+	facts[FactIdentityDetectorBots] = bots
 	facts[FactIdentityDetectorPeopleDict] = detector.PeopleDict
 	facts[FactIdentityDetectorReversedPeopleDict] = detector.ReversedPeopleDict
+	facts[core.FactPipelineSignatures] = detector.Signatures
 	return nil
 }
 
@@ -126,15 +246,43 @@ func (detector *Detector) Initialize(repository *git.Repository) error {
 // in Provides(). If there was an error, nil is returned.
 func (detector *Detector) Consume(deps map[string]interface{}) (map[string]interface{}, error) {
 	commit := deps[core.DependencyCommit].(*object.Commit)
-	signature := commit.Author
-	authorID, exists := detector.PeopleDict[strings.ToLower(signature.Email)]
-	if !exists {
-		authorID, exists = detector.PeopleDict[strings.ToLower(signature.Name)]
-		if !exists {
-			authorID = AuthorMissing
+	signatures := detector.commitSignatures(commit)
+	authorID := detector.findAuthor(signatures[0])
+	authors := []int{authorID}
+	addAuthor := func(signature object.Signature) {
+		otherID := detector.findAuthor(signature)
+		if otherID == AuthorMissing {
+			return
+		}
+		for _, id := range authors {
+			if id == otherID {
+				return
+			}
 		}
+		authors = append(authors, otherID)
+	}
+	for _, signature := range signatures[1:] {
+		addAuthor(signature)
 	}
-	return map[string]interface{}{DependencyAuthor: authorID}, nil
+	if detector.CoAuthors {
+		for _, signature := range ParseCoAuthors(commit.Message) {
+			addAuthor(signature)
+		}
+	}
+	return map[string]interface{}{DependencyAuthor: authorID, DependencyAuthors: authors}, nil
+}
+
+// findAuthor returns the index of the developer with the specified signature. The signature
+// is resolved with Mailmap first; the original name and email are checked if the resolved
+// ones are unknown.
+func (detector *Detector) findAuthor(signature object.Signature) int {
+	name, email := detector.Mailmap.Resolve(signature.Name, signature.Email)
+	for _, key := range [...]string{email, name, signature.Email, signature.Name} {
+		if authorID, exists := detector.PeopleDict[strings.ToLower(key)]; exists {
+			return authorID
+		}
+	}
+	return AuthorMissing
 }
 
 // Fork clones this PipelineItem.
@@ -142,9 +290,44 @@ func (detector *Detector) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(detector, n)
 }
 
+// SaveState writes the identities so that LoadState() can ensure that the author indices
+// are the same.
+func (detector *Detector) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(detector.ReversedPeopleDict)
+}
+
+// LoadState checks that the identities written by SaveState() did not change their indices.
+// New identities may be appended and the existing ones may receive new aliases.
+func (detector *Detector) LoadState(reader io.Reader) error {
+	var reversedPeopleDict []string
+	err := gob.NewDecoder(reader).Decode(&reversedPeopleDict)
+	if err != nil {
+		return err
+	}
+	if len(reversedPeopleDict) > len(detector.ReversedPeopleDict) {
+		return errors.Errorf("%d identities were saved but only %d are known now",
+			len(reversedPeopleDict), len(detector.ReversedPeopleDict))
+	}
+	for i, saved := range reversedPeopleDict {
+		current := map[string]bool{}
+		for _, key := range strings.Split(detector.ReversedPeopleDict[i], "|") {
+			current[key] = true
+		}
+		for _, key := range strings.Split(saved, "|") {
+			if !current[key] {
+				return errors.Errorf("the identity #%d changed: %s -> %s",
+					i, saved, detector.ReversedPeopleDict[i])
+			}
+		}
+	}
+	return nil
+}
+
 // LoadPeopleDict loads author signatures from a text file.
 // The format is one signature per line, and the signature consists of several
 // keys separated by "|". The first key is the main one and used to reference all the rest.
+// The line may start with "[bot] " to mark the bot identity. The empty lines and the lines
+// which start with "#" are ignored.
 func (detector *Detector) LoadPeopleDict(path string) error {
 	file, err := os.Open(path)
 	if err != nil {
@@ -154,9 +337,17 @@ func (detector *Detector) LoadPeopleDict(path string) error {
 	scanner := bufio.NewScanner(file)
 	dict := make(map[string]int)
 	var reverseDict []string
+	bots := map[int]bool{}
 	size := 0
 	for scanner.Scan() {
-		ids := strings.Split(scanner.Text(), "|")
+		if isPeopleDictComment(scanner.Text()) {
+			continue
+		}
+		line, bot := parseBotTag(scanner.Text())
+		if bot {
+			bots[size] = true
+		}
+		ids := strings.Split(line, "|")
 		for _, id := range ids {
 			dict[strings.ToLower(id)] = size
 		}
@@ -166,74 +357,87 @@ func (detector *Detector) LoadPeopleDict(path string) error {
 	reverseDict = append(reverseDict, AuthorMissingName)
 	detector.PeopleDict = dict
 	detector.ReversedPeopleDict = reverseDict
+	detector.botAnnotations = bots
+	return nil
+}
+
+// LoadMailmap reads .mailmap from the specified commit and then the mailmap file at the specified
+// path on top of it. Either of them may be absent: `head` may be nil and `path` may be empty.
+func (detector *Detector) LoadMailmap(head *object.Commit, path string) error {
+	mailmap := &Mailmap{}
+	if head != nil {
+		mailmapFile, err := head.File(".mailmap")
+		if err == nil {
+			contents, err := mailmapFile.Contents()
+			if err == nil {
+				mailmap.Parse(contents)
+			}
+		}
+	}
+	if path != "" {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		mailmap.Parse(string(contents))
+	}
+	detector.Mailmap = mailmap
 	return nil
 }
 
 // GeneratePeopleDict loads author signatures from the specified list of Git commits.
+// The signatures are resolved with Mailmap, which is read from the last commit if it was not
+// loaded before. The committers are included instead of or in addition to the authors
+// according to Signatures, and the co-authors are included if CoAuthors is enabled.
 func (detector *Detector) GeneratePeopleDict(commits []*object.Commit) {
 	dict := map[string]int{}
 	emails := map[int][]string{}
 	names := map[int][]string{}
 	size := 0
 
-	mailmapFile, err := commits[len(commits)-1].File(".mailmap")
-	if err == nil {
-		mailMapContents, err := mailmapFile.Contents()
-		if err == nil {
-			mailmap := ParseMailmap(mailMapContents)
-			for key, val := range mailmap {
-				key = strings.ToLower(key)
-				toEmail := strings.ToLower(val.Email)
-				toName := strings.ToLower(val.Name)
-				id, exists := dict[toEmail]
-				if !exists {
-					id, exists = dict[toName]
-				}
-				if exists {
-					dict[key] = id
-				} else {
-					id = size
-					size++
-					if toEmail != "" {
-						dict[toEmail] = id
-						emails[id] = append(emails[id], toEmail)
-					}
-					if toName != "" {
-						dict[toName] = id
-						names[id] = append(names[id], toName)
-					}
-					dict[key] = id
-				}
-				if strings.Contains(key, "@") {
-					exists := false
-					for _, val := range emails[id] {
-						if key == val {
-							exists = true
-							break
-						}
-					}
-					if !exists {
-						emails[id] = append(emails[id], key)
-					}
-				} else {
-					exists := false
-					for _, val := range names[id] {
-						if key == val {
-							exists = true
-							break
-						}
-					}
-					if !exists {
-						names[id] = append(names[id], key)
-					}
-				}
+	if detector.Mailmap == nil {
+		// cannot fail without the path
+		detector.LoadMailmap(commits[len(commits)-1], "")
+	}
+	// the commit and the proper signatures of each mailmap entry belong to the same developer
+	for _, entry := range detector.Mailmap.sortedEntries() {
+		commitEmail, commitName := strings.ToLower(entry[0].Email), strings.ToLower(entry[0].Name)
+		properEmail, properName := strings.ToLower(entry[1].Email), strings.ToLower(entry[1].Name)
+		if properEmail == "" {
+			properEmail = commitEmail
+		}
+		id := -1
+		for _, key := range [...]string{properEmail, properName, commitEmail, commitName} {
+			if key == "" {
+				continue
+			}
+			if existing, exists := dict[key]; exists {
+				id = existing
+				break
+			}
+		}
+		if id < 0 {
+			id = size
+			size++
+		}
+		for _, key := range [...]string{properEmail, commitEmail} {
+			if _, exists := dict[key]; key != "" && !exists {
+				dict[key] = id
+				emails[id] = append(emails[id], key)
+			}
+		}
+		for _, key := range [...]string{properName, commitName} {
+			if _, exists := dict[key]; key != "" && !exists {
+				dict[key] = id
+				names[id] = append(names[id], key)
 			}
 		}
 	}
 
-	for _, commit := range commits {
-		email := strings.ToLower(commit.Author.Email)
-		name := strings.ToLower(commit.Author.Name)
+	addSignature := func(signature object.Signature) {
+		name, email := detector.Mailmap.Resolve(signature.Name, signature.Email)
+		email = strings.ToLower(email)
+		name = strings.ToLower(name)
 		id, exists := dict[email]
 		if exists {
 			_, exists := dict[name]
@@ -241,13 +445,13 @@ func (detector *Detector) GeneratePeopleDict(commits []*object.Commit) {
 				dict[name] = id
 				names[id] = append(names[id], name)
 			}
-			continue
+			return
 		}
 		id, exists = dict[name]
 		if exists {
 			dict[email] = id
 			emails[id] = append(emails[id], email)
-			continue
+			return
 		}
 		dict[email] = size
 		dict[name] = size
@@ -255,6 +459,16 @@ func (detector *Detector) GeneratePeopleDict(commits []*object.Commit) {
 		names[size] = append(names[size], name)
 		size++
 	}
+	for _, commit := range commits {
+		for _, signature := range detector.commitSignatures(commit) {
+			addSignature(signature)
+		}
+		if detector.CoAuthors {
+			for _, signature := range ParseCoAuthors(commit.Message) {
+				addSignature(signature)
+			}
+		}
+	}
 	reverseDict := make([]string, size)
 	for _, val := range dict {
 		sort.Strings(names[val])
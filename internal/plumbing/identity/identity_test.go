@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
@@ -36,11 +37,17 @@ func TestIdentityDetectorMeta(t *testing.T) {
 	id := fixtureIdentityDetector()
 	assert.Equal(t, id.Name(), "IdentityDetector")
 	assert.Equal(t, len(id.Requires()), 0)
-	assert.Equal(t, len(id.Provides()), 1)
+	assert.Equal(t, len(id.Provides()), 2)
 	assert.Equal(t, id.Provides()[0], DependencyAuthor)
+	assert.Equal(t, id.Provides()[1], DependencyAuthors)
 	opts := id.ListConfigurationOptions()
-	assert.Len(t, opts, 1)
+	assert.Len(t, opts, 6)
 	assert.Equal(t, opts[0].Name, ConfigIdentityDetectorPeopleDictPath)
+	assert.Equal(t, opts[1].Name, ConfigIdentityDetectorMailmapPath)
+	assert.Equal(t, opts[2].Name, ConfigIdentityDetectorCoAuthors)
+	assert.Equal(t, opts[3].Name, ConfigIdentityDetectorBotPatterns)
+	assert.Equal(t, opts[4].Name, ConfigIdentityDetectorBots)
+	assert.Equal(t, opts[5].Name, ConfigIdentityDetectorSignatures)
 }
 
 func TestIdentityDetectorConfigure(t *testing.T) {
@@ -126,7 +133,7 @@ func TestIdentityDetectorRegistration(t *testing.T) {
 
 func TestIdentityDetectorConfigureEmpty(t *testing.T) {
 	id := Detector{}
-	assert.Panics(t, func() { id.Configure(map[string]interface{}{}) })
+	assert.NotNil(t, id.Configure(map[string]interface{}{}))
 }
 
 func TestIdentityDetectorConsume(t *testing.T) {
@@ -381,6 +388,103 @@ func TestIdentityDetectorGeneratePeopleDictMailmap(t *testing.T) {
 		"strange guy|vadim markovtsev|gmarkhor@gmail.com|vadim@sourced.tech")
 }
 
+func TestIdentityDetectorConfigureMailmap(t *testing.T) {
+	tmpf, err := ioutil.TempFile("", "hercules-test-")
+	assert.Nil(t, err)
+	defer os.Remove(tmpf.Name())
+	_, err = tmpf.WriteString("Vadim <vadim@sourced.tech> <gmarkhor@gmail.com>\n")
+	assert.Nil(t, err)
+	assert.Nil(t, tmpf.Close())
+	commits := make([]*object.Commit, 0)
+	iter, err := test.Repository.CommitObjects()
+	commit, err := iter.Next()
+	for ; err != io.EOF; commit, err = iter.Next() {
+		if err != nil {
+			panic(err)
+		}
+		commits = append(commits, commit)
+	}
+	facts := map[string]interface{}{
+		core.ConfigPipelineCommits:        commits,
+		ConfigIdentityDetectorMailmapPath: tmpf.Name(),
+	}
+	id := &Detector{}
+	assert.Nil(t, id.Configure(facts))
+	assert.Equal(t, 1, id.Mailmap.Len())
+	vadim := id.PeopleDict["vadim@sourced.tech"]
+	assert.Equal(t, vadim, id.PeopleDict["gmarkhor@gmail.com"])
+	assert.Equal(t, vadim, id.PeopleDict["vadim"])
+	facts[ConfigIdentityDetectorMailmapPath] = "/does/not/exist"
+	id = &Detector{}
+	assert.NotNil(t, id.Configure(facts))
+}
+
+func TestIdentityDetectorConsumeMailmap(t *testing.T) {
+	id := fixtureIdentityDetector()
+	id.Mailmap = &Mailmap{}
+	id.Mailmap.Parse("Vadim <vadim@sourced.tech> Bob <bob@example.com>")
+	deps := map[string]interface{}{}
+	deps[core.DependencyCommit] = &object.Commit{
+		Author: object.Signature{Name: "Bob", Email: "bob@example.com"}}
+	res, err := id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+	deps[core.DependencyCommit] = &object.Commit{
+		Author: object.Signature{Name: "Alice", Email: "bob@example.com"}}
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, AuthorMissing, res[DependencyAuthor].(int))
+	// the original signature is checked if the resolved one is unknown
+	id.Mailmap.Parse("Somebody Else <else@example.com> <gmarkhor@gmail.com>")
+	deps[core.DependencyCommit] = &object.Commit{
+		Author: object.Signature{Name: "Vadim", Email: "gmarkhor@gmail.com"}}
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+}
+
+func TestIdentityDetectorConsumeCoAuthors(t *testing.T) {
+	id := fixtureIdentityDetector()
+	id.PeopleDict["bob@example.com"] = 1
+	id.ReversedPeopleDict = append(id.ReversedPeopleDict, "Bob")
+	deps := map[string]interface{}{}
+	deps[core.DependencyCommit] = &object.Commit{
+		Author: object.Signature{Name: "Vadim", Email: "vadim@sourced.tech"},
+		Message: `Pair programming
+
+Co-authored-by: Bob <bob@example.com>
+Co-authored-by: Vadim <gmarkhor@gmail.com>
+Co-authored-by: Unknown <unknown@example.com>`,
+	}
+	res, err := id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+	assert.Equal(t, []int{0}, res[DependencyAuthors].([]int))
+	id.CoAuthors = true
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+	assert.Equal(t, []int{0, 1}, res[DependencyAuthors].([]int))
+}
+
+func TestIdentityDetectorGeneratePeopleDictCoAuthors(t *testing.T) {
+	commits := []*object.Commit{{
+		Author:  object.Signature{Name: "Vadim", Email: "vadim@sourced.tech"},
+		Message: "Pair programming\n\nCo-authored-by: Bob <bob@example.com>",
+	}}
+	id := &Detector{Mailmap: &Mailmap{}}
+	id.GeneratePeopleDict(commits)
+	assert.Equal(t, []string{"vadim|vadim@sourced.tech"}, id.ReversedPeopleDict)
+	id = &Detector{Mailmap: &Mailmap{}, CoAuthors: true}
+	id.GeneratePeopleDict(commits)
+	assert.Equal(t, []string{"vadim|vadim@sourced.tech", "bob|bob@example.com"},
+		id.ReversedPeopleDict)
+	facts := map[string]interface{}{ConfigIdentityDetectorCoAuthors: true}
+	id = &Detector{}
+	assert.NotNil(t, id.Configure(facts))
+	assert.True(t, id.CoAuthors)
+}
+
 func TestIdentityDetectorMergeReversedDicts(t *testing.T) {
 	pa1 := [...]string{"one", "two"}
 	pa2 := [...]string{"two", "three"}
@@ -411,3 +515,19 @@ func TestIdentityDetectorFork(t *testing.T) {
 	assert.True(t, id1 == id2)
 	id1.Merge([]core.PipelineItem{id2})
 }
+
+func TestIdentityDetectorSaveLoadState(t *testing.T) {
+	id := fixtureIdentityDetector()
+	id.ReversedPeopleDict = []string{"vadim|vadim@sourced.tech", "bob|bob@example.com"}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, id.SaveState(buffer))
+	state := buffer.Bytes()
+	id.ReversedPeopleDict = []string{
+		"vadim|vadim@sourced.tech|gmarkhor@gmail.com", "bob|bob@example.com", "alice|alice@example.com"}
+	assert.Nil(t, id.LoadState(bytes.NewBuffer(state)))
+	id.ReversedPeopleDict = []string{"bob|bob@example.com", "vadim|vadim@sourced.tech"}
+	assert.NotNil(t, id.LoadState(bytes.NewBuffer(state)))
+	id.ReversedPeopleDict = []string{"vadim|vadim@sourced.tech"}
+	assert.NotNil(t, id.LoadState(bytes.NewBuffer(state)))
+	assert.NotNil(t, id.LoadState(bytes.NewBuffer([]byte("garbage"))))
+}
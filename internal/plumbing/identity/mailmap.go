@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"sort"
 	"strings"
 
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
@@ -9,7 +10,7 @@ import (
 // ParseMailmap parses the contents of .mailmap and returns the mapping
 // between signature parts. It does *not* follow the full signature
 // matching convention, that is, developers are identified by email
-// and by name independently.
+// and by name independently. See Mailmap for the full semantics.
 func ParseMailmap(contents string) map[string]object.Signature {
 	mm := map[string]object.Signature{}
 	lines := strings.Split(contents, "\n")
@@ -49,3 +50,134 @@ func ParseMailmap(contents string) map[string]object.Signature {
 	}
 	return mm
 }
+
+// Mailmap resolves the commit signatures to the proper names and emails following the full
+// git convention, see gitmailmap(5). The entries are keyed by the commit email and optionally
+// by the commit name; both are compared case-insensitively. The zero value is an empty mapping.
+type Mailmap struct {
+	// entries maps the lower case commit email to the lower case commit name to the proper
+	// signature. The empty commit name matches any name.
+	entries map[string]map[string]object.Signature
+}
+
+// Parse adds the mappings from the contents of .mailmap. The later lines override the earlier ones
+// in the same way as git does. Each line has one of the forms:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func (mm *Mailmap) Parse(contents string) {
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name1, email1, rest, ok := parseNameAndEmail(line)
+		if !ok {
+			continue
+		}
+		name2, email2, _, ok := parseNameAndEmail(rest)
+		if !ok {
+			// Proper Name <commit@email>
+			mm.add(email1, "", object.Signature{Name: name1})
+			continue
+		}
+		mm.add(email2, name2, object.Signature{Name: name1, Email: email1})
+	}
+}
+
+// Len returns the number of mapped signatures.
+func (mm *Mailmap) Len() int {
+	size := 0
+	for _, names := range mm.entries {
+		size += len(names)
+	}
+	return size
+}
+
+// Resolve returns the proper name and email of the commit signature. The parts which are not
+// mapped remain the same.
+func (mm *Mailmap) Resolve(name, email string) (string, string) {
+	if mm == nil {
+		return name, email
+	}
+	names, exists := mm.entries[strings.ToLower(email)]
+	if !exists {
+		return name, email
+	}
+	proper, exists := names[strings.ToLower(name)]
+	if !exists {
+		proper = names[""]
+	}
+	if proper.Name != "" {
+		name = proper.Name
+	}
+	if proper.Email != "" {
+		email = proper.Email
+	}
+	return name, email
+}
+
+// sortedEntries returns the pairs of the commit and the proper signatures ordered by the commit
+// email and name. The commit name is empty if it matches any name.
+func (mm *Mailmap) sortedEntries() [][2]object.Signature {
+	var entries [][2]object.Signature
+	emails := make([]string, 0, len(mm.entries))
+	for email := range mm.entries {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	for _, email := range emails {
+		names := make([]string, 0, len(mm.entries[email]))
+		for name := range mm.entries[email] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entries = append(entries, [2]object.Signature{
+				{Name: name, Email: email}, mm.entries[email][name]})
+		}
+	}
+	return entries
+}
+
+func (mm *Mailmap) add(email, name string, proper object.Signature) {
+	if mm.entries == nil {
+		mm.entries = map[string]map[string]object.Signature{}
+	}
+	email = strings.ToLower(email)
+	names, exists := mm.entries[email]
+	if !exists {
+		names = map[string]object.Signature{}
+		mm.entries[email] = names
+	}
+	name = strings.ToLower(name)
+	if name != "" {
+		names[name] = proper
+		return
+	}
+	// the lines without the commit name are merged
+	merged := names[""]
+	if proper.Name != "" {
+		merged.Name = proper.Name
+	}
+	if proper.Email != "" {
+		merged.Email = proper.Email
+	}
+	names[""] = merged
+}
+
+// parseNameAndEmail splits "Name <email> rest" into the parts. The name may be empty.
+func parseNameAndEmail(line string) (name, email, rest string, ok bool) {
+	left := strings.Index(line, "<")
+	if left < 0 {
+		return "", "", line, false
+	}
+	right := strings.Index(line[left:], ">")
+	if right < 0 {
+		return "", "", line, false
+	}
+	right += left
+	return strings.TrimSpace(line[:left]), line[left+1 : right], line[right+1:], true
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 func TestParseMailmap(t *testing.T) {
@@ -301,3 +302,62 @@ func TestParseMailmapBadFormat(t *testing.T) {
 	assert.Equal(t, mm["<dengemann"].Name, "Denis Engemann")
 	assert.Equal(t, mm["<dengemann"].Email, "denis-alexander.engemann@inria.fr")
 }
+
+func TestMailmapResolve(t *testing.T) {
+	mm := &Mailmap{}
+	mm.Parse(`# comment
+Joe Developer <joe@example.com>
+<jane@example.com> <jane@desktop.(none)>
+Jane Doe <jane@example.com> <jane@laptop.(none)>
+Other Author <other@author.xx> nick2 <bugs@company.xx>
+Santa Claus <santa.claus@northpole.xx> <me@company.xx>
+Santa Claus <santa.claus@northpole.xx> Santa <santa@company.xx>
+broken line <without the end
+`)
+	assert.Equal(t, 6, mm.Len())
+	name, email := mm.Resolve("joe", "Joe@Example.com")
+	assert.Equal(t, "Joe Developer", name)
+	assert.Equal(t, "Joe@Example.com", email)
+	name, email = mm.Resolve("Jane", "jane@desktop.(none)")
+	assert.Equal(t, "Jane", name)
+	assert.Equal(t, "jane@example.com", email)
+	name, email = mm.Resolve("Jane", "jane@laptop.(none)")
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "jane@example.com", email)
+	name, email = mm.Resolve("NICK2", "bugs@company.xx")
+	assert.Equal(t, "Other Author", name)
+	assert.Equal(t, "other@author.xx", email)
+	// the name does not match
+	name, email = mm.Resolve("nick1", "bugs@company.xx")
+	assert.Equal(t, "nick1", name)
+	assert.Equal(t, "bugs@company.xx", email)
+	name, email = mm.Resolve("Santa", "santa@company.xx")
+	assert.Equal(t, "Santa Claus", name)
+	assert.Equal(t, "santa.claus@northpole.xx", email)
+	name, email = mm.Resolve("Unknown", "unknown@company.xx")
+	assert.Equal(t, "Unknown", name)
+	assert.Equal(t, "unknown@company.xx", email)
+	name, email = (*Mailmap)(nil).Resolve("Unknown", "unknown@company.xx")
+	assert.Equal(t, "Unknown", name)
+	assert.Equal(t, "unknown@company.xx", email)
+}
+
+func TestMailmapOverride(t *testing.T) {
+	mm := &Mailmap{}
+	mm.Parse(`Joe <joe@example.com>
+<joe.developer@example.com> <joe@example.com>
+Jane <jane@example.com> Jane D <jane@laptop>
+Jane Doe <jane@example.com> Jane D <jane@laptop>`)
+	mm.Parse(`Joe Developer <joe@example.com>`)
+	name, email := mm.Resolve("joe", "joe@example.com")
+	assert.Equal(t, "Joe Developer", name)
+	assert.Equal(t, "joe.developer@example.com", email)
+	name, email = mm.Resolve("Jane D", "jane@laptop")
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "jane@example.com", email)
+	assert.Equal(t, [][2]object.Signature{
+		{{Name: "jane d", Email: "jane@laptop"}, {Name: "Jane Doe", Email: "jane@example.com"}},
+		{{Name: "", Email: "joe@example.com"},
+			{Name: "Joe Developer", Email: "joe.developer@example.com"}},
+	}, mm.sortedEntries())
+}
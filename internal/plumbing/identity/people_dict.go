@@ -0,0 +1,116 @@
+package identity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/hercules.v9/internal/core"
+)
+
+// Activity is the summary of the commits which are attributed to a single identity.
+type Activity struct {
+	// Commits is the number of the commits.
+	Commits int
+	// FirstSeen is the time of the earliest commit.
+	FirstSeen time.Time
+	// LastSeen is the time of the latest commit.
+	LastSeen time.Time
+}
+
+// isPeopleDictComment reports whether the line of the people dict file carries no identity.
+func isPeopleDictComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#")
+}
+
+// CollectActivity runs Consume() on each commit and summarizes the commits of every identity
+// in DependencyAuthors. The commit time is the committer's time, the same as in Pipeline.
+// The unmatched identities are not included.
+func (detector *Detector) CollectActivity(commits []*object.Commit) (map[int]*Activity, error) {
+	activity := map[int]*Activity{}
+	for _, commit := range commits {
+		result, err := detector.Consume(map[string]interface{}{core.DependencyCommit: commit})
+		if err != nil {
+			return nil, err
+		}
+		when := commit.Committer.When
+		for _, id := range result[DependencyAuthors].([]int) {
+			if id == AuthorMissing {
+				continue
+			}
+			record := activity[id]
+			if record == nil {
+				record = &Activity{FirstSeen: when, LastSeen: when}
+				activity[id] = record
+			}
+			record.Commits++
+			if when.Before(record.FirstSeen) {
+				record.FirstSeen = when
+			}
+			if when.After(record.LastSeen) {
+				record.LastSeen = when
+			}
+		}
+	}
+	return activity, nil
+}
+
+// WritePeopleDict writes the first `peopleCount` identities in the format of LoadPeopleDict().
+// Every identity is preceded by a comment with its activity, and the bots are marked with
+// the "[bot] " prefix. Each line starts with the keys of ReversedPeopleDict which are followed by
+// the rest of the names and then the rest of the emails in PeopleDict.
+func (detector *Detector) WritePeopleDict(
+	writer io.Writer, peopleCount int, activity map[int]*Activity, bots map[int]bool) error {
+	keys := make([][]string, peopleCount)
+	for key, id := range detector.PeopleDict {
+		if id < peopleCount && key != "" {
+			keys[id] = append(keys[id], key)
+		}
+	}
+	buffered := bufio.NewWriter(writer)
+	for id := 0; id < peopleCount; id++ {
+		if record := activity[id]; record != nil {
+			fmt.Fprintf(buffered, "# commits: %d, first: %s, last: %s\n", record.Commits,
+				record.FirstSeen.UTC().Format("2006-01-02"), record.LastSeen.UTC().Format("2006-01-02"))
+		} else {
+			fmt.Fprintln(buffered, "# commits: 0")
+		}
+		if bots[id] {
+			buffered.WriteString(peopleDictBotTag)
+		}
+		buffered.WriteString(strings.Join(detector.peopleDictKeys(id, keys[id]), "|"))
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+// peopleDictKeys orders the keys of the identity for WritePeopleDict().
+func (detector *Detector) peopleDictKeys(id int, keys []string) []string {
+	var result []string
+	written := map[string]bool{}
+	for _, key := range strings.Split(detector.ReversedPeopleDict[id], "|") {
+		if key != "" && !written[strings.ToLower(key)] {
+			result = append(result, key)
+			written[strings.ToLower(key)] = true
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		iEmail, jEmail := strings.Contains(keys[i], "@"), strings.Contains(keys[j], "@")
+		if iEmail != jEmail {
+			return jEmail
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		if !written[key] {
+			result = append(result, key)
+			written[key] = true
+		}
+	}
+	return result
+}
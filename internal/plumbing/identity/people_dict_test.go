@@ -0,0 +1,104 @@
+package identity
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func fixturePeopleDictCommits() []*object.Commit {
+	commit := func(name, email, when string) *object.Commit {
+		timestamp, _ := time.Parse("2006-01-02", when)
+		return &object.Commit{
+			Author:    object.Signature{Name: name, Email: email, When: timestamp},
+			Committer: object.Signature{Name: name, Email: email, When: timestamp},
+		}
+	}
+	return []*object.Commit{
+		commit("Vadim", "vadim@sourced.tech", "2018-01-05"),
+		commit("dependabot[bot]", "support@dependabot.com", "2018-02-10"),
+		commit("Vadim", "gmarkhor@gmail.com", "2018-01-01"),
+		commit("Vadim Markovtsev", "vadim@sourced.tech", "2018-03-15"),
+		commit("Unknown", "unknown@example.com", "2018-04-01"),
+	}
+}
+
+func TestIdentityDetectorCollectActivity(t *testing.T) {
+	id := fixtureIdentityDetector()
+	id.PeopleDict["vadim markovtsev"] = 0
+	id.PeopleDict["dependabot[bot]"] = 1
+	id.ReversedPeopleDict = append(id.ReversedPeopleDict, "dependabot[bot]")
+	activity, err := id.CollectActivity(fixturePeopleDictCommits())
+	assert.Nil(t, err)
+	assert.Len(t, activity, 2)
+	assert.Equal(t, 3, activity[0].Commits)
+	assert.Equal(t, "2018-01-01", activity[0].FirstSeen.Format("2006-01-02"))
+	assert.Equal(t, "2018-03-15", activity[0].LastSeen.Format("2006-01-02"))
+	assert.Equal(t, 1, activity[1].Commits)
+	assert.Equal(t, activity[1].FirstSeen, activity[1].LastSeen)
+}
+
+func TestIdentityDetectorWritePeopleDict(t *testing.T) {
+	id := &Detector{Mailmap: &Mailmap{}}
+	commits := fixturePeopleDictCommits()
+	id.GeneratePeopleDict(commits)
+	peopleCount := len(id.ReversedPeopleDict)
+	activity, err := id.CollectActivity(commits)
+	assert.Nil(t, err)
+	bots, err := id.DetectBots(peopleCount)
+	assert.Nil(t, err)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, id.WritePeopleDict(buffer, peopleCount, activity, bots))
+	assert.Equal(t, `# commits: 3, first: 2018-01-01, last: 2018-03-15
+vadim|vadim markovtsev|gmarkhor@gmail.com|vadim@sourced.tech
+# commits: 1, first: 2018-02-10, last: 2018-02-10
+[bot] dependabot[bot]|support@dependabot.com
+# commits: 1, first: 2018-04-01, last: 2018-04-01
+unknown|unknown@example.com
+`, buffer.String())
+
+	tmpf, err := ioutil.TempFile("", "hercules-test-")
+	assert.Nil(t, err)
+	defer os.Remove(tmpf.Name())
+	_, err = tmpf.Write(buffer.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, tmpf.Close())
+	loaded := &Detector{}
+	assert.Nil(t, loaded.LoadPeopleDict(tmpf.Name()))
+	assert.Equal(t, id.PeopleDict, loaded.PeopleDict)
+	assert.Equal(t, map[int]bool{1: true}, loaded.botAnnotations)
+	// the loaded identities have only the main key in ReversedPeopleDict
+	buffer2 := &bytes.Buffer{}
+	assert.Nil(t, loaded.WritePeopleDict(buffer2, peopleCount, activity, bots))
+	assert.Equal(t, buffer.String(), buffer2.String())
+	buffer2.Reset()
+	assert.Nil(t, loaded.WritePeopleDict(buffer2, 1, nil, nil))
+	assert.Equal(t, "# commits: 0\nvadim|vadim markovtsev|gmarkhor@gmail.com|vadim@sourced.tech\n",
+		buffer2.String())
+}
+
+func TestIdentityDetectorLoadPeopleDictComments(t *testing.T) {
+	tmpf, err := ioutil.TempFile("", "hercules-test-")
+	assert.Nil(t, err)
+	defer os.Remove(tmpf.Name())
+	_, err = tmpf.WriteString(`# the core team
+
+# 10 commits
+Vadim|vadim@sourced.tech
+  # indented comment
+Egor|egor@sourced.tech
+`)
+	assert.Nil(t, err)
+	assert.Nil(t, tmpf.Close())
+	id := &Detector{}
+	assert.Nil(t, id.LoadPeopleDict(tmpf.Name()))
+	assert.Equal(t, []string{"Vadim", "Egor", AuthorMissingName}, id.ReversedPeopleDict)
+	assert.Equal(t, map[string]int{
+		"vadim": 0, "vadim@sourced.tech": 0, "egor": 1, "egor@sourced.tech": 1,
+	}, id.PeopleDict)
+}
@@ -0,0 +1,41 @@
+package identity
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// SignaturesAuthor identifies the developers by the commit authors.
+	SignaturesAuthor = "author"
+	// SignaturesCommitter identifies the developers by the committers.
+	SignaturesCommitter = "committer"
+	// SignaturesBoth identifies the developers by the commit authors and by the committers.
+	// The author remains DependencyAuthor and the committer follows it in DependencyAuthors.
+	SignaturesBoth = "both"
+)
+
+// checkSignatures validates the value of ConfigIdentityDetectorSignatures.
+// The empty value is the same as SignaturesAuthor.
+func checkSignatures(signatures string) (string, error) {
+	switch signatures {
+	case "":
+		return SignaturesAuthor, nil
+	case SignaturesAuthor, SignaturesCommitter, SignaturesBoth:
+		return signatures, nil
+	}
+	return "", errors.Errorf("unknown signatures %q, must be one of %s, %s, %s",
+		signatures, SignaturesAuthor, SignaturesCommitter, SignaturesBoth)
+}
+
+// commitSignatures returns the signatures of the commit which identify the developers according
+// to Signatures. The main one goes first.
+func (detector *Detector) commitSignatures(commit *object.Commit) []object.Signature {
+	switch detector.Signatures {
+	case SignaturesCommitter:
+		return []object.Signature{commit.Committer}
+	case SignaturesBoth:
+		return []object.Signature{commit.Author, commit.Committer}
+	}
+	return []object.Signature{commit.Author}
+}
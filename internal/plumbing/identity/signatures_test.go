@@ -0,0 +1,105 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/hercules.v9/internal/core"
+)
+
+func fixtureSignaturesCommit() *object.Commit {
+	return &object.Commit{
+		Author:    object.Signature{Name: "Vadim", Email: "vadim@sourced.tech"},
+		Committer: object.Signature{Name: "GitHub", Email: "noreply@github.com"},
+		Message:   "Merge the pull request\n\nCo-authored-by: Bob <bob@example.com>",
+	}
+}
+
+func TestCheckSignatures(t *testing.T) {
+	for _, signatures := range []string{SignaturesAuthor, SignaturesCommitter, SignaturesBoth} {
+		checked, err := checkSignatures(signatures)
+		assert.Nil(t, err)
+		assert.Equal(t, signatures, checked)
+	}
+	checked, err := checkSignatures("")
+	assert.Nil(t, err)
+	assert.Equal(t, SignaturesAuthor, checked)
+	_, err = checkSignatures("reviewer")
+	assert.NotNil(t, err)
+}
+
+func TestIdentityDetectorConsumeSignatures(t *testing.T) {
+	id := fixtureIdentityDetector()
+	id.PeopleDict["noreply@github.com"] = 1
+	id.PeopleDict["bob@example.com"] = 2
+	id.ReversedPeopleDict = append(id.ReversedPeopleDict, "GitHub", "Bob")
+	deps := map[string]interface{}{core.DependencyCommit: fixtureSignaturesCommit()}
+	res, err := id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+	assert.Equal(t, []int{0}, res[DependencyAuthors].([]int))
+	id.Signatures = SignaturesCommitter
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res[DependencyAuthor].(int))
+	assert.Equal(t, []int{1}, res[DependencyAuthors].([]int))
+	id.Signatures = SignaturesBoth
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, res[DependencyAuthor].(int))
+	assert.Equal(t, []int{0, 1}, res[DependencyAuthors].([]int))
+	id.CoAuthors = true
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 2}, res[DependencyAuthors].([]int))
+	// the committer is the same as the author
+	deps[core.DependencyCommit] = &object.Commit{
+		Author:    object.Signature{Name: "Vadim", Email: "vadim@sourced.tech"},
+		Committer: object.Signature{Name: "Vadim", Email: "gmarkhor@gmail.com"},
+	}
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0}, res[DependencyAuthors].([]int))
+	// the unknown committer is not credited
+	deps[core.DependencyCommit] = &object.Commit{
+		Author:    object.Signature{Name: "Vadim", Email: "vadim@sourced.tech"},
+		Committer: object.Signature{Name: "Unknown", Email: "unknown@example.com"},
+	}
+	res, err = id.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0}, res[DependencyAuthors].([]int))
+}
+
+func TestIdentityDetectorGeneratePeopleDictSignatures(t *testing.T) {
+	commits := []*object.Commit{fixtureSignaturesCommit()}
+	id := &Detector{Mailmap: &Mailmap{}}
+	id.GeneratePeopleDict(commits)
+	assert.Equal(t, []string{"vadim|vadim@sourced.tech"}, id.ReversedPeopleDict)
+	id = &Detector{Mailmap: &Mailmap{}, Signatures: SignaturesCommitter}
+	id.GeneratePeopleDict(commits)
+	assert.Equal(t, []string{"github|noreply@github.com"}, id.ReversedPeopleDict)
+	id = &Detector{Mailmap: &Mailmap{}, Signatures: SignaturesBoth}
+	id.GeneratePeopleDict(commits)
+	assert.Equal(t, []string{"vadim|vadim@sourced.tech", "github|noreply@github.com"},
+		id.ReversedPeopleDict)
+}
+
+func TestIdentityDetectorConfigureSignatures(t *testing.T) {
+	facts := map[string]interface{}{
+		FactIdentityDetectorPeopleDict:         map[string]int{"vadim": 0},
+		FactIdentityDetectorReversedPeopleDict: []string{"vadim"},
+	}
+	id := &Detector{}
+	assert.Nil(t, id.Configure(facts))
+	assert.Equal(t, SignaturesAuthor, id.Signatures)
+	assert.Equal(t, SignaturesAuthor, facts[core.FactPipelineSignatures])
+	facts[ConfigIdentityDetectorSignatures] = SignaturesCommitter
+	id = &Detector{}
+	assert.Nil(t, id.Configure(facts))
+	assert.Equal(t, SignaturesCommitter, id.Signatures)
+	assert.Equal(t, SignaturesCommitter, facts[core.FactPipelineSignatures])
+	facts[ConfigIdentityDetectorSignatures] = "reviewer"
+	id = &Detector{}
+	assert.NotNil(t, id.Configure(facts))
+}
@@ -15,6 +15,7 @@ import (
 // LanguagesDetection run programming language detection over the changed files.
 type LanguagesDetection struct {
 	core.NoopMerger
+	core.NoopCheckpointer
 }
 
 const (
@@ -14,6 +14,7 @@ import (
 // LinesStatsCalculator measures line statistics for each text file in the commit.
 type LinesStatsCalculator struct {
 	core.NoopMerger
+	core.NoopCheckpointer
 }
 
 // LineStats holds the numbers of inserted, deleted and changed lines.
@@ -23,6 +23,7 @@ import (
 // RenameAnalysis is a PipelineItem.
 type RenameAnalysis struct {
 	core.NoopMerger
+	core.NoopCheckpointer
 	// SimilarityThreshold adjusts the heuristic to determine file renames.
 	// It has the same units as cgit's -X rename-threshold or -M. Better to
 	// set it to the default value of 80 (80%).
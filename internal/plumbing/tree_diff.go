@@ -1,6 +1,7 @@
 package plumbing
 
 import (
+	"encoding/gob"
 	"fmt"
 	"io"
 	"log"
@@ -253,6 +254,29 @@ func (treediff *TreeDiff) Fork(n int) []core.PipelineItem {
 	return core.ForkCopyPipelineItem(treediff, n)
 }
 
+// SaveState writes the hash of the last consumed commit.
+func (treediff *TreeDiff) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(treediff.previousCommit)
+}
+
+// LoadState restores the last consumed commit and its tree.
+func (treediff *TreeDiff) LoadState(reader io.Reader) error {
+	err := gob.NewDecoder(reader).Decode(&treediff.previousCommit)
+	if err != nil {
+		return err
+	}
+	treediff.previousTree = nil
+	if treediff.previousCommit == plumbing.ZeroHash {
+		return nil
+	}
+	commit, err := treediff.repository.CommitObject(treediff.previousCommit)
+	if err != nil {
+		return err
+	}
+	treediff.previousTree, err = commit.Tree()
+	return err
+}
+
 // checkLanguage returns whether the blob corresponds to the list of required languages.
 func (treediff *TreeDiff) checkLanguage(name string, blobHash plumbing.Hash) (bool, error) {
 	if treediff.Languages[allLanguages] {
@@ -1,6 +1,7 @@
 package plumbing
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -276,3 +277,29 @@ func TestTreeDiffConsumeEnryFilter(t *testing.T) {
 	newDiffs = td.filterDiffs(diffs)
 	assert.Len(t, newDiffs, 0)
 }
+
+func TestTreeDiffSaveLoadState(t *testing.T) {
+	td := fixtureTreeDiff()
+	commit, _ := test.Repository.CommitObject(plumbing.NewHash(
+		"fbe766ffdc3f87f6affddc051c6f8b419beea6a2"))
+	deps := map[string]interface{}{}
+	deps[core.DependencyCommit] = commit
+	_, err := td.Consume(deps)
+	assert.Nil(t, err)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, td.SaveState(buffer))
+	td2 := fixtureTreeDiff()
+	assert.Nil(t, td2.LoadState(buffer))
+	assert.Equal(t, td.previousCommit, td2.previousCommit)
+	assert.Equal(t, td.previousTree.Hash, td2.previousTree.Hash)
+	commit, _ = test.Repository.CommitObject(plumbing.NewHash(
+		"2b1ed978194a94edeabbca6de7ff3b5771d4d665"))
+	deps[core.DependencyCommit] = commit
+	res, err := td2.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, res[DependencyTreeChanges].(object.Changes), 12)
+	buffer.Reset()
+	assert.Nil(t, fixtureTreeDiff().SaveState(buffer))
+	assert.Nil(t, td2.LoadState(buffer))
+	assert.Nil(t, td2.previousTree)
+}
@@ -2,6 +2,7 @@ package uast
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -29,11 +30,14 @@ import (
 )
 
 // Extractor retrieves UASTs from Babelfish server which correspond to changed files in a commit.
-// It is a PipelineItem.
+// It is a PipelineItem. ParentContext is the same as Context but derives the request context
+// from the context of the pipeline, so that the requests are cancelled together with the run;
+// it takes precedence over Context.
 type Extractor struct {
 	core.NoopMerger
 	Endpoint       string
 	Context        func() (context.Context, context.CancelFunc)
+	ParentContext  func(parent context.Context) (context.Context, context.CancelFunc)
 	PoolSize       int
 	FailOnErrors   bool
 	ProcessedFiles map[string]int
@@ -62,12 +66,13 @@ const (
 )
 
 type uastTask struct {
-	Lock   *sync.RWMutex
-	Dest   map[plumbing.Hash]nodes.Node
-	Name   string
-	Hash   plumbing.Hash
-	Data   []byte
-	Errors *[]error
+	Context context.Context
+	Lock    *sync.RWMutex
+	Dest    map[plumbing.Hash]nodes.Node
+	Name    string
+	Hash    plumbing.Hash
+	Data    []byte
+	Errors  *[]error
 }
 
 type worker struct {
@@ -147,6 +152,9 @@ func (exr *Extractor) Configure(facts map[string]interface{}) error {
 			return context.WithTimeout(context.Background(),
 				time.Duration(val)*time.Second)
 		}
+		exr.ParentContext = func(parent context.Context) (context.Context, context.CancelFunc) {
+			return context.WithTimeout(parent, time.Duration(val)*time.Second)
+		}
 	}
 	if val, exists := facts[ConfigUASTPoolSize].(int); exists {
 		exr.PoolSize = val
@@ -160,6 +168,11 @@ func (exr *Extractor) Configure(facts map[string]interface{}) error {
 // Initialize resets the temporary caches and prepares this PipelineItem for a series of Consume()
 // calls. The repository which is going to be analysed is supplied as an argument.
 func (exr *Extractor) Initialize(repository *git.Repository) error {
+	if exr.ParentContext == nil && exr.Context == nil {
+		exr.ParentContext = func(parent context.Context) (context.Context, context.CancelFunc) {
+			return parent, nil
+		}
+	}
 	if exr.Context == nil {
 		exr.Context = func() (context.Context, context.CancelFunc) {
 			return context.Background(), nil
@@ -193,7 +206,7 @@ func (exr *Extractor) Initialize(repository *git.Repository) error {
 		})
 	}
 	if exr.pool == nil {
-		panic("UAST goroutine pool was not created")
+		return errors.New("UAST goroutine pool was not created")
 	}
 	exr.ProcessedFiles = map[string]int{}
 	return nil
@@ -207,6 +220,10 @@ func (exr *Extractor) Initialize(repository *git.Repository) error {
 func (exr *Extractor) Consume(deps map[string]interface{}) (map[string]interface{}, error) {
 	cache := deps[items.DependencyBlobCache].(map[plumbing.Hash]*items.CachedBlob)
 	treeDiffs := deps[items.DependencyTreeChanges].(object.Changes)
+	ctx, _ := deps[core.DependencyContext].(context.Context)
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	uasts := map[plumbing.Hash]nodes.Node{}
 	lock := sync.RWMutex{}
 	errs := make([]error, 0)
@@ -218,12 +235,13 @@ func (exr *Extractor) Consume(deps map[string]interface{}) (map[string]interface
 			exr.pool.Process(task)
 			wg.Done()
 		}(uastTask{
-			Lock:   &lock,
-			Dest:   uasts,
-			Name:   change.To.Name,
-			Hash:   change.To.TreeEntry.Hash,
-			Data:   cache[change.To.TreeEntry.Hash].Data,
-			Errors: &errs,
+			Context: ctx,
+			Lock:    &lock,
+			Dest:    uasts,
+			Name:    change.To.Name,
+			Hash:    change.To.TreeEntry.Hash,
+			Data:    cache[change.To.TreeEntry.Hash].Data,
+			Errors:  &errs,
 		})
 	}
 	for _, change := range treeDiffs {
@@ -241,6 +259,9 @@ func (exr *Extractor) Consume(deps map[string]interface{}) (map[string]interface
 		}
 	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if len(errs) > 0 {
 		msgs := make([]string, len(errs))
 		for i, err := range errs {
@@ -260,9 +281,20 @@ func (exr *Extractor) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(exr, n)
 }
 
+// Sequential returns true because all the branches count the files in ProcessedFiles.
+func (exr *Extractor) Sequential() bool {
+	return true
+}
+
 func (exr *Extractor) extractUAST(
-	client *bblfsh.Client, name string, data []byte) (nodes.Node, error) {
-	ctx, cancel := exr.Context()
+	parent context.Context, client *bblfsh.Client, name string, data []byte) (nodes.Node, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if exr.ParentContext != nil {
+		ctx, cancel = exr.ParentContext(parent)
+	} else {
+		ctx, cancel = exr.Context()
+	}
 	if cancel != nil {
 		defer cancel()
 	}
@@ -280,7 +312,7 @@ func (exr *Extractor) extractUAST(
 
 func (exr *Extractor) extractTask(client *bblfsh.Client, data interface{}) interface{} {
 	task := data.(uastTask)
-	node, err := exr.extractUAST(client, task.Name, task.Data)
+	node, err := exr.extractUAST(task.Context, client, task.Name, task.Data)
 	task.Lock.Lock()
 	defer task.Lock.Unlock()
 	if err != nil {
@@ -515,13 +547,22 @@ func (saver *ChangesSaver) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(saver, n)
 }
 
+// Sequential returns true so that the changes are saved in the order of the execution plan.
+func (saver *ChangesSaver) Sequential() bool {
+	return true
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (saver *ChangesSaver) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (saver *ChangesSaver) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	saverResult := result.([][]Change)
 	fileNames := saver.dumpFiles(saverResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return saver.serializeBinary(fileNames, writer)
+	case core.JSONFormat:
+		return saver.serializeJSON(fileNames, writer)
 	}
 	saver.serializeText(fileNames, writer)
 	return nil
@@ -585,6 +626,27 @@ func (saver *ChangesSaver) serializeText(result []*pb.UASTChange, writer io.Writ
 	}
 }
 
+// changeJSON is the JSON representation of pb.UASTChange.
+type changeJSON struct {
+	File  string `json:"file"`
+	Src0  string `json:"src0"`
+	Src1  string `json:"src1"`
+	UAST0 string `json:"uast0"`
+	UAST1 string `json:"uast1"`
+}
+
+func (saver *ChangesSaver) serializeJSON(result []*pb.UASTChange, writer io.Writer) error {
+	changes := make([]changeJSON, len(result))
+	for i, sc := range result {
+		changes[i] = changeJSON{
+			File: sc.FileName,
+			Src0: sc.SrcBefore, Src1: sc.SrcAfter,
+			UAST0: sc.UastBefore, UAST1: sc.UastAfter,
+		}
+	}
+	return json.NewEncoder(writer).Encode(changes)
+}
+
 func (saver *ChangesSaver) serializeBinary(result []*pb.UASTChange, writer io.Writer) error {
 	message := pb.UASTChangesSaverResults{Changes: result}
 	serialized, err := proto.Marshal(&message)
@@ -4,6 +4,8 @@ package uast
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -73,6 +75,17 @@ func TestUASTExtractorConfiguration(t *testing.T) {
 	assert.NotNil(t, exr.Context)
 	assert.Equal(t, exr.PoolSize, facts[ConfigUASTPoolSize])
 	assert.Equal(t, exr.FailOnErrors, true)
+	ctx, ctxCancel := exr.Context()
+	_, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	ctxCancel()
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, ctxCancel = exr.ParentContext(parent)
+	defer ctxCancel()
+	_, hasDeadline = ctx.Deadline()
+	assert.True(t, hasDeadline)
+	cancel()
+	assert.NotNil(t, ctx.Err())
 }
 
 func TestUASTExtractorRegistration(t *testing.T) {
@@ -403,7 +416,7 @@ func TestUASTChangesSaverPayload(t *testing.T) {
 	defer os.RemoveAll(tmpdir)
 	chs.OutputPath = tmpdir
 	buffer := &bytes.Buffer{}
-	chs.Serialize(res, true, buffer)
+	chs.Serialize(res, core.ProtobufFormat, buffer)
 	pbResults := &pb.UASTChangesSaverResults{}
 	proto.Unmarshal(buffer.Bytes(), pbResults)
 	assert.Len(t, pbResults.Changes, 1)
@@ -435,10 +448,22 @@ func TestUASTChangesSaverPayload(t *testing.T) {
 	}
 	checkFiles()
 	buffer.Truncate(0)
-	chs.Serialize(res, false, buffer)
+	chs.Serialize(res, core.YAMLFormat, buffer)
 	assert.Equal(t, buffer.String(), fmt.Sprintf(`  - {file: analyser.go, src0: %s/0_0_before_dc248ba2b22048cc730c571a748e8ffcf7085ab9.src, src1: %s/0_0_after_334cde09da4afcb74f8d2b3e6fd6cce61228b485.src, uast0: %s/0_0_before_dc248ba2b22048cc730c571a748e8ffcf7085ab9.pb, uast1: %s/0_0_after_334cde09da4afcb74f8d2b3e6fd6cce61228b485.pb}
 `, tmpdir, tmpdir, tmpdir, tmpdir))
 	checkFiles()
+	buffer.Truncate(0)
+	assert.Nil(t, chs.Serialize(res, core.JSONFormat, buffer))
+	var jsonResults []changeJSON
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &jsonResults))
+	assert.Equal(t, []changeJSON{{
+		File:  "analyser.go",
+		Src0:  pbResults.Changes[0].SrcBefore,
+		Src1:  pbResults.Changes[0].SrcAfter,
+		UAST0: pbResults.Changes[0].UastBefore,
+		UAST1: pbResults.Changes[0].UastAfter,
+	}}, jsonResults)
+	checkFiles()
 }
 
 func TestUASTChangesSaverConsumeMerge(t *testing.T) {
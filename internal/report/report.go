@@ -0,0 +1,34 @@
+// Package report renders the analysis results as static SVG charts and HTML pages
+// without any external dependencies.
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// WriteHTML writes the self-contained HTML page with all the charts embedded as inline SVG.
+func WriteHTML(writer io.Writer, title string, charts []Chart) error {
+	out := bufio.NewWriter(writer)
+	fmt.Fprintf(out, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>body {font-family: sans-serif; margin: 2em;} figure {margin: 2em 0;}</style>
+</head>
+<body>
+<h1>%s</h1>
+`, html.EscapeString(title), html.EscapeString(title))
+	for _, chart := range charts {
+		out.WriteString("<figure>\n")
+		if err := chart.WriteSVG(out); err != nil {
+			return err
+		}
+		out.WriteString("</figure>\n")
+	}
+	out.WriteString("</body>\n</html>\n")
+	return out.Flush()
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTML(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	charts := []Chart{
+		&StackPlot{Caption: "one"},
+		&Heatmap{Caption: "two", Rows: []string{"a"}, Columns: []string{"a"}, Values: [][]float64{{1}}},
+	}
+	assert.Nil(t, WriteHTML(buffer, "src-d/hercules & co", charts))
+	html := buffer.String()
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.True(t, strings.HasSuffix(html, "</html>\n"))
+	assert.Contains(t, html, "<title>src-d/hercules &amp; co</title>")
+	assert.Equal(t, 2, strings.Count(html, "<figure>"))
+	assert.Equal(t, 2, strings.Count(html, "<svg "))
+	assert.True(t, strings.Index(html, ">one</text>") < strings.Index(html, ">two</text>"))
+}
@@ -0,0 +1,288 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chart is a single static image in the report.
+type Chart interface {
+	// WriteSVG renders the chart as a standalone SVG image.
+	WriteSVG(writer io.Writer) error
+}
+
+// palette is the "tab20" color map from matplotlib, the same as labours.py uses.
+var palette = [...]string{
+	"#1f77b4", "#aec7e8", "#ff7f0e", "#ffbb78", "#2ca02c", "#98df8a", "#d62728", "#ff9896",
+	"#9467bd", "#c5b0d5", "#8c564b", "#c49c94", "#e377c2", "#f7b6d2", "#7f7f7f", "#c7c7c7",
+	"#bcbd22", "#dbdb8d", "#17becf", "#9edae5",
+}
+
+const (
+	stackPlotWidth       = 1100
+	stackPlotHeight      = 540
+	stackPlotMarginLeft  = 70
+	stackPlotMarginRight = 260
+	stackPlotMarginTop   = 40
+	stackPlotMarginBot   = 50
+	stackPlotXTicks      = 6
+	stackPlotYTicks      = 5
+	legendLineHeight     = 16
+	maxLabelLength       = 40
+)
+
+// StackPlot draws several time series on top of each other, e.g. the burndown bands.
+type StackPlot struct {
+	// Caption is the title of the chart.
+	Caption string
+	// X are the moments which correspond to the columns of Layers. They must be ascending.
+	X []time.Time
+	// Layers are the stacked series: [number of layers][len(X)]. Negative values are clipped.
+	Layers [][]float64
+	// Names are the legend labels of Layers.
+	Names []string
+}
+
+// WriteSVG renders the stack plot as a standalone SVG image.
+func (plot *StackPlot) WriteSVG(writer io.Writer) error {
+	out := bufio.NewWriter(writer)
+	writeSVGHeader(out, stackPlotWidth, stackPlotHeight, plot.Caption)
+	areaWidth := float64(stackPlotWidth - stackPlotMarginLeft - stackPlotMarginRight)
+	areaHeight := float64(stackPlotHeight - stackPlotMarginTop - stackPlotMarginBot)
+	columns := len(plot.X)
+	// cumulative[i] is the top border of the layer i
+	cumulative := make([][]float64, len(plot.Layers))
+	maxY := 0.0
+	for i, layer := range plot.Layers {
+		cumulative[i] = make([]float64, columns)
+		for j := 0; j < columns; j++ {
+			value := 0.0
+			if j < len(layer) && layer[j] > 0 {
+				value = layer[j]
+			}
+			if i > 0 {
+				value += cumulative[i-1][j]
+			}
+			cumulative[i][j] = value
+		}
+	}
+	if len(cumulative) > 0 {
+		for _, value := range cumulative[len(cumulative)-1] {
+			maxY = math.Max(maxY, value)
+		}
+	}
+	step := niceStep(maxY / stackPlotYTicks)
+	top := step * math.Ceil(maxY/step)
+	if top == 0 {
+		top = step
+	}
+	xpos := func(j int) float64 {
+		if columns < 2 {
+			return stackPlotMarginLeft + areaWidth/2
+		}
+		span := plot.X[columns-1].Sub(plot.X[0]).Seconds()
+		if span <= 0 {
+			return stackPlotMarginLeft + areaWidth*float64(j)/float64(columns-1)
+		}
+		return stackPlotMarginLeft + areaWidth*plot.X[j].Sub(plot.X[0]).Seconds()/span
+	}
+	ypos := func(value float64) float64 {
+		return stackPlotMarginTop + areaHeight*(1-value/top)
+	}
+	// the grid and the Y axis labels
+	for k := 0; float64(k)*step <= top+step/2; k++ {
+		value := float64(k) * step
+		y := ypos(value)
+		fmt.Fprintf(out, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e0e0e0"/>`+"\n",
+			stackPlotMarginLeft, y, stackPlotMarginLeft+areaWidth, y)
+		fmt.Fprintf(out, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n",
+			stackPlotMarginLeft-6, y, FormatNumber(value))
+	}
+	// the layers
+	for i := range cumulative {
+		var points []string
+		for j := 0; j < columns; j++ {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", xpos(j), ypos(cumulative[i][j])))
+		}
+		for j := columns - 1; j >= 0; j-- {
+			bottom := 0.0
+			if i > 0 {
+				bottom = cumulative[i-1][j]
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", xpos(j), ypos(bottom)))
+		}
+		fmt.Fprintf(out, `<polygon points="%s" fill="%s"><title>%s</title></polygon>`+"\n",
+			strings.Join(points, " "), palette[i%len(palette)], html.EscapeString(plot.name(i)))
+	}
+	// the X axis labels
+	if columns > 0 {
+		ticks := stackPlotXTicks
+		if columns < ticks {
+			ticks = columns
+		}
+		for t := 0; t < ticks; t++ {
+			j := 0
+			if ticks > 1 {
+				j = t * (columns - 1) / (ticks - 1)
+			}
+			fmt.Fprintf(out, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n",
+				xpos(j), stackPlotHeight-stackPlotMarginBot+20, plot.X[j].Format("2006-01-02"))
+		}
+	}
+	fmt.Fprintf(out, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n",
+		stackPlotMarginLeft, ypos(0), stackPlotMarginLeft+areaWidth, ypos(0))
+	// the legend, the topmost layer goes first
+	legendX := stackPlotWidth - stackPlotMarginRight + 20
+	for k := 0; k < len(plot.Layers); k++ {
+		i := len(plot.Layers) - 1 - k
+		y := stackPlotMarginTop + k*legendLineHeight
+		if y+legendLineHeight > stackPlotHeight {
+			break
+		}
+		fmt.Fprintf(out, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n",
+			legendX, y, palette[i%len(palette)])
+		fmt.Fprintf(out, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n",
+			legendX+18, y+6, html.EscapeString(TruncateLabel(plot.name(i))))
+	}
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+func (plot *StackPlot) name(i int) string {
+	if i < len(plot.Names) {
+		return plot.Names[i]
+	}
+	return ""
+}
+
+const (
+	heatmapCellSize   = 18
+	heatmapCharWidth  = 7
+	heatmapMarginTop  = 40
+	heatmapMarginSide = 20
+)
+
+// Heatmap draws a matrix with the color intensity proportional to the values,
+// e.g. who overwrites whose code.
+type Heatmap struct {
+	// Caption is the title of the chart.
+	Caption string
+	// Rows are the labels of the matrix rows.
+	Rows []string
+	// Columns are the labels of the matrix columns.
+	Columns []string
+	// Values are the matrix cells in [0, 1]: [len(Rows)][len(Columns)].
+	Values [][]float64
+}
+
+// WriteSVG renders the heatmap as a standalone SVG image.
+func (heatmap *Heatmap) WriteSVG(writer io.Writer) error {
+	out := bufio.NewWriter(writer)
+	rowLabelWidth := maxLabelWidth(heatmap.Rows)
+	// the column labels are rotated by 45 degrees
+	columnLabelHeight := int(float64(maxLabelWidth(heatmap.Columns)) * math.Sqrt2 / 2)
+	left := heatmapMarginSide + rowLabelWidth
+	top := heatmapMarginTop + columnLabelHeight
+	width := left + heatmapCellSize*len(heatmap.Columns) + heatmapMarginSide + columnLabelHeight
+	height := top + heatmapCellSize*len(heatmap.Rows) + heatmapMarginSide
+	writeSVGHeader(out, width, height, heatmap.Caption)
+	for i, label := range heatmap.Rows {
+		fmt.Fprintf(out, `<text x="%d" y="%d" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n",
+			left-6, top+i*heatmapCellSize+heatmapCellSize/2, html.EscapeString(TruncateLabel(label)))
+	}
+	for j, label := range heatmap.Columns {
+		x := left + j*heatmapCellSize + heatmapCellSize/2
+		fmt.Fprintf(out, `<text x="%d" y="%d" transform="rotate(-45 %d %d)">%s</text>`+"\n",
+			x, top-6, x, top-6, html.EscapeString(TruncateLabel(label)))
+	}
+	for i, row := range heatmap.Values {
+		for j, value := range row {
+			fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#e0e0e0">`+
+				`<title>%s: %s</title></rect>`+"\n",
+				left+j*heatmapCellSize, top+i*heatmapCellSize, heatmapCellSize, heatmapCellSize,
+				heatColor(value), html.EscapeString(heatmap.label(i, j)),
+				strconv.FormatFloat(value, 'f', 3, 64))
+		}
+	}
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+func (heatmap *Heatmap) label(i, j int) string {
+	var row, column string
+	if i < len(heatmap.Rows) {
+		row = heatmap.Rows[i]
+	}
+	if j < len(heatmap.Columns) {
+		column = heatmap.Columns[j]
+	}
+	return row + " -> " + column
+}
+
+// heatColor interpolates between white and dark red ("OrRd" in matplotlib).
+func heatColor(value float64) string {
+	value = math.Max(0, math.Min(1, value))
+	r := 255 - int(value*(255-127))
+	g := 255 - int(value*255)
+	b := 255 - int(value*255)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+func maxLabelWidth(labels []string) int {
+	width := 0
+	for _, label := range labels {
+		if w := len([]rune(TruncateLabel(label))) * heatmapCharWidth; w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+func writeSVGHeader(out *bufio.Writer, width, height int, title string) {
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	fmt.Fprintf(out, `<text x="%d" y="24" text-anchor="middle" font-size="16">%s</text>`+"\n",
+		width/2, html.EscapeString(title))
+}
+
+// TruncateLabel shortens the long names in the same way as labours.py does.
+func TruncateLabel(label string) string {
+	runes := []rune(label)
+	if len(runes) > maxLabelLength {
+		return string(runes[:maxLabelLength-3]) + "..."
+	}
+	return label
+}
+
+// FormatNumber prints the axis tick values compactly, e.g. 12000 as "12k".
+func FormatNumber(value float64) string {
+	switch {
+	case math.Abs(value) >= 1e6:
+		return strconv.FormatFloat(value/1e6, 'g', 6, 64) + "M"
+	case math.Abs(value) >= 1e3:
+		return strconv.FormatFloat(value/1e3, 'g', 6, 64) + "k"
+	}
+	return strconv.FormatFloat(value, 'g', 6, 64)
+}
+
+// niceStep rounds the raw tick step up to 1, 2 or 5 multiplied by a power of 10.
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range [...]float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkXML verifies that the SVG is well-formed.
+func checkXML(t *testing.T, data []byte) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+}
+
+func TestStackPlotWriteSVG(t *testing.T) {
+	begin := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	plot := &StackPlot{
+		Caption: "test <burndown>",
+		X:       []time.Time{begin, begin.AddDate(0, 0, 30), begin.AddDate(0, 0, 60)},
+		Layers:  [][]float64{{100, 80, 60}, {0, 50, -10}},
+		Names:   []string{"2018-01-01", "2018-01-31"},
+	}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, plot.WriteSVG(buffer))
+	checkXML(t, buffer.Bytes())
+	svg := buffer.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+	assert.Contains(t, svg, "test &lt;burndown&gt;")
+	assert.Equal(t, 2, strings.Count(svg, "<polygon "))
+	assert.Contains(t, svg, ">2018-01-31</text>")
+	assert.Contains(t, svg, ">2018-03-02</text>")
+	assert.Contains(t, svg, ">150</text>")
+}
+
+func TestStackPlotWriteSVGEmpty(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, (&StackPlot{}).WriteSVG(buffer))
+	checkXML(t, buffer.Bytes())
+	assert.NotContains(t, buffer.String(), "<polygon ")
+}
+
+func TestHeatmapWriteSVG(t *testing.T) {
+	heatmap := &Heatmap{
+		Caption: "overwrites",
+		Rows:    []string{"Alice", "Bob"},
+		Columns: []string{"Unidentified", "Alice", "Bob"},
+		Values:  [][]float64{{0, 0.5, 1}, {0.25, 2, -1}},
+	}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, heatmap.WriteSVG(buffer))
+	checkXML(t, buffer.Bytes())
+	svg := buffer.String()
+	assert.Equal(t, 6+1, strings.Count(svg, "<rect "))
+	assert.Contains(t, svg, "<title>Alice -&gt; Bob: 1.000</title>")
+	assert.Contains(t, svg, `fill="#ffffff"`)
+	assert.Contains(t, svg, `fill="#7f0000"`)
+}
+
+func TestHeatColor(t *testing.T) {
+	assert.Equal(t, "#ffffff", heatColor(0))
+	assert.Equal(t, "#7f0000", heatColor(1))
+	assert.Equal(t, "#ffffff", heatColor(-5))
+	assert.Equal(t, "#7f0000", heatColor(5))
+}
+
+func TestTruncateLabel(t *testing.T) {
+	assert.Equal(t, "short", TruncateLabel("short"))
+	long := strings.Repeat("я", 50)
+	truncated := TruncateLabel(long)
+	assert.Equal(t, maxLabelLength, len([]rune(truncated)))
+	assert.True(t, strings.HasSuffix(truncated, "..."))
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "0", FormatNumber(0))
+	assert.Equal(t, "500", FormatNumber(500))
+	assert.Equal(t, "12k", FormatNumber(12000))
+	assert.Equal(t, "2.5M", FormatNumber(2500000))
+}
+
+func TestNiceStep(t *testing.T) {
+	assert.Equal(t, 1.0, niceStep(0))
+	assert.Equal(t, 1.0, niceStep(0.7))
+	assert.Equal(t, 20.0, niceStep(14))
+	assert.Equal(t, 50.0, niceStep(28))
+	assert.Equal(t, 100.0, niceStep(51))
+}
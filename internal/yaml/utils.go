@@ -5,6 +5,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	yamlv2 "gopkg.in/yaml.v2"
 )
 
 // SafeString returns a string which is sufficiently quoted and escaped for YAML.
@@ -69,3 +71,32 @@ func PrintMatrix(writer io.Writer, matrix [][]int64, indent int, name string, fi
 		fmt.Fprintln(writer)
 	}
 }
+
+// ParseMatrix reads back the integer matrix written by PrintMatrix() as the YAML block.
+// Each line is a row, the values are separated with spaces.
+func ParseMatrix(text string) ([][]int64, error) {
+	text = strings.TrimRight(text, "\n")
+	if strings.TrimSpace(text) == "" {
+		return [][]int64{}, nil
+	}
+	lines := strings.Split(text, "\n")
+	matrix := make([][]int64, len(lines))
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		row := make([]int64, len(fields))
+		for j, field := range fields {
+			val, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("matrix row %d: %v", i, err)
+			}
+			row[j] = val
+		}
+		matrix[i] = row
+	}
+	return matrix, nil
+}
+
+// Unmarshal decodes the YAML text, e.g. the output of a LeafPipelineItem, into `out`.
+func Unmarshal(text []byte, out interface{}) error {
+	return yamlv2.Unmarshal(text, out)
+}
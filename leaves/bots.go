@@ -0,0 +1,33 @@
+package leaves
+
+import (
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+// droppedBots returns the bot identities whose commits must be excluded according to
+// identity.ConfigIdentityDetectorBots. The second value is false if the facts do not
+// contain the bots detected by identity.Detector.
+func droppedBots(facts map[string]interface{}) (map[int]bool, bool) {
+	bots, exists := facts[identity.FactIdentityDetectorBots].(map[int]bool)
+	if !exists {
+		return nil, false
+	}
+	if mode, _ := facts[identity.ConfigIdentityDetectorBots].(string); mode != identity.BotsDrop {
+		return nil, true
+	}
+	return bots, true
+}
+
+// withoutBots removes the dropped bots from the list of the commit authors.
+func withoutBots(authors []int, bots map[int]bool) []int {
+	if len(bots) == 0 {
+		return authors
+	}
+	result := make([]int, 0, len(authors))
+	for _, author := range authors {
+		if !bots[author] {
+			result = append(result, author)
+		}
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package leaves
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+func TestDroppedBots(t *testing.T) {
+	bots, exists := droppedBots(map[string]interface{}{})
+	assert.False(t, exists)
+	assert.Nil(t, bots)
+	facts := map[string]interface{}{identity.FactIdentityDetectorBots: map[int]bool{1: true}}
+	bots, exists = droppedBots(facts)
+	assert.True(t, exists)
+	assert.Nil(t, bots)
+	facts[identity.ConfigIdentityDetectorBots] = identity.BotsAggregate
+	bots, exists = droppedBots(facts)
+	assert.True(t, exists)
+	assert.Nil(t, bots)
+	facts[identity.ConfigIdentityDetectorBots] = identity.BotsDrop
+	bots, exists = droppedBots(facts)
+	assert.True(t, exists)
+	assert.Equal(t, map[int]bool{1: true}, bots)
+}
+
+func TestWithoutBots(t *testing.T) {
+	authors := []int{0, 1, 2}
+	assert.Equal(t, authors, withoutBots(authors, nil))
+	assert.Equal(t, []int{0, 2}, withoutBots(authors, map[int]bool{1: true}))
+	assert.Equal(t, []int{0, 1, 2}, authors)
+	assert.Equal(t, []int{}, withoutBots([]int{1}, map[int]bool{1: true}))
+}
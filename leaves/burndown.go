@@ -1,6 +1,8 @@
 package leaves
 
 import (
+	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +11,7 @@ import (
 	"math"
 	"os"
 	"sort"
+	"strconv"
 	"sync"
 	"unicode/utf8"
 
@@ -46,6 +49,10 @@ type BurndownAnalysis struct {
 	// PeopleNumber is the number of developers for which to collect the burndown stats. 0 disables it.
 	PeopleNumber int
 
+	// CoAuthorsCredit defines how the co-authors are credited: CoAuthorsCreditAuthor or
+	// CoAuthorsCreditSplit. Each line has a single owner, so the full credit is impossible.
+	CoAuthorsCredit string
+
 	// HibernationThreshold sets the hibernation threshold for the underlying
 	// RBTree allocator. It is useful to trade CPU time for reduced peak memory consumption
 	// if there are many branches.
@@ -91,6 +98,10 @@ type BurndownAnalysis struct {
 	mergedFiles map[string]bool
 	// mergedAuthor of the processed merge commit
 	mergedAuthor int
+	// coAuthors of the processed commit between whom the inserted lines are split
+	coAuthors []int
+	// droppedBots are the identities whose lines are not attributed to anybody
+	droppedBots map[int]bool
 	// renames is a quick and dirty solution for the "future branch renames" problem.
 	renames map[string]string
 	// matrix is the mutual deletions and self insertions.
@@ -139,6 +150,22 @@ type BurndownResult struct {
 	granularity int
 }
 
+// ReversedPeopleDict returns the developer names which correspond to the indexes in
+// PeopleHistories and PeopleMatrix.
+func (result BurndownResult) ReversedPeopleDict() []string {
+	return result.reversedPeopleDict
+}
+
+// Sampling returns the number of days between two consecutive rows of the histories.
+func (result BurndownResult) Sampling() int {
+	return result.sampling
+}
+
+// Granularity returns the number of days in each band of the histories.
+func (result BurndownResult) Granularity() int {
+	return result.granularity
+}
+
 const (
 	// ConfigBurndownGranularity is the name of the option to set BurndownAnalysis.Granularity.
 	ConfigBurndownGranularity = "Burndown.Granularity"
@@ -160,6 +187,8 @@ const (
 	ConfigBurndownHibernationDirectory = "Burndown.HibernationDirectory"
 	// ConfigBurndownDebug enables some extra debug assertions.
 	ConfigBurndownDebug = "Burndown.Debug"
+	// ConfigBurndownCoAuthorsCredit is the name of the option to set BurndownAnalysis.CoAuthorsCredit.
+	ConfigBurndownCoAuthorsCredit = "Burndown.CoAuthorsCredit"
 	// DefaultBurndownGranularity is the default number of days for BurndownAnalysis.Granularity
 	// and BurndownAnalysis.Sampling.
 	DefaultBurndownGranularity = 30
@@ -192,7 +221,7 @@ func (analyser *BurndownAnalysis) Provides() []string {
 func (analyser *BurndownAnalysis) Requires() []string {
 	arr := [...]string{
 		items.DependencyFileDiff, items.DependencyTreeChanges, items.DependencyBlobCache,
-		items.DependencyDay, identity.DependencyAuthor}
+		items.DependencyDay, identity.DependencyAuthor, identity.DependencyAuthors}
 	return arr[:]
 }
 
@@ -241,7 +270,14 @@ func (analyser *BurndownAnalysis) ListConfigurationOptions() []core.Configuratio
 		Description: "Validate the trees on each step.",
 		Flag:        "burndown-debug",
 		Type:        core.BoolConfigurationOption,
-		Default:     false},
+		Default:     false}, {
+		Name: ConfigBurndownCoAuthorsCredit,
+		Description: "How to credit the co-authors detected with --co-authors in --burndown-people: " +
+			"\"author\" ignores them, \"split\" divides the inserted lines equally. " +
+			"Not set means \"author\", or \"split\" with --signatures both.",
+		Flag:    "burndown-co-authors",
+		Type:    core.StringConfigurationOption,
+		Default: ""},
 	}
 	return options[:]
 }
@@ -280,6 +316,17 @@ func (analyser *BurndownAnalysis) Configure(facts map[string]interface{}) error
 	if val, exists := facts[ConfigBurndownDebug].(bool); exists {
 		analyser.Debug = val
 	}
+	if val, exists := droppedBots(facts); exists {
+		analyser.droppedBots = val
+	}
+	if val, exists := facts[ConfigBurndownCoAuthorsCredit].(string); exists {
+		credit, err := checkCoAuthorsCredit(analyser.Name(), val, CoAuthorsCreditSplit)
+		if err != nil {
+			return err
+		}
+		analyser.CoAuthorsCredit = credit
+	}
+	analyser.CoAuthorsCredit = signaturesCredit(facts, analyser.CoAuthorsCredit, CoAuthorsCreditSplit)
 	return nil
 }
 
@@ -342,9 +389,19 @@ func (analyser *BurndownAnalysis) Consume(deps map[string]interface{}) (map[stri
 	}
 	author := deps[identity.DependencyAuthor].(int)
 	day := deps[items.DependencyDay].(int)
+	analyser.coAuthors = nil
+	// we cannot skip the commits of the bots because the files must stay consistent
+	bot := analyser.droppedBots[author]
+	if bot {
+		author = identity.AuthorMissing
+	}
 	if !deps[core.DependencyIsMerge].(bool) {
 		analyser.day = day
 		analyser.onNewDay()
+		if analyser.PeopleNumber > 0 && !bot {
+			analyser.coAuthors = withoutBots(
+				creditedAuthors(deps, analyser.CoAuthorsCredit), analyser.droppedBots)
+		}
 	} else {
 		// effectively disables the status updates if the commit is a merge
 		// we will analyse the conflicts resolution in Merge()
@@ -391,6 +448,12 @@ func (analyser *BurndownAnalysis) Fork(n int) []core.PipelineItem {
 	return result
 }
 
+// Sequential returns true because the clones share the global history and the people
+// matrices which are updated by every commit.
+func (analyser *BurndownAnalysis) Sequential() bool {
+	return true
+}
+
 // Merge combines several items together. We apply the special file merging logic here.
 func (analyser *BurndownAnalysis) Merge(branches []core.PipelineItem) {
 	all := make([]*BurndownAnalysis, len(branches)+1)
@@ -484,6 +547,108 @@ func (analyser *BurndownAnalysis) Boot() error {
 	return nil
 }
 
+// burndownState is the serialized state of BurndownAnalysis, see SaveState().
+type burndownState struct {
+	GlobalHistory   sparseHistory
+	FileHistories   map[string]sparseHistory
+	PeopleHistories []sparseHistory
+	// Files maps file names to the flattened line interval trees: key, value, key, value, ...
+	Files        map[string][]int
+	MergedFiles  map[string]bool
+	MergedAuthor int
+	Renames      map[string]string
+	Matrix       []map[int]int64
+	Day          int
+	PreviousDay  int
+}
+
+// SaveState writes the histories and the line interval trees of the files.
+func (analyser *BurndownAnalysis) SaveState(writer io.Writer) error {
+	if analyser.fileAllocator.Size() == 0 && len(analyser.files) > 0 {
+		return errors.New("cannot save the state of a hibernated BurndownAnalysis")
+	}
+	files := map[string][]int{}
+	for name, file := range analyser.files {
+		var tree []int
+		file.ForEach(func(line, value int) {
+			tree = append(tree, line, value)
+		})
+		files[name] = tree
+	}
+	return gob.NewEncoder(writer).Encode(burndownState{
+		GlobalHistory:   analyser.globalHistory,
+		FileHistories:   analyser.fileHistories,
+		PeopleHistories: analyser.peopleHistories,
+		Files:           files,
+		MergedFiles:     analyser.mergedFiles,
+		MergedAuthor:    analyser.mergedAuthor,
+		Renames:         analyser.renames,
+		Matrix:          analyser.matrix,
+		Day:             analyser.day,
+		PreviousDay:     analyser.previousDay,
+	})
+}
+
+// LoadState restores the state written by SaveState(). The files are rebuilt in a new
+// RBTree allocator.
+func (analyser *BurndownAnalysis) LoadState(reader io.Reader) error {
+	state := burndownState{}
+	err := gob.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return err
+	}
+	// new developers may appear in an incremental analysis, but the known ones must stay
+	if len(state.PeopleHistories) > analyser.PeopleNumber ||
+		len(state.Matrix) > analyser.PeopleNumber {
+		return fmt.Errorf("the number of people does not match: %d > %d",
+			len(state.PeopleHistories), analyser.PeopleNumber)
+	}
+	for len(state.PeopleHistories) < analyser.PeopleNumber {
+		state.PeopleHistories = append(state.PeopleHistories, nil)
+	}
+	for len(state.Matrix) < analyser.PeopleNumber {
+		state.Matrix = append(state.Matrix, nil)
+	}
+	analyser.globalHistory = state.GlobalHistory
+	if analyser.globalHistory == nil {
+		analyser.globalHistory = sparseHistory{}
+	}
+	analyser.fileHistories = state.FileHistories
+	if analyser.fileHistories == nil {
+		analyser.fileHistories = map[string]sparseHistory{}
+	}
+	analyser.peopleHistories = state.PeopleHistories
+	analyser.mergedFiles = state.MergedFiles
+	if analyser.mergedFiles == nil {
+		analyser.mergedFiles = map[string]bool{}
+	}
+	analyser.mergedAuthor = state.MergedAuthor
+	analyser.renames = state.Renames
+	if analyser.renames == nil {
+		analyser.renames = map[string]string{}
+	}
+	analyser.matrix = state.Matrix
+	analyser.day = state.Day
+	analyser.previousDay = state.PreviousDay
+	analyser.fileAllocator = rbtree.NewAllocator()
+	analyser.fileAllocator.HibernationThreshold = analyser.HibernationThreshold
+	analyser.files = map[string]*burndown.File{}
+	for name, tree := range state.Files {
+		keys := make([]int, len(tree)/2)
+		vals := make([]int, len(tree)/2)
+		for i := range keys {
+			keys[i] = tree[i*2]
+			vals[i] = tree[i*2+1]
+			if vals[i] < 0 {
+				vals[i] = burndown.TreeEnd
+			}
+		}
+		analyser.files[name] = burndown.NewFileFromTree(
+			keys, vals, analyser.fileAllocator, analyser.fileUpdaters(name)...)
+	}
+	return nil
+}
+
 // Finalize returns the result of the analysis. Further Consume() calls are not expected.
 func (analyser *BurndownAnalysis) Finalize() interface{} {
 	globalHistory, lastDay := analyser.groupSparseHistory(analyser.globalHistory, -1)
@@ -552,16 +717,75 @@ func (analyser *BurndownAnalysis) Finalize() interface{} {
 }
 
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (analyser *BurndownAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (analyser *BurndownAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	burndownResult := result.(BurndownResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return analyser.serializeBinary(&burndownResult, writer)
+	case core.JSONFormat:
+		return analyser.serializeJSON(&burndownResult, writer)
 	}
 	analyser.serializeText(&burndownResult, writer)
 	return nil
 }
 
+// Metrics exports the number of lines which survived until the last sample per band and
+// per developer.
+func (analyser *BurndownAnalysis) Metrics(result interface{}) []core.MetricFamily {
+	burndownResult := result.(BurndownResult)
+	bands := core.MetricFamily{
+		Name: "hercules_burndown_surviving_lines",
+		Help: "Number of lines which were written in the band and still exist.",
+		Type: core.GaugeMetric,
+	}
+	if rows := len(burndownResult.GlobalHistory); rows > 0 {
+		for band, value := range burndownResult.GlobalHistory[rows-1] {
+			bands.Metrics = append(bands.Metrics, core.Metric{
+				Labels: map[string]string{
+					"band":      strconv.Itoa(band),
+					"start_day": strconv.Itoa(band * burndownResult.granularity),
+				},
+				Value: float64(value),
+			})
+		}
+	}
+	families := []core.MetricFamily{bands}
+	if len(burndownResult.PeopleHistories) == 0 {
+		return families
+	}
+	people := core.MetricFamily{
+		Name: "hercules_burndown_developer_surviving_lines",
+		Help: "Number of lines which were written by the developer and still exist.",
+		Type: core.GaugeMetric,
+	}
+	var unmatched int64
+	hasUnmatched := false
+	for i, history := range burndownResult.PeopleHistories {
+		var sum int64
+		if len(history) > 0 {
+			for _, value := range history[len(history)-1] {
+				sum += value
+			}
+		}
+		if i >= len(burndownResult.reversedPeopleDict) {
+			// all the unmatched developers share the same label
+			unmatched += sum
+			hasUnmatched = true
+			continue
+		}
+		people.Metrics = append(people.Metrics, core.Metric{
+			Labels: map[string]string{"developer": burndownResult.reversedPeopleDict[i]},
+			Value:  float64(sum)})
+	}
+	if hasUnmatched {
+		people.Metrics = append(people.Metrics, core.Metric{
+			Labels: map[string]string{"developer": "<unmatched>"}, Value: float64(unmatched)})
+	}
+	return append(families, people)
+}
+
 // Deserialize converts the specified protobuf bytes to BurndownResult.
 func (analyser *BurndownAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
 	msg := pb.BurndownAnalysisResults{}
@@ -611,6 +835,103 @@ func (analyser *BurndownAnalysis) Deserialize(pbmessage []byte) (interface{}, er
 	return result, nil
 }
 
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to BurndownResult.
+// The YAML output does not list the empty files in files_ownership, so the ownership is restored
+// only if it matches the files.
+func (analyser *BurndownAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	message := burndownJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &message); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var err error
+		if message, err = parseBurndownYAML(text); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	result := BurndownResult{
+		GlobalHistory:      message.Project,
+		FileHistories:      map[string]DenseHistory{},
+		FileOwnership:      map[string]map[int]int{},
+		PeopleHistories:    make([]DenseHistory, len(message.PeopleSequence)),
+		PeopleMatrix:       message.PeopleInteraction,
+		reversedPeopleDict: message.PeopleSequence,
+		granularity:        message.Granularity,
+		sampling:           message.Sampling,
+	}
+	for key, val := range message.Files {
+		result.FileHistories[key] = val
+	}
+	for key, val := range message.FilesOwnership {
+		result.FileOwnership[key] = val
+	}
+	for i, name := range message.PeopleSequence {
+		result.PeopleHistories[i] = message.People[name]
+	}
+	return result, nil
+}
+
+// burndownYAML is the layout of the YAML output. The matrices are written as text blocks
+// and files_ownership follows the sorted file names.
+type burndownYAML struct {
+	Granularity       int               `yaml:"granularity"`
+	Sampling          int               `yaml:"sampling"`
+	Project           string            `yaml:"project"`
+	Files             map[string]string `yaml:"files"`
+	FilesOwnership    []map[int]int     `yaml:"files_ownership"`
+	PeopleSequence    []string          `yaml:"people_sequence"`
+	People            map[string]string `yaml:"people"`
+	PeopleInteraction string            `yaml:"people_interaction"`
+}
+
+func parseBurndownYAML(text []byte) (burndownJSON, error) {
+	parsed := burndownYAML{}
+	message := burndownJSON{}
+	if err := yaml.Unmarshal(text, &parsed); err != nil {
+		return message, err
+	}
+	message.Granularity = parsed.Granularity
+	message.Sampling = parsed.Sampling
+	message.PeopleSequence = parsed.PeopleSequence
+	var err error
+	if message.Project, err = yaml.ParseMatrix(parsed.Project); err != nil {
+		return message, fmt.Errorf("project: %v", err)
+	}
+	if len(parsed.Files) > 0 {
+		message.Files = map[string][][]int64{}
+		for key, val := range parsed.Files {
+			if message.Files[key], err = yaml.ParseMatrix(val); err != nil {
+				return message, fmt.Errorf("files: %s: %v", key, err)
+			}
+		}
+		if keys := sortedKeys(message.Files); len(keys) == len(parsed.FilesOwnership) {
+			message.FilesOwnership = map[string]map[int]int{}
+			for i, key := range keys {
+				message.FilesOwnership[key] = parsed.FilesOwnership[i]
+			}
+		}
+	}
+	if len(parsed.People) > 0 {
+		message.People = map[string][][]int64{}
+		for key, val := range parsed.People {
+			if message.People[key], err = yaml.ParseMatrix(val); err != nil {
+				return message, fmt.Errorf("people: %s: %v", key, err)
+			}
+		}
+	}
+	if parsed.PeopleInteraction != "" {
+		if message.PeopleInteraction, err = yaml.ParseMatrix(parsed.PeopleInteraction); err != nil {
+			return message, fmt.Errorf("people_interaction: %v", err)
+		}
+	}
+	return message, nil
+}
+
 // MergeResults combines two BurndownResult-s together.
 func (analyser *BurndownAnalysis) MergeResults(
 	r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
@@ -995,6 +1316,66 @@ func (analyser *BurndownAnalysis) serializeText(result *BurndownResult, writer i
 	}
 }
 
+// burndownJSON mirrors the YAML layout of BurndownResult.
+type burndownJSON struct {
+	Granularity       int                    `json:"granularity"`
+	Sampling          int                    `json:"sampling"`
+	Project           [][]int64              `json:"project"`
+	Files             map[string][][]int64   `json:"files,omitempty"`
+	FilesOwnership    map[string]map[int]int `json:"files_ownership,omitempty"`
+	PeopleSequence    []string               `json:"people_sequence,omitempty"`
+	People            map[string][][]int64   `json:"people,omitempty"`
+	PeopleInteraction [][]int64              `json:"people_interaction,omitempty"`
+}
+
+func (analyser *BurndownAnalysis) serializeJSON(result *BurndownResult, writer io.Writer) error {
+	message := burndownJSON{
+		Granularity: result.granularity,
+		Sampling:    result.sampling,
+		Project:     alignMatrix(result.GlobalHistory, true),
+	}
+	if len(result.FileHistories) > 0 {
+		message.Files = map[string][][]int64{}
+		for key, val := range result.FileHistories {
+			message.Files[key] = alignMatrix(val, true)
+		}
+		message.FilesOwnership = result.FileOwnership
+	}
+	if len(result.PeopleHistories) > 0 {
+		message.PeopleSequence = make([]string, len(result.PeopleHistories))
+		message.People = map[string][][]int64{}
+		for key, val := range result.PeopleHistories {
+			message.PeopleSequence[key] = result.reversedPeopleDict[key]
+			message.People[result.reversedPeopleDict[key]] = alignMatrix(val, true)
+		}
+		message.PeopleInteraction = alignMatrix(result.PeopleMatrix, false)
+	}
+	return json.NewEncoder(writer).Encode(message)
+}
+
+// alignMatrix pads the rows of the matrix to the same length as yaml.PrintMatrix() does.
+// `fixNegative` changes all negative values to 0.
+func alignMatrix(matrix DenseHistory, fixNegative bool) [][]int64 {
+	if len(matrix) == 0 {
+		return [][]int64{}
+	}
+	width := len(matrix[len(matrix)-1])
+	aligned := make([][]int64, len(matrix))
+	for i, row := range matrix {
+		arow := make([]int64, width)
+		copy(arow, row)
+		if fixNegative {
+			for j, val := range arow {
+				if val < 0 {
+					arow[j] = 0
+				}
+			}
+		}
+		aligned[i] = arow
+	}
+	return aligned
+}
+
 func (analyser *BurndownAnalysis) serializeBinary(result *BurndownResult, writer io.Writer) error {
 	message := pb.BurndownAnalysisResults{
 		Granularity: int32(result.granularity),
@@ -1157,6 +1538,14 @@ func (analyser *BurndownAnalysis) updateMatrix(currentTime, previousTime, delta
 
 func (analyser *BurndownAnalysis) newFile(
 	hash plumbing.Hash, name string, author int, day int, size int) (*burndown.File, error) {
+	if analyser.PeopleNumber > 0 {
+		day = analyser.packPersonWithDay(author, day)
+	}
+	return burndown.NewFile(day, size, analyser.fileAllocator, analyser.fileUpdaters(name)...), nil
+}
+
+// fileUpdaters returns the callbacks which must be attached to the file with the specified name.
+func (analyser *BurndownAnalysis) fileUpdaters(name string) []burndown.Updater {
 	updaters := make([]burndown.Updater, 1)
 	updaters[0] = analyser.updateGlobal
 	if analyser.TrackFiles {
@@ -1173,9 +1562,8 @@ func (analyser *BurndownAnalysis) newFile(
 	if analyser.PeopleNumber > 0 {
 		updaters = append(updaters, analyser.updateAuthor)
 		updaters = append(updaters, analyser.updateMatrix)
-		day = analyser.packPersonWithDay(author, day)
 	}
-	return burndown.NewFile(day, size, analyser.fileAllocator, updaters...), nil
+	return updaters
 }
 
 func (analyser *BurndownAnalysis) handleInsertion(
@@ -1195,7 +1583,12 @@ func (analyser *BurndownAnalysis) handleInsertion(
 	if analyser.day != burndown.TreeMergeMark {
 		hash = blob.Hash
 	}
-	file, err = analyser.newFile(hash, name, author, analyser.day, lines)
+	if len(analyser.coAuthors) > 1 {
+		file, err = analyser.newFile(hash, name, author, analyser.day, 0)
+		analyser.insertLines(file, author, 0, lines, 0)
+	} else {
+		file, err = analyser.newFile(hash, name, author, analyser.day, lines)
+	}
 	analyser.files[name] = file
 	if analyser.day == burndown.TreeMergeMark {
 		analyser.mergedFiles[name] = true
@@ -1297,7 +1690,7 @@ func (analyser *BurndownAnalysis) handleModification(
 	apply := func(edit diffmatchpatch.Diff) {
 		length := utf8.RuneCountInString(edit.Text)
 		if edit.Type == diffmatchpatch.DiffInsert {
-			file.Update(analyser.packPersonWithDay(author, analyser.day), position, length, 0)
+			analyser.insertLines(file, author, position, length, 0)
 			position += length
 		} else {
 			file.Update(analyser.packPersonWithDay(author, analyser.day), position, 0, length)
@@ -1335,7 +1728,7 @@ func (analyser *BurndownAnalysis) handleModification(
 					debugError()
 					return errors.New("DiffInsert may not appear after DiffInsert")
 				}
-				file.Update(analyser.packPersonWithDay(author, analyser.day), position, length,
+				analyser.insertLines(file, author, position, length,
 					utf8.RuneCountInString(pending.Text))
 				if analyser.Debug {
 					file.Validate()
@@ -1368,6 +1761,25 @@ func (analyser *BurndownAnalysis) handleModification(
 	return nil
 }
 
+// insertLines inserts `length` lines at `position` after deleting `deleted` lines there.
+// The inserted lines are divided between the co-authors in CoAuthorsCreditSplit mode,
+// the deletions are always attributed to the author.
+func (analyser *BurndownAnalysis) insertLines(
+	file *burndown.File, author int, position int, length int, deleted int) {
+	if len(analyser.coAuthors) < 2 {
+		file.Update(analyser.packPersonWithDay(author, analyser.day), position, length, deleted)
+		return
+	}
+	for i, share := range splitCredit(length, len(analyser.coAuthors)) {
+		if i > 0 {
+			deleted = 0
+		}
+		file.Update(analyser.packPersonWithDay(analyser.coAuthors[i], analyser.day),
+			position, share, deleted)
+		position += share
+	}
+}
+
 func (analyser *BurndownAnalysis) handleRename(from, to string) error {
 	if from == to {
 		return nil
@@ -38,7 +38,7 @@ func TestBurndownMeta(t *testing.T) {
 	assert.Len(t, bd.Provides(), 0)
 	required := [...]string{
 		items.DependencyFileDiff, items.DependencyTreeChanges, items.DependencyBlobCache,
-		items.DependencyDay, identity.DependencyAuthor}
+		items.DependencyDay, identity.DependencyAuthor, identity.DependencyAuthors}
 	for _, name := range required {
 		assert.Contains(t, bd.Requires(), name)
 	}
@@ -49,7 +49,7 @@ func TestBurndownMeta(t *testing.T) {
 		case ConfigBurndownGranularity, ConfigBurndownSampling, ConfigBurndownTrackFiles,
 			ConfigBurndownTrackPeople, ConfigBurndownHibernationThreshold,
 			ConfigBurndownHibernationToDisk, ConfigBurndownHibernationDirectory,
-			ConfigBurndownDebug:
+			ConfigBurndownDebug, ConfigBurndownCoAuthorsCredit:
 			matches++
 		}
 	}
@@ -358,6 +358,79 @@ func TestBurndownConsumeFinalize(t *testing.T) {
 	}
 }
 
+func TestBurndownConsumeCoAuthors(t *testing.T) {
+	bd := BurndownAnalysis{
+		Granularity:  30,
+		Sampling:     30,
+		PeopleNumber: 2,
+	}
+	assert.NotNil(t, bd.Configure(map[string]interface{}{
+		ConfigBurndownCoAuthorsCredit: CoAuthorsCreditFull}))
+	assert.Nil(t, bd.Configure(map[string]interface{}{
+		ConfigBurndownCoAuthorsCredit: CoAuthorsCreditSplit}))
+	assert.Equal(t, CoAuthorsCreditSplit, bd.CoAuthorsCredit)
+	assert.Nil(t, bd.Initialize(test.Repository))
+	deps := map[string]interface{}{}
+	deps[identity.DependencyAuthor] = 0
+	deps[identity.DependencyAuthors] = []int{0, 1}
+	deps[items.DependencyDay] = 0
+	cache := map[plumbing.Hash]*items.CachedBlob{}
+	AddHash(t, cache, "291286b4ac41952cbd1389fda66420ec03c1a9fe")
+	AddHash(t, cache, "c29112dbd697ad9b401333b80c18a63951bc18d9")
+	deps[items.DependencyBlobCache] = cache
+	treeTo, _ := test.Repository.TreeObject(plumbing.NewHash(
+		"994eac1cd07235bb9815e547a75c84265dea00f5"))
+	changes := object.Changes{&object.Change{From: object.ChangeEntry{}, To: object.ChangeEntry{
+		Name: "cmd/hercules/main.go",
+		Tree: treeTo,
+		TreeEntry: object.TreeEntry{
+			Name: "cmd/hercules/main.go",
+			Mode: 0100644,
+			Hash: plumbing.NewHash("c29112dbd697ad9b401333b80c18a63951bc18d9"),
+		},
+	}}, &object.Change{From: object.ChangeEntry{}, To: object.ChangeEntry{
+		Name: ".travis.yml",
+		Tree: treeTo,
+		TreeEntry: object.TreeEntry{
+			Name: ".travis.yml",
+			Mode: 0100644,
+			Hash: plumbing.NewHash("291286b4ac41952cbd1389fda66420ec03c1a9fe"),
+		},
+	}}}
+	deps[items.DependencyTreeChanges] = changes
+	deps[items.DependencyFileDiff] = map[string]items.FileDiffData{}
+	deps[core.DependencyIsMerge] = false
+	_, err := bd.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, bd.files["cmd/hercules/main.go"].Len(), 207)
+	assert.Equal(t, bd.files[".travis.yml"].Len(), 12)
+	assert.Equal(t, bd.globalHistory[0][0], int64(12+207))
+	assert.Equal(t, bd.peopleHistories[0][0][0], int64(6+104))
+	assert.Equal(t, bd.peopleHistories[1][0][0], int64(6+103))
+	assert.Equal(t, bd.matrix[0][authorSelf], int64(6+104))
+	assert.Equal(t, bd.matrix[1][authorSelf], int64(6+103))
+
+	// the lines of the dropped bots belong to nobody
+	bd = BurndownAnalysis{
+		Granularity:  30,
+		Sampling:     30,
+		PeopleNumber: 2,
+	}
+	assert.Nil(t, bd.Configure(map[string]interface{}{
+		ConfigBurndownCoAuthorsCredit:       CoAuthorsCreditSplit,
+		identity.FactIdentityDetectorBots:   map[int]bool{1: true},
+		identity.ConfigIdentityDetectorBots: identity.BotsDrop,
+	}))
+	assert.Nil(t, bd.Initialize(test.Repository))
+	deps[identity.DependencyAuthor] = 1
+	deps[identity.DependencyAuthors] = []int{1, 0}
+	_, err = bd.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, bd.globalHistory[0][0], int64(12+207))
+	assert.Len(t, bd.peopleHistories[0], 0)
+	assert.Len(t, bd.peopleHistories[1], 0)
+}
+
 func TestBurndownConsumeMergeAuthorMissing(t *testing.T) {
 	deps := map[string]interface{}{}
 	deps[items.DependencyDay] = 0
@@ -607,12 +680,36 @@ func bakeBurndownForSerialization(t *testing.T, firstAuthor, secondAuthor int) (
 	return out, &bd
 }
 
+func TestBurndownMetrics(t *testing.T) {
+	out, _ := bakeBurndownForSerialization(t, 0, 1)
+	bd := &BurndownAnalysis{}
+	families := bd.Metrics(out)
+	assert.Len(t, families, 2)
+	assert.Equal(t, "hercules_burndown_surviving_lines", families[0].Name)
+	assert.Equal(t, core.GaugeMetric, families[0].Type)
+	assert.Equal(t, []core.Metric{
+		{Labels: map[string]string{"band": "0", "start_day": "0"}, Value: 464},
+		{Labels: map[string]string{"band": "1", "start_day": "30"}, Value: 369},
+	}, families[0].Metrics)
+	assert.Equal(t, "hercules_burndown_developer_surviving_lines", families[1].Name)
+	assert.Equal(t, []core.Metric{
+		{Labels: map[string]string{"developer": "one@srcd"}, Value: 464},
+		{Labels: map[string]string{"developer": "two@srcd"}, Value: 369},
+	}, families[1].Metrics)
+	// both developers are unmatched
+	out.reversedPeopleDict = nil
+	families = bd.Metrics(out)
+	assert.Equal(t, []core.Metric{
+		{Labels: map[string]string{"developer": "<unmatched>"}, Value: 833},
+	}, families[1].Metrics)
+}
+
 func TestBurndownSerialize(t *testing.T) {
 	out, _ := bakeBurndownForSerialization(t, 0, 1)
 	bd := &BurndownAnalysis{}
 
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, bd.Serialize(out, false, buffer))
+	assert.Nil(t, bd.Serialize(out, core.YAMLFormat, buffer))
 	assert.Equal(t, buffer.String(), `  granularity: 30
   sampling: 30
   "project": |-
@@ -645,7 +742,7 @@ func TestBurndownSerialize(t *testing.T) {
      369    0    0    0
 `)
 	buffer = &bytes.Buffer{}
-	bd.Serialize(out, true, buffer)
+	bd.Serialize(out, core.ProtobufFormat, buffer)
 	msg := pb.BurndownAnalysisResults{}
 	proto.Unmarshal(buffer.Bytes(), &msg)
 	assert.Equal(t, msg.Granularity, int32(30))
@@ -694,12 +791,39 @@ func TestBurndownSerialize(t *testing.T) {
 	assert.Equal(t, msg.PeopleInteraction.Indptr, indptr[:])
 }
 
+func TestBurndownSerializeJSON(t *testing.T) {
+	out, _ := bakeBurndownForSerialization(t, 0, 1)
+	bd := &BurndownAnalysis{}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, bd.Serialize(out, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{
+  "granularity": 30,
+  "sampling": 30,
+  "project": [[1145, 0], [464, 369]],
+  "files": {
+    "burndown.go": [[926, 0], [293, 250]],
+    "cmd/hercules/main.go": [[207, 0], [171, 119]]
+  },
+  "files_ownership": {
+    "burndown.go": {"0": 293, "1": 250},
+    "cmd/hercules/main.go": {"0": 171, "1": 119}
+  },
+  "people_sequence": ["one@srcd", "two@srcd"],
+  "people": {
+    "one@srcd": [[1145, 0], [464, 0]],
+    "two@srcd": [[0, 0], [0, 369]]
+  },
+  "people_interaction": [[1145, 0, 0, -681], [369, 0, 0, 0]]
+}`, buffer.String())
+}
+
 func TestBurndownSerializeAuthorMissing(t *testing.T) {
 	out, _ := bakeBurndownForSerialization(t, 0, identity.AuthorMissing)
 	bd := &BurndownAnalysis{}
 
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, bd.Serialize(out, false, buffer))
+	assert.Nil(t, bd.Serialize(out, core.YAMLFormat, buffer))
 	assert.Equal(t, buffer.String(), `  granularity: 30
   sampling: 30
   "project": |-
@@ -732,7 +856,7 @@ func TestBurndownSerializeAuthorMissing(t *testing.T) {
        0    0    0    0
 `)
 	buffer = &bytes.Buffer{}
-	bd.Serialize(out, true, buffer)
+	bd.Serialize(out, core.ProtobufFormat, buffer)
 	msg := pb.BurndownAnalysisResults{}
 	proto.Unmarshal(buffer.Bytes(), &msg)
 	assert.Equal(t, msg.Granularity, int32(30))
@@ -1289,6 +1413,35 @@ func TestBurndownDeserialize(t *testing.T) {
 	assert.Equal(t, result.sampling, 30)
 }
 
+func TestBurndownDeserializeText(t *testing.T) {
+	bd := BurndownAnalysis{}
+	result := BurndownResult{
+		GlobalHistory: DenseHistory{{10, 0}, {8, 5}},
+		FileHistories: map[string]DenseHistory{
+			"a.go": {{10, 0}, {8, 0}},
+			"b.go": {{0, 0}, {0, 5}},
+		},
+		FileOwnership: map[string]map[int]int{
+			"a.go": {0: 8},
+			"b.go": {1: 5},
+		},
+		PeopleHistories:    []DenseHistory{{{10, 0}, {8, 0}}, {{0, 0}, {0, 5}}},
+		PeopleMatrix:       DenseHistory{{10, 0, 0, 0}, {5, 0, -2, 0}},
+		reversedPeopleDict: []string{"one@srcd", "two@srcd"},
+		granularity:        30,
+		sampling:           15,
+	}
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, bd.Serialize(result, format, buffer))
+		iresult, err := bd.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, result, iresult, format)
+	}
+	_, err := bd.DeserializeText([]byte("project: |-\n  1 x\n"), core.YAMLFormat)
+	assert.NotNil(t, err)
+}
+
 func TestBurndownEmptyFileHistory(t *testing.T) {
 	bd := &BurndownAnalysis{
 		Sampling:      30,
@@ -1349,6 +1502,40 @@ func TestBurndownHibernateBootSerialize(t *testing.T) {
 	assert.Empty(t, bd.hibernatedFileName)
 }
 
+func TestBurndownSaveLoadState(t *testing.T) {
+	out, bd := bakeBurndownForSerialization(t, 0, 1)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, bd.SaveState(buffer))
+	state := buffer.Bytes()
+	bd2 := &BurndownAnalysis{
+		Granularity:  30,
+		Sampling:     30,
+		PeopleNumber: 2,
+		TrackFiles:   true,
+	}
+	assert.Nil(t, bd2.Initialize(test.Repository))
+	bd2.reversedPeopleDict = bd.reversedPeopleDict
+	assert.Nil(t, bd2.LoadState(bytes.NewBuffer(state)))
+	assert.Len(t, bd2.files, len(bd.files))
+	for key, file := range bd.files {
+		assert.Equal(t, file.Dump(), bd2.files[key].Dump())
+	}
+	assert.Equal(t, bd.day, bd2.day)
+	assert.Equal(t, bd.previousDay, bd2.previousDay)
+	assert.Equal(t, out, bd2.Finalize())
+	bd3 := &BurndownAnalysis{Granularity: 30, Sampling: 30, PeopleNumber: 1}
+	assert.Nil(t, bd3.Initialize(test.Repository))
+	assert.NotNil(t, bd3.LoadState(bytes.NewBuffer(state)))
+	assert.NotNil(t, bd3.LoadState(bytes.NewBuffer([]byte("garbage"))))
+	bd4 := &BurndownAnalysis{Granularity: 30, Sampling: 30, PeopleNumber: 3, TrackFiles: true}
+	assert.Nil(t, bd4.Initialize(test.Repository))
+	assert.Nil(t, bd4.LoadState(bytes.NewBuffer(state)))
+	assert.Len(t, bd4.peopleHistories, 3)
+	assert.Len(t, bd4.matrix, 3)
+	assert.Nil(t, bd.Hibernate())
+	assert.NotNil(t, bd.SaveState(&bytes.Buffer{}))
+}
+
 func TestBurndownAddBurndownMatrix(t *testing.T) {
 	h := DenseHistory{
 		[]int64{13430, 0, 0, 0},
@@ -0,0 +1,68 @@
+package leaves
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/hercules.v9/internal/core"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+const (
+	// CoAuthorsCreditAuthor credits only the commit author, the co-authors are ignored.
+	CoAuthorsCreditAuthor = "author"
+	// CoAuthorsCreditSplit divides the changed lines equally between the author and the co-authors.
+	CoAuthorsCreditSplit = "split"
+	// CoAuthorsCreditFull gives the full credit for the commit to the author and to each co-author.
+	CoAuthorsCreditFull = "full"
+)
+
+// checkCoAuthorsCredit validates the value of the co-authors option of the analysis.
+// The empty value means that the option is not set, see signaturesCredit().
+func checkCoAuthorsCredit(analysis, credit string, supported ...string) (string, error) {
+	if credit == "" || credit == CoAuthorsCreditAuthor {
+		return credit, nil
+	}
+	for _, mode := range supported {
+		if credit == mode {
+			return credit, nil
+		}
+	}
+	return "", fmt.Errorf("%s does not support the co-authors credit mode %q", analysis, credit)
+}
+
+// signaturesCredit returns the credit mode which the analysis applies when the developers are
+// identified by both commit signatures: if the mode is not set, the default CoAuthorsCreditAuthor
+// would ignore the committers, so the specified one is used instead. The explicit modes are kept.
+func signaturesCredit(facts map[string]interface{}, credit, both string) string {
+	signatures, _ := facts[core.FactPipelineSignatures].(string)
+	if signatures == identity.SignaturesBoth && credit == "" {
+		return both
+	}
+	return credit
+}
+
+// creditedAuthors returns the indices of the commit authors who receive the credit.
+// Only the author is returned if the mode is CoAuthorsCreditAuthor or the co-authors are unknown.
+func creditedAuthors(deps map[string]interface{}, credit string) []int {
+	author := deps[identity.DependencyAuthor].(int)
+	if credit == "" || credit == CoAuthorsCreditAuthor {
+		return []int{author}
+	}
+	if authors, exists := deps[identity.DependencyAuthors].([]int); exists && len(authors) > 0 {
+		return authors
+	}
+	return []int{author}
+}
+
+// splitCredit divides the value between n authors as evenly as possible. The first authors
+// receive the remainder so that the sum does not change.
+func splitCredit(value, n int) []int {
+	shares := make([]int, n)
+	for i := range shares {
+		shares[i] = value / n
+		if i < value%n {
+			shares[i]++
+		}
+	}
+	return shares
+}
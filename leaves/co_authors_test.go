@@ -0,0 +1,51 @@
+package leaves
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/hercules.v9/internal/core"
+	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+)
+
+func TestCheckCoAuthorsCredit(t *testing.T) {
+	credit, err := checkCoAuthorsCredit("Devs", "", CoAuthorsCreditSplit)
+	assert.Nil(t, err)
+	assert.Equal(t, "", credit)
+	credit, err = checkCoAuthorsCredit("Devs", CoAuthorsCreditAuthor, CoAuthorsCreditSplit)
+	assert.Nil(t, err)
+	assert.Equal(t, CoAuthorsCreditAuthor, credit)
+	credit, err = checkCoAuthorsCredit("Devs", CoAuthorsCreditSplit, CoAuthorsCreditSplit)
+	assert.Nil(t, err)
+	assert.Equal(t, CoAuthorsCreditSplit, credit)
+	_, err = checkCoAuthorsCredit("Couples", CoAuthorsCreditSplit, CoAuthorsCreditFull)
+	assert.EqualError(t, err, `Couples does not support the co-authors credit mode "split"`)
+}
+
+func TestCreditedAuthors(t *testing.T) {
+	deps := map[string]interface{}{identity.DependencyAuthor: 1}
+	assert.Equal(t, []int{1}, creditedAuthors(deps, CoAuthorsCreditSplit))
+	deps[identity.DependencyAuthors] = []int{1, 0, 2}
+	assert.Equal(t, []int{1}, creditedAuthors(deps, CoAuthorsCreditAuthor))
+	assert.Equal(t, []int{1, 0, 2}, creditedAuthors(deps, CoAuthorsCreditFull))
+}
+
+func TestSignaturesCredit(t *testing.T) {
+	facts := map[string]interface{}{}
+	assert.Equal(t, CoAuthorsCreditAuthor, signaturesCredit(facts, CoAuthorsCreditAuthor, CoAuthorsCreditFull))
+	assert.Equal(t, "", signaturesCredit(facts, "", CoAuthorsCreditFull))
+	facts[core.FactPipelineSignatures] = identity.SignaturesBoth
+	// the explicit mode is respected
+	assert.Equal(t, CoAuthorsCreditAuthor, signaturesCredit(facts, CoAuthorsCreditAuthor, CoAuthorsCreditFull))
+	assert.Equal(t, CoAuthorsCreditSplit, signaturesCredit(facts, "", CoAuthorsCreditSplit))
+	assert.Equal(t, CoAuthorsCreditSplit, signaturesCredit(facts, CoAuthorsCreditSplit, CoAuthorsCreditFull))
+	facts[core.FactPipelineSignatures] = identity.SignaturesCommitter
+	assert.Equal(t, CoAuthorsCreditAuthor, signaturesCredit(facts, CoAuthorsCreditAuthor, CoAuthorsCreditFull))
+}
+
+func TestSplitCredit(t *testing.T) {
+	assert.Equal(t, []int{4, 3, 3}, splitCredit(10, 3))
+	assert.Equal(t, []int{1, 0}, splitCredit(1, 2))
+	assert.Equal(t, []int{7}, splitCredit(7, 1))
+	assert.Equal(t, []int{0, 0}, splitCredit(0, 2))
+}
@@ -3,6 +3,7 @@
 package leaves
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -21,10 +22,12 @@ import (
 	"gopkg.in/src-d/hercules.v9/internal/pb"
 	items "gopkg.in/src-d/hercules.v9/internal/plumbing"
 	uast_items "gopkg.in/src-d/hercules.v9/internal/plumbing/uast"
+	"gopkg.in/src-d/hercules.v9/internal/yaml"
 	"gopkg.in/vmarkovtsev/BiDiSentiment.v1"
 )
 
 // CommentSentimentAnalysis measures comment sentiment through time.
+// It is a ResultMergeablePipelineItem.
 type CommentSentimentAnalysis struct {
 	core.NoopMerger
 	core.OneShotMergeProcessor
@@ -122,14 +125,17 @@ func (sent *CommentSentimentAnalysis) Description() string {
 
 // Configure sets the properties previously published by ListConfigurationOptions().
 func (sent *CommentSentimentAnalysis) Configure(facts map[string]interface{}) error {
-	if val, exists := facts[ConfigCommentSentimentGap]; exists {
-		sent.Gap = val.(float32)
+	if val, exists := facts[ConfigCommentSentimentGap].(float32); exists {
+		sent.Gap = val
 	}
-	if val, exists := facts[ConfigCommentSentimentMinLength]; exists {
-		sent.MinCommentLength = val.(int)
+	if val, exists := facts[ConfigCommentSentimentMinLength].(int); exists {
+		sent.MinCommentLength = val
 	}
 	sent.validate()
-	sent.commitsByDay = facts[items.FactCommitsByDay].(map[int][]plumbing.Hash)
+	// the fact is missing when the results are merged, it is checked in Initialize()
+	if val, exists := facts[items.FactCommitsByDay].(map[int][]plumbing.Hash); exists {
+		sent.commitsByDay = val
+	}
 	return nil
 }
 
@@ -149,6 +155,9 @@ func (sent *CommentSentimentAnalysis) validate() {
 // Initialize resets the temporary caches and prepares this PipelineItem for a series of Consume()
 // calls. The repository which is going to be analysed is supplied as an argument.
 func (sent *CommentSentimentAnalysis) Initialize(repository *git.Repository) error {
+	if sent.commitsByDay == nil {
+		return fmt.Errorf("%s needs %s to initialize", sent.Name(), items.FactCommitsByDay)
+	}
 	sent.commentsByDay = map[int][]string{}
 	sent.xpather = &uast_items.ChangesXPather{XPath: "//uast:Comment"}
 	sent.validate()
@@ -246,12 +255,21 @@ func (sent *CommentSentimentAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(sent, n)
 }
 
+// Sequential returns true because all the branches append to the same commentsByDay.
+func (sent *CommentSentimentAnalysis) Sequential() bool {
+	return true
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (sent *CommentSentimentAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (sent *CommentSentimentAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	sentimentResult := result.(CommentSentimentResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return sent.serializeBinary(&sentimentResult, writer)
+	case core.JSONFormat:
+		return sent.serializeJSON(&sentimentResult, writer)
 	}
 	sent.serializeText(&sentimentResult, writer)
 	return nil
@@ -275,6 +293,29 @@ func (sent *CommentSentimentAnalysis) serializeText(result *CommentSentimentResu
 	}
 }
 
+// sentimentJSON is the JSON representation of the comment sentiment in a single day.
+type sentimentJSON struct {
+	Emotion  float32  `json:"emotion"`
+	Commits  []string `json:"commits"`
+	Comments []string `json:"comments"`
+}
+
+func (sent *CommentSentimentAnalysis) serializeJSON(
+	result *CommentSentimentResult, writer io.Writer) error {
+	days := map[int]sentimentJSON{}
+	for day, emotion := range result.EmotionsByDay {
+		commits := result.commitsByDay[day]
+		hashes := make([]string, len(commits))
+		for i, hash := range commits {
+			hashes[i] = hash.String()
+		}
+		days[day] = sentimentJSON{
+			Emotion: emotion, Commits: hashes, Comments: result.CommentsByDay[day],
+		}
+	}
+	return json.NewEncoder(writer).Encode(days)
+}
+
 func (sent *CommentSentimentAnalysis) serializeBinary(
 	result *CommentSentimentResult, writer io.Writer) error {
 	message := pb.CommentSentimentResults{
@@ -299,6 +340,136 @@ func (sent *CommentSentimentAnalysis) serializeBinary(
 	return nil
 }
 
+// Deserialize converts the specified protobuf bytes to CommentSentimentResult.
+func (sent *CommentSentimentAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
+	message := pb.CommentSentimentResults{}
+	err := proto.Unmarshal(pbmessage, &message)
+	if err != nil {
+		return nil, err
+	}
+	result := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{},
+		CommentsByDay: map[int][]string{},
+		commitsByDay:  map[int][]plumbing.Hash{},
+	}
+	for key, val := range message.SentimentByDay {
+		day := int(key)
+		result.EmotionsByDay[day] = val.Value
+		result.CommentsByDay[day] = val.Comments
+		commits := make([]plumbing.Hash, len(val.Commits))
+		for i, hash := range val.Commits {
+			commits[i] = plumbing.NewHash(hash)
+		}
+		result.commitsByDay[day] = commits
+	}
+	return result, nil
+}
+
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to
+// CommentSentimentResult. The YAML output joins the comments with "|", so they are split back
+// by the same separator.
+func (sent *CommentSentimentAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	days := map[int]sentimentJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &days); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var parsed map[int][]sentimentYAMLField
+		if err := yaml.Unmarshal(text, &parsed); err != nil {
+			return nil, err
+		}
+		for day, fields := range parsed {
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("day %d: 3 values expected, got %d", day, len(fields))
+			}
+			var comments []string
+			if fields[2].comments != "" {
+				comments = strings.Split(fields[2].comments, "|")
+			}
+			days[day] = sentimentJSON{
+				Emotion: fields[0].emotion, Commits: fields[1].commits, Comments: comments,
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	result := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{},
+		CommentsByDay: map[int][]string{},
+		commitsByDay:  map[int][]plumbing.Hash{},
+	}
+	for day, val := range days {
+		result.EmotionsByDay[day] = val.Emotion
+		result.CommentsByDay[day] = val.Comments
+		commits := make([]plumbing.Hash, len(val.Commits))
+		for i, hash := range val.Commits {
+			commits[i] = plumbing.NewHash(hash)
+		}
+		result.commitsByDay[day] = commits
+	}
+	return result, nil
+}
+
+// sentimentYAMLField is a single value in the YAML row of the day: [emotion, [commits], "comments"].
+type sentimentYAMLField struct {
+	emotion  float32
+	commits  []string
+	comments string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (field *sentimentYAMLField) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&field.commits); err == nil {
+		return nil
+	}
+	if err := unmarshal(&field.emotion); err == nil {
+		return nil
+	}
+	return unmarshal(&field.comments)
+}
+
+// MergeResults combines two CommentSentimentResult-s together. The days are aligned by the
+// absolute time using CommonAnalysisResult.BeginTime the same way as in BurndownAnalysis.
+// The sentiment of the same day is the average weighted by the number of comments.
+func (sent *CommentSentimentAnalysis) MergeResults(
+	r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
+	csr1 := r1.(CommentSentimentResult)
+	csr2 := r2.(CommentSentimentResult)
+	commonMerged := c1.Copy()
+	commonMerged.Merge(c2)
+	merged := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{},
+		CommentsByDay: map[int][]string{},
+		commitsByDay:  map[int][]plumbing.Hash{},
+	}
+	addDays := func(result CommentSentimentResult, common *core.CommonAnalysisResult) {
+		offset := roundTime(common.BeginTime, false) - roundTime(commonMerged.BeginTime, false)
+		for day, emotion := range result.EmotionsByDay {
+			comments := result.CommentsByDay[day]
+			day += offset
+			existing := merged.CommentsByDay[day]
+			if total := len(existing) + len(comments); total > 0 {
+				merged.EmotionsByDay[day] = (merged.EmotionsByDay[day]*float32(len(existing)) +
+					emotion*float32(len(comments))) / float32(total)
+			} else {
+				merged.EmotionsByDay[day] = emotion
+			}
+			merged.CommentsByDay[day] = append(existing, comments...)
+		}
+		for day, commits := range result.commitsByDay {
+			day += offset
+			existing := merged.commitsByDay[day]
+			merged.commitsByDay[day] = append(existing, commits...)
+		}
+	}
+	addDays(csr1, c1)
+	addDays(csr2, c2)
+	return merged
+}
+
 func (sent *CommentSentimentAnalysis) mergeComments(extracted []nodes.Node) []string {
 	var mergedComments []string
 	lines := map[int][]nodes.Node{}
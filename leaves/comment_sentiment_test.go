@@ -73,6 +73,10 @@ func TestCommentSentimentConfigure(t *testing.T) {
 	sent.Configure(facts)
 	assert.Equal(t, sent.Gap, DefaultCommentSentimentGap)
 	assert.Equal(t, sent.MinCommentLength, DefaultCommentSentimentCommentMinLength)
+	delete(facts, items.FactCommitsByDay)
+	sent = CommentSentimentAnalysis{}
+	assert.Nil(t, sent.Configure(facts))
+	assert.NotNil(t, sent.Initialize(test.Repository))
 }
 
 func TestCommentSentimentRegistration(t *testing.T) {
@@ -110,10 +114,26 @@ func TestCommentSentimentSerializeText(t *testing.T) {
 	result.CommentsByDay[9] = []string{"test", "hello"}
 	result.commitsByDay[9] = []plumbing.Hash{plumbing.NewHash("4f7c7a154638a0f2468276c56188d90c9cef0dfc")}
 	buffer := &bytes.Buffer{}
-	sent.Serialize(result, false, buffer)
+	sent.Serialize(result, core.YAMLFormat, buffer)
 	assert.Equal(t, buffer.String(), "  9: [0.5000, [4f7c7a154638a0f2468276c56188d90c9cef0dfc], \"test|hello\"]\n")
 }
 
+func TestCommentSentimentSerializeJSON(t *testing.T) {
+	sent := fixtureCommentSentiment()
+	result := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{},
+		CommentsByDay: map[int][]string{},
+		commitsByDay:  map[int][]plumbing.Hash{},
+	}
+	result.EmotionsByDay[9] = 0.5
+	result.CommentsByDay[9] = []string{"test", "hello"}
+	result.commitsByDay[9] = []plumbing.Hash{plumbing.NewHash("4f7c7a154638a0f2468276c56188d90c9cef0dfc")}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, sent.Serialize(result, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{"9": {"emotion": 0.5, "commits": ["4f7c7a154638a0f2468276c56188d90c9cef0dfc"],
+"comments": ["test", "hello"]}}`, buffer.String())
+}
+
 func TestCommentSentimentSerializeBinary(t *testing.T) {
 	sent := fixtureCommentSentiment()
 	result := CommentSentimentResult{
@@ -125,7 +145,7 @@ func TestCommentSentimentSerializeBinary(t *testing.T) {
 	result.CommentsByDay[9] = []string{"test", "hello"}
 	result.commitsByDay[9] = []plumbing.Hash{plumbing.NewHash("4f7c7a154638a0f2468276c56188d90c9cef0dfc")}
 	buffer := &bytes.Buffer{}
-	sent.Serialize(result, true, buffer)
+	sent.Serialize(result, core.ProtobufFormat, buffer)
 	msg := pb.CommentSentimentResults{}
 	proto.Unmarshal(buffer.Bytes(), &msg)
 	assert.Len(t, msg.SentimentByDay, 1)
@@ -134,6 +154,57 @@ func TestCommentSentimentSerializeBinary(t *testing.T) {
 	assert.Equal(t, msg.SentimentByDay[int32(9)].Value, float32(0.5))
 }
 
+func TestCommentSentimentDeserialize(t *testing.T) {
+	sent := fixtureCommentSentiment()
+	result := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{9: 0.5},
+		CommentsByDay: map[int][]string{9: {"test", "hello"}},
+		commitsByDay: map[int][]plumbing.Hash{
+			9: {plumbing.NewHash("4f7c7a154638a0f2468276c56188d90c9cef0dfc")}},
+	}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, sent.Serialize(result, core.ProtobufFormat, buffer))
+	rawResult2, err := sent.Deserialize(buffer.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, result, rawResult2)
+	_, err = sent.Deserialize([]byte("garbage"))
+	assert.NotNil(t, err)
+}
+
+func TestCommentSentimentMergeResults(t *testing.T) {
+	sent := fixtureCommentSentiment()
+	hash1 := plumbing.NewHash("4f7c7a154638a0f2468276c56188d90c9cef0dfc")
+	hash2 := plumbing.NewHash("cce947b98a050c6d356bc6ba95030254914027b1")
+	r1 := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{0: 0.2, 2: 0.9},
+		CommentsByDay: map[int][]string{0: {"one"}, 2: {"two", "three", "four"}},
+		commitsByDay:  map[int][]plumbing.Hash{0: {hash1}, 1: {hash1}, 2: {hash1}},
+	}
+	r2 := CommentSentimentResult{
+		EmotionsByDay: map[int]float32{0: 0.1},
+		CommentsByDay: map[int][]string{0: {"five"}},
+		commitsByDay:  map[int][]plumbing.Hash{0: {hash2}},
+	}
+	c1 := &core.CommonAnalysisResult{BeginTime: 1481500800, EndTime: 1481760000}
+	// two days later
+	c2 := &core.CommonAnalysisResult{BeginTime: 1481673600, EndTime: 1481760000}
+	merged := sent.MergeResults(r1, r2, c1, c2).(CommentSentimentResult)
+	assert.Len(t, merged.EmotionsByDay, 2)
+	assert.Equal(t, float32(0.2), merged.EmotionsByDay[0])
+	assert.InDelta(t, 0.7, merged.EmotionsByDay[2], 0.0001)
+	assert.Equal(t, []string{"one"}, merged.CommentsByDay[0])
+	assert.Equal(t, []string{"two", "three", "four", "five"}, merged.CommentsByDay[2])
+	assert.Equal(t, []plumbing.Hash{hash1, hash2}, merged.commitsByDay[2])
+	assert.Equal(t, []plumbing.Hash{hash1}, merged.commitsByDay[1])
+	// the source results are not modified
+	assert.Len(t, r1.CommentsByDay[2], 3)
+
+	// the second result started earlier
+	merged = sent.MergeResults(r2, r1, c2, c1).(CommentSentimentResult)
+	assert.InDelta(t, 0.7, merged.EmotionsByDay[2], 0.0001)
+	assert.Equal(t, []string{"five", "two", "three", "four"}, merged.CommentsByDay[2])
+}
+
 func TestCommentSentimentFinalize(t *testing.T) {
 	sent := fixtureCommentSentiment()
 	sent.commitsByDay = testSentimentCommits
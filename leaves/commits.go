@@ -1,8 +1,11 @@
 package leaves
 
 import (
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/gogo/protobuf/proto"
 	git "gopkg.in/src-d/go-git.v4"
@@ -15,7 +18,8 @@ import (
 	"gopkg.in/src-d/hercules.v9/internal/yaml"
 )
 
-// CommitsAnalysis extracts statistics for each commit
+// CommitsAnalysis extracts statistics for each commit.
+// It is a ResultMergeablePipelineItem.
 type CommitsAnalysis struct {
 	core.NoopMerger
 
@@ -23,6 +27,8 @@ type CommitsAnalysis struct {
 	commits []*CommitStat
 	// reversedPeopleDict references IdentityDetector.ReversedPeopleDict
 	reversedPeopleDict []string
+	// droppedBots are the identities whose commits are ignored
+	droppedBots map[int]bool
 }
 
 // CommitsResult is returned by CommitsAnalysis.Finalize() and carries the statistics
@@ -34,6 +40,16 @@ type CommitsResult struct {
 	reversedPeopleDict []string
 }
 
+// TagRepository sets the origin repository of the commits which do not have it yet.
+// The commits are modified in place.
+func (cr CommitsResult) TagRepository(repository string) {
+	for _, c := range cr.Commits {
+		if c.Repository == "" {
+			c.Repository = repository
+		}
+	}
+}
+
 // FileStat is the statistics for a file in a commit
 type FileStat struct {
 	Name     string
@@ -47,6 +63,9 @@ type CommitStat struct {
 	When   int64
 	Author int
 	Files  []FileStat
+	// Repository is the origin repository of the commit. It is set when the results
+	// from several repositories are loaded to be merged, see CommitsResult.TagRepository().
+	Repository string
 }
 
 // Name of this PipelineItem. Uniquely identifies the type, used for mapping keys, etc.
@@ -80,6 +99,9 @@ func (ca *CommitsAnalysis) Configure(facts map[string]interface{}) error {
 	if val, exists := facts[identity.FactIdentityDetectorReversedPeopleDict].([]string); exists {
 		ca.reversedPeopleDict = val
 	}
+	if val, exists := droppedBots(facts); exists {
+		ca.droppedBots = val
+	}
 	return nil
 }
 
@@ -110,6 +132,9 @@ func (ca *CommitsAnalysis) Consume(deps map[string]interface{}) (map[string]inte
 	}
 	commit := deps[core.DependencyCommit].(*object.Commit)
 	author := deps[identity.DependencyAuthor].(int)
+	if ca.droppedBots[author] {
+		return nil, nil
+	}
 	lineStats := deps[items.DependencyLineStats].(map[object.ChangeEntry]items.LineStats)
 	langs := deps[items.DependencyLanguages].(map[plumbing.Hash]string)
 	cs := CommitStat{
@@ -141,23 +166,200 @@ func (ca *CommitsAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(ca, n)
 }
 
+// Sequential returns true so that the commits are recorded in the order of the execution plan.
+func (ca *CommitsAnalysis) Sequential() bool {
+	return true
+}
+
+// SaveState writes the statistics of the commits consumed so far.
+func (ca *CommitsAnalysis) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(ca.commits)
+}
+
+// LoadState restores the statistics written by SaveState().
+func (ca *CommitsAnalysis) LoadState(reader io.Reader) error {
+	var commits []*CommitStat
+	err := gob.NewDecoder(reader).Decode(&commits)
+	if err != nil {
+		return err
+	}
+	ca.commits = commits
+	return nil
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (ca *CommitsAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (ca *CommitsAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	commitsResult := result.(CommitsResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return ca.serializeBinary(&commitsResult, writer)
+	case core.JSONFormat:
+		return ca.serializeJSON(&commitsResult, writer)
 	}
 	ca.serializeText(&commitsResult, writer)
 	return nil
 }
 
+// Deserialize converts the specified protobuf bytes to CommitsResult.
+func (ca *CommitsAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
+	message := pb.CommitsAnalysisResults{}
+	err := proto.Unmarshal(pbmessage, &message)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]*CommitStat, len(message.Commits))
+	for i, c := range message.Commits {
+		files := make([]FileStat, len(c.Files))
+		for j, f := range c.Files {
+			files[j] = FileStat{Name: f.Name, Language: f.Language}
+			if f.Stats != nil {
+				files[j].LineStats = items.LineStats{
+					Added:   int(f.Stats.Added),
+					Removed: int(f.Stats.Removed),
+					Changed: int(f.Stats.Changed),
+				}
+			}
+		}
+		commits[i] = &CommitStat{
+			Hash:       c.Hash,
+			When:       c.WhenUnixTime,
+			Author:     int(c.Author),
+			Files:      files,
+			Repository: c.Repository,
+		}
+	}
+	result := CommitsResult{
+		Commits:            commits,
+		reversedPeopleDict: message.AuthorIndex,
+	}
+	return result, nil
+}
+
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to CommitsResult.
+func (ca *CommitsAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	message := commitsJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &message); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var err error
+		if message, err = parseCommitsYAML(text); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	commits := make([]*CommitStat, len(message.Commits))
+	for i, c := range message.Commits {
+		files := make([]FileStat, len(c.Files))
+		for j, f := range c.Files {
+			files[j] = FileStat{Name: f.Name, Language: f.Language, LineStats: f.Stat.lineStats()}
+		}
+		commits[i] = &CommitStat{
+			Hash:       c.Hash,
+			When:       c.When,
+			Author:     c.Author,
+			Files:      files,
+			Repository: c.Repository,
+		}
+	}
+	result := CommitsResult{
+		Commits:            commits,
+		reversedPeopleDict: message.People,
+	}
+	return result, nil
+}
+
+func parseCommitsYAML(text []byte) (commitsJSON, error) {
+	var parsed struct {
+		Commits []struct {
+			Hash       string `yaml:"hash"`
+			When       int64  `yaml:"when"`
+			Author     int    `yaml:"author"`
+			Repository string `yaml:"repository"`
+			Files      []struct {
+				Name     string `yaml:"name"`
+				Language string `yaml:"language"`
+				// Stat is [added, changed, removed]
+				Stat []int `yaml:"stat"`
+			} `yaml:"files"`
+		} `yaml:"commits"`
+		People []string `yaml:"people"`
+	}
+	message := commitsJSON{}
+	if err := yaml.Unmarshal(text, &parsed); err != nil {
+		return message, err
+	}
+	message.Commits = make([]commitJSON, len(parsed.Commits))
+	message.People = parsed.People
+	for i, c := range parsed.Commits {
+		files := make([]fileStatJSON, len(c.Files))
+		for j, f := range c.Files {
+			if len(f.Stat) != 3 {
+				return message, fmt.Errorf("%s: %s: 3 values expected, got %d", c.Hash, f.Name, len(f.Stat))
+			}
+			files[j] = fileStatJSON{
+				Name:     f.Name,
+				Language: f.Language,
+				Stat:     lineStatsJSON{Added: f.Stat[0], Changed: f.Stat[1], Removed: f.Stat[2]},
+			}
+		}
+		message.Commits[i] = commitJSON{
+			Hash: c.Hash, When: c.When, Author: c.Author, Files: files, Repository: c.Repository,
+		}
+	}
+	return message, nil
+}
+
+// MergeResults combines two CommitsResult-s together. The author indices are remapped to
+// the joint author index, and the commits which do not have the origin repository yet are
+// tagged with the repository from the corresponding CommonAnalysisResult.
+// The merged commits are sorted by time.
+func (ca *CommitsAnalysis) MergeResults(r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
+	cr1 := r1.(CommitsResult)
+	cr2 := r2.(CommitsResult)
+	merged := CommitsResult{}
+	var people map[string][3]int
+	people, merged.reversedPeopleDict = identity.Detector{}.MergeReversedDicts(
+		cr1.reversedPeopleDict, cr2.reversedPeopleDict)
+	merged.Commits = make([]*CommitStat, 0, len(cr1.Commits)+len(cr2.Commits))
+	addCommits := func(commits []*CommitStat, reversedPeopleDict []string,
+		common *core.CommonAnalysisResult) {
+		for _, c := range commits {
+			clone := *c
+			if clone.Author >= 0 && clone.Author < len(reversedPeopleDict) {
+				clone.Author = people[reversedPeopleDict[clone.Author]][0]
+			} else {
+				clone.Author = identity.AuthorMissing
+			}
+			if clone.Repository == "" && common != nil {
+				clone.Repository = common.Repository
+			}
+			merged.Commits = append(merged.Commits, &clone)
+		}
+	}
+	addCommits(cr1.Commits, cr1.reversedPeopleDict, c1)
+	addCommits(cr2.Commits, cr2.reversedPeopleDict, c2)
+	sort.SliceStable(merged.Commits, func(i, j int) bool {
+		return merged.Commits[i].When < merged.Commits[j].When
+	})
+	return merged
+}
+
 func (ca *CommitsAnalysis) serializeText(result *CommitsResult, writer io.Writer) {
 	fmt.Fprintln(writer, "  commits:")
 	for _, c := range result.Commits {
 		fmt.Fprintf(writer, "    - hash: %s\n", c.Hash)
 		fmt.Fprintf(writer, "      when: %d\n", c.When)
 		fmt.Fprintf(writer, "      author: %d\n", c.Author)
+		if c.Repository != "" {
+			fmt.Fprintf(writer, "      repository: %s\n", yaml.SafeString(c.Repository))
+		}
 		fmt.Fprintf(writer, "      files:\n")
 		for _, f := range c.Files {
 			fmt.Fprintf(writer, "       - name: %s\n", f.Name)
@@ -171,6 +373,44 @@ func (ca *CommitsAnalysis) serializeText(result *CommitsResult, writer io.Writer
 	}
 }
 
+// commitJSON is the JSON representation of CommitStat.
+type commitJSON struct {
+	Hash       string         `json:"hash"`
+	When       int64          `json:"when"`
+	Author     int            `json:"author"`
+	Files      []fileStatJSON `json:"files"`
+	Repository string         `json:"repository,omitempty"`
+}
+
+// fileStatJSON is the JSON representation of FileStat.
+type fileStatJSON struct {
+	Name     string        `json:"name"`
+	Language string        `json:"language"`
+	Stat     lineStatsJSON `json:"stat"`
+}
+
+func (ca *CommitsAnalysis) serializeJSON(result *CommitsResult, writer io.Writer) error {
+	commits := make([]commitJSON, len(result.Commits))
+	for i, c := range result.Commits {
+		files := make([]fileStatJSON, len(c.Files))
+		for j, f := range c.Files {
+			files[j] = fileStatJSON{
+				Name: f.Name, Language: f.Language, Stat: newLineStatsJSON(f.LineStats),
+			}
+		}
+		commits[i] = commitJSON{
+			Hash: c.Hash, When: c.When, Author: c.Author, Files: files, Repository: c.Repository,
+		}
+	}
+	return json.NewEncoder(writer).Encode(commitsJSON{commits, result.reversedPeopleDict})
+}
+
+// commitsJSON mirrors the YAML layout of CommitsResult.
+type commitsJSON struct {
+	Commits []commitJSON `json:"commits"`
+	People  []string     `json:"people"`
+}
+
 func (ca *CommitsAnalysis) serializeBinary(result *CommitsResult, writer io.Writer) error {
 	message := pb.CommitsAnalysisResults{}
 	message.AuthorIndex = result.reversedPeopleDict
@@ -194,6 +434,7 @@ func (ca *CommitsAnalysis) serializeBinary(result *CommitsResult, writer io.Writ
 			WhenUnixTime: c.When,
 			Author:       int32(c.Author),
 			Files:        files,
+			Repository:   c.Repository,
 		}
 	}
 	serialized, err := proto.Marshal(&message)
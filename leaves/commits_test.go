@@ -184,6 +184,29 @@ func TestCommitsConsume(t *testing.T) {
 	assert.Equal(t, 0, c.Files[2].Changed)
 }
 
+func TestCommitsConsumeBots(t *testing.T) {
+	ca := CommitsAnalysis{}
+	assert.Nil(t, ca.Configure(map[string]interface{}{
+		identity.FactIdentityDetectorBots:   map[int]bool{1: true},
+		identity.ConfigIdentityDetectorBots: identity.BotsDrop,
+	}))
+	assert.Nil(t, ca.Initialize(test.Repository))
+	deps := map[string]interface{}{
+		core.DependencyIsMerge:    false,
+		core.DependencyCommit:     &object.Commit{},
+		identity.DependencyAuthor: 1,
+		items.DependencyLanguages: map[plumbing.Hash]string{},
+		items.DependencyLineStats: map[object.ChangeEntry]items.LineStats{},
+	}
+	_, err := ca.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, ca.commits, 0)
+	deps[identity.DependencyAuthor] = 0
+	_, err = ca.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, ca.commits, 1)
+}
+
 func fixtureCommits() *CommitsAnalysis {
 	ca := CommitsAnalysis{}
 	ca.Initialize(test.Repository)
@@ -243,11 +266,22 @@ func TestCommitsFinalize(t *testing.T) {
 	assert.Equal(t, x.reversedPeopleDict, ca.reversedPeopleDict)
 }
 
+func TestCommitsSaveLoadState(t *testing.T) {
+	ca := fixtureCommits()
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, ca.SaveState(buffer))
+	ca2 := &CommitsAnalysis{}
+	assert.Nil(t, ca2.Initialize(test.Repository))
+	assert.Nil(t, ca2.LoadState(bytes.NewBuffer(buffer.Bytes())))
+	assert.Equal(t, ca.commits, ca2.commits)
+	assert.NotNil(t, ca2.LoadState(bytes.NewBuffer([]byte("garbage"))))
+}
+
 func TestCommitsSerialize(t *testing.T) {
 	ca := fixtureCommits()
 	res := ca.Finalize().(CommitsResult)
 	buffer := &bytes.Buffer{}
-	err := ca.Serialize(res, false, buffer)
+	err := ca.Serialize(res, core.YAMLFormat, buffer)
 	assert.Nil(t, err)
 	assert.Equal(t, `  commits:
     - hash: cce947b98a050c6d356bc6ba95030254914027b1
@@ -273,7 +307,7 @@ func TestCommitsSerialize(t *testing.T) {
 `, buffer.String())
 
 	buffer = &bytes.Buffer{}
-	err = ca.Serialize(res, true, buffer)
+	err = ca.Serialize(res, core.ProtobufFormat, buffer)
 	assert.Nil(t, err)
 	msg := pb.CommitsAnalysisResults{}
 	assert.Nil(t, proto.Unmarshal(buffer.Bytes(), &msg))
@@ -300,3 +334,126 @@ func TestCommitsSerialize(t *testing.T) {
 		Stats:    &pb.LineStats{Added: 1, Removed: 0, Changed: 0},
 		Language: "Go"})
 }
+
+func TestCommitsSerializeJSON(t *testing.T) {
+	ca := fixtureCommits()
+	res := ca.Finalize().(CommitsResult)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, ca.Serialize(res, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{
+  "commits": [
+    {"hash": "cce947b98a050c6d356bc6ba95030254914027b1", "when": 1481563829, "author": 0,
+     "files": [
+       {"name": ".travis.yml", "language": "Yaml",
+        "stat": {"added": 12, "removed": 0, "changed": 0}},
+       {"name": "analyser.go", "language": "Go",
+        "stat": {"added": 628, "removed": 9, "changed": 67}}]},
+    {"hash": "c29112dbd697ad9b401333b80c18a63951bc18d9", "when": 1481563999, "author": 1,
+     "files": [
+       {"name": "cmd/hercules/main.go", "language": "Go",
+        "stat": {"added": 1, "removed": 0, "changed": 0}}]}
+  ],
+  "people": ["one@srcd", "two@srcd"]
+}`, buffer.String())
+}
+
+func TestCommitsDeserialize(t *testing.T) {
+	ca := fixtureCommits()
+	res := ca.Finalize().(CommitsResult)
+	res.Commits[1].Repository = "hercules"
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, ca.Serialize(res, core.ProtobufFormat, buffer))
+	rawres2, err := ca.Deserialize(buffer.Bytes())
+	assert.Nil(t, err)
+	res2 := rawres2.(CommitsResult)
+	assert.Equal(t, res, res2)
+	_, err = ca.Deserialize([]byte("garbage"))
+	assert.NotNil(t, err)
+}
+
+func TestCommitsDeserializeText(t *testing.T) {
+	ca := fixtureCommits()
+	res := ca.Finalize().(CommitsResult)
+	res.Commits[1].Repository = "hercules"
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, ca.Serialize(res, format, buffer))
+		rawres2, err := ca.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, res, rawres2, format)
+	}
+	_, err := ca.DeserializeText([]byte("garbage"), core.JSONFormat)
+	assert.NotNil(t, err)
+}
+
+func TestCommitsMergeResults(t *testing.T) {
+	ca := fixtureCommits()
+	r1 := ca.Finalize().(CommitsResult)
+	r2 := CommitsResult{
+		Commits: []*CommitStat{
+			{Hash: "1111111111111111111111111111111111111111", When: 1481563900, Author: 0},
+			{Hash: "2222222222222222222222222222222222222222", When: 1481563800, Author: 1},
+			{Hash: "3333333333333333333333333333333333333333", When: 1481564000,
+				Author: identity.AuthorMissing},
+		},
+		reversedPeopleDict: []string{"three@srcd", "one@srcd"},
+	}
+	c1 := &core.CommonAnalysisResult{Repository: "hercules"}
+	c2 := &core.CommonAnalysisResult{Repository: "go-git"}
+	merged := ca.MergeResults(r1, r2, c1, c2).(CommitsResult)
+	assert.Equal(t, []string{"one@srcd", "two@srcd", "three@srcd"}, merged.reversedPeopleDict)
+	assert.Len(t, merged.Commits, 5)
+	type summary struct {
+		Hash       string
+		Author     int
+		Repository string
+	}
+	var summaries []summary
+	for _, c := range merged.Commits {
+		summaries = append(summaries, summary{c.Hash[:4], c.Author, c.Repository})
+	}
+	assert.Equal(t, []summary{
+		{"2222", 0, "go-git"},
+		{"cce9", 0, "hercules"},
+		{"1111", 2, "go-git"},
+		{"c291", 1, "hercules"},
+		{"3333", identity.AuthorMissing, "go-git"},
+	}, summaries)
+	assert.Len(t, merged.Commits[1].Files, 2)
+	// the source results are not modified
+	assert.Equal(t, "", r1.Commits[0].Repository)
+	assert.Equal(t, 1, r2.Commits[1].Author)
+
+	// the tags from the previous merges are preserved
+	c3 := &core.CommonAnalysisResult{Repository: "gitbase"}
+	merged = ca.MergeResults(merged, r1, c1, c3).(CommitsResult)
+	assert.Len(t, merged.Commits, 7)
+	assert.Equal(t, "go-git", merged.Commits[0].Repository)
+	assert.Equal(t, "hercules", merged.Commits[1].Repository)
+	assert.Equal(t, "gitbase", merged.Commits[2].Repository)
+}
+
+func TestCommitsTagRepository(t *testing.T) {
+	r := CommitsResult{Commits: []*CommitStat{
+		{Hash: "1111111111111111111111111111111111111111"},
+		{Hash: "2222222222222222222222222222222222222222", Repository: "go-git"},
+	}}
+	r.TagRepository("hercules")
+	assert.Equal(t, "hercules", r.Commits[0].Repository)
+	assert.Equal(t, "go-git", r.Commits[1].Repository)
+
+	// the results loaded one by one keep their own origins in the joint merge
+	ca := fixtureCommits()
+	r1 := ca.Finalize().(CommitsResult)
+	r1.TagRepository("hercules")
+	r2 := CommitsResult{Commits: []*CommitStat{
+		{Hash: "3333333333333333333333333333333333333333", When: 1481564000},
+	}}
+	r2.TagRepository("gitbase")
+	c12 := &core.CommonAnalysisResult{Repository: "hercules & go-git"}
+	c3 := &core.CommonAnalysisResult{Repository: "gitbase"}
+	merged := ca.MergeResults(r1, r2, c12, c3).(CommitsResult)
+	assert.Equal(t, "hercules", merged.Commits[0].Repository)
+	assert.Equal(t, "hercules", merged.Commits[1].Repository)
+	assert.Equal(t, "gitbase", merged.Commits[2].Repository)
+}
@@ -1,6 +1,8 @@
 package leaves
 
 import (
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
 	"gopkg.in/src-d/hercules.v9/internal/core"
@@ -25,6 +28,9 @@ type CouplesAnalysis struct {
 	core.OneShotMergeProcessor
 	// PeopleNumber is the number of developers for which to build the matrix. 0 disables this analysis.
 	PeopleNumber int
+	// CoAuthorsCredit defines how the co-authors are credited: CoAuthorsCreditAuthor or
+	// CoAuthorsCreditFull. The commit counts cannot be split.
+	CoAuthorsCredit string
 
 	// people store how many times every developer committed to every file.
 	people []map[string]int
@@ -38,6 +44,10 @@ type CouplesAnalysis struct {
 	lastCommit *object.Commit
 	// reversedPeopleDict references IdentityDetector.ReversedPeopleDict
 	reversedPeopleDict []string
+	// droppedBots are the identities whose commits are ignored
+	droppedBots map[int]bool
+	// repository is used to load the last consumed commit in LoadState().
+	repository *git.Repository
 }
 
 // CouplesResult is returned by CouplesAnalysis.Finalize() and carries couples matrices from
@@ -65,6 +75,9 @@ const (
 	// CouplesMaximumMeaningfulContextSize is the threshold on the number of files in a commit to
 	// consider them as grouped together.
 	CouplesMaximumMeaningfulContextSize = 1000
+
+	// ConfigCouplesCoAuthorsCredit is the name of the option to set CouplesAnalysis.CoAuthorsCredit.
+	ConfigCouplesCoAuthorsCredit = "Couples.CoAuthorsCredit"
 )
 
 type rename struct {
@@ -88,21 +101,41 @@ func (couples *CouplesAnalysis) Provides() []string {
 // Each requested entity will be inserted into `deps` of Consume(). In turn, those
 // entities are Provides() upstream.
 func (couples *CouplesAnalysis) Requires() []string {
-	arr := [...]string{identity.DependencyAuthor, items.DependencyTreeChanges}
+	arr := [...]string{
+		identity.DependencyAuthor, items.DependencyTreeChanges, identity.DependencyAuthors}
 	return arr[:]
 }
 
 // ListConfigurationOptions returns the list of changeable public properties of this PipelineItem.
 func (couples *CouplesAnalysis) ListConfigurationOptions() []core.ConfigurationOption {
-	return []core.ConfigurationOption{}
+	options := [...]core.ConfigurationOption{{
+		Name: ConfigCouplesCoAuthorsCredit,
+		Description: "How to credit the co-authors detected with --co-authors: \"author\" ignores " +
+			"them, \"full\" counts the commit for each. Not set means \"author\", or \"full\" " +
+			"with --signatures both.",
+		Flag:    "couples-co-authors",
+		Type:    core.StringConfigurationOption,
+		Default: ""}}
+	return options[:]
 }
 
 // Configure sets the properties previously published by ListConfigurationOptions().
 func (couples *CouplesAnalysis) Configure(facts map[string]interface{}) error {
+	if val, exists := facts[ConfigCouplesCoAuthorsCredit].(string); exists {
+		credit, err := checkCoAuthorsCredit(couples.Name(), val, CoAuthorsCreditFull)
+		if err != nil {
+			return err
+		}
+		couples.CoAuthorsCredit = credit
+	}
+	couples.CoAuthorsCredit = signaturesCredit(facts, couples.CoAuthorsCredit, CoAuthorsCreditFull)
 	if val, exists := facts[identity.FactIdentityDetectorPeopleCount].(int); exists {
 		couples.PeopleNumber = val
 		couples.reversedPeopleDict = facts[identity.FactIdentityDetectorReversedPeopleDict].([]string)
 	}
+	if val, exists := droppedBots(facts); exists {
+		couples.droppedBots = val
+	}
 	return nil
 }
 
@@ -121,6 +154,7 @@ func (couples *CouplesAnalysis) Description() string {
 // Initialize resets the temporary caches and prepares this PipelineItem for a series of Consume()
 // calls. The repository which is going to be analysed is supplied as an argument.
 func (couples *CouplesAnalysis) Initialize(repository *git.Repository) error {
+	couples.repository = repository
 	couples.people = make([]map[string]int, couples.PeopleNumber+1)
 	for i := range couples.people {
 		couples.people[i] = map[string]int{}
@@ -141,12 +175,27 @@ func (couples *CouplesAnalysis) Consume(deps map[string]interface{}) (map[string
 	firstMerge := couples.ShouldConsumeCommit(deps)
 	mergeMode := deps[core.DependencyIsMerge].(bool)
 	couples.lastCommit = deps[core.DependencyCommit].(*object.Commit)
-	author := deps[identity.DependencyAuthor].(int)
-	if author == identity.AuthorMissing {
-		author = couples.PeopleNumber
+	// the renames must be tracked even if the commit is ignored
+	dropped := couples.droppedBots[deps[identity.DependencyAuthor].(int)]
+	var authors []int
+	if !dropped {
+		for _, author := range withoutBots(
+			creditedAuthors(deps, couples.CoAuthorsCredit), couples.droppedBots) {
+			if author == identity.AuthorMissing {
+				author = couples.PeopleNumber
+			}
+			authors = append(authors, author)
+		}
 	}
 	if firstMerge {
-		couples.peopleCommits[author]++
+		for _, author := range authors {
+			couples.peopleCommits[author]++
+		}
+	}
+	touch := func(name string) {
+		for _, author := range authors {
+			couples.people[author][name]++
+		}
 	}
 	treeDiff := deps[items.DependencyTreeChanges].(object.Changes)
 	context := make([]string, 0, len(treeDiff))
@@ -161,11 +210,11 @@ func (couples *CouplesAnalysis) Consume(deps map[string]interface{}) (map[string
 		case merkletrie.Insert:
 			if !mergeMode || couples.files[toName] == nil {
 				context = append(context, toName)
-				couples.people[author][toName]++
+				touch(toName)
 			}
 		case merkletrie.Delete:
 			if !mergeMode {
-				couples.people[author][fromName]++
+				touch(fromName)
 			}
 		case merkletrie.Modify:
 			if fromName != toName {
@@ -175,11 +224,11 @@ func (couples *CouplesAnalysis) Consume(deps map[string]interface{}) (map[string
 			}
 			if !mergeMode || couples.files[toName] == nil {
 				context = append(context, toName)
-				couples.people[author][toName]++
+				touch(toName)
 			}
 		}
 	}
-	if len(context) <= CouplesMaximumMeaningfulContextSize {
+	if !dropped && len(context) <= CouplesMaximumMeaningfulContextSize {
 		for _, file := range context {
 			for _, otherFile := range context {
 				lane, exists := couples.files[file]
@@ -268,12 +317,86 @@ func (couples *CouplesAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkCopyPipelineItem(couples, n)
 }
 
+// Sequential returns true because the copied clones reference the same co-occurrence maps.
+func (couples *CouplesAnalysis) Sequential() bool {
+	return true
+}
+
+// couplesState is the serialized state of CouplesAnalysis, see SaveState().
+type couplesState struct {
+	People        []map[string]int
+	PeopleCommits []int
+	Files         map[string]map[string]int
+	Renames       []rename
+	LastCommit    plumbing.Hash
+}
+
+// SaveState writes the collected co-occurrences and renames.
+func (couples *CouplesAnalysis) SaveState(writer io.Writer) error {
+	state := couplesState{
+		People:        couples.people,
+		PeopleCommits: couples.peopleCommits,
+		Files:         couples.files,
+		Renames:       *couples.renames,
+	}
+	if couples.lastCommit != nil {
+		state.LastCommit = couples.lastCommit.Hash
+	}
+	return gob.NewEncoder(writer).Encode(state)
+}
+
+// LoadState restores the state written by SaveState(). The number of people may grow
+// since the state was saved, the unmatched identities are moved to the new last index.
+func (couples *CouplesAnalysis) LoadState(reader io.Reader) error {
+	state := couplesState{}
+	err := gob.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return err
+	}
+	if len(state.People) == 0 || len(state.People) > couples.PeopleNumber+1 ||
+		len(state.PeopleCommits) != len(state.People) {
+		return fmt.Errorf("the number of people does not match: %d > %d",
+			len(state.People)-1, couples.PeopleNumber)
+	}
+	people := make([]map[string]int, couples.PeopleNumber+1)
+	peopleCommits := make([]int, couples.PeopleNumber+1)
+	last := len(state.People) - 1
+	copy(people, state.People[:last])
+	copy(peopleCommits, state.PeopleCommits[:last])
+	people[couples.PeopleNumber] = state.People[last]
+	peopleCommits[couples.PeopleNumber] = state.PeopleCommits[last]
+	for i := range people {
+		if people[i] == nil {
+			people[i] = map[string]int{}
+		}
+	}
+	couples.people = people
+	couples.peopleCommits = peopleCommits
+	couples.files = state.Files
+	if couples.files == nil {
+		couples.files = map[string]map[string]int{}
+	}
+	couples.renames = &state.Renames
+	couples.lastCommit = nil
+	if state.LastCommit != plumbing.ZeroHash {
+		couples.lastCommit, err = couples.repository.CommitObject(state.LastCommit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (couples *CouplesAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (couples *CouplesAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	couplesResult := result.(CouplesResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return couples.serializeBinary(&couplesResult, writer)
+	case core.JSONFormat:
+		return couples.serializeJSON(&couplesResult, writer)
 	}
 	couples.serializeText(&couplesResult, writer)
 	return nil
@@ -323,6 +446,95 @@ func (couples *CouplesAnalysis) Deserialize(pbmessage []byte) (interface{}, erro
 	return result, nil
 }
 
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to CouplesResult.
+func (couples *CouplesAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	message := couplesJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &message); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var err error
+		if message, err = parseCouplesYAML(text); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	if len(message.FilesCoocc.Index) != len(message.FilesCoocc.Lines) {
+		return nil, fmt.Errorf("the number of files (%d) does not match the number of lines (%d)",
+			len(message.FilesCoocc.Index), len(message.FilesCoocc.Lines))
+	}
+	result := CouplesResult{
+		Files:              message.FilesCoocc.Index,
+		FilesLines:         message.FilesCoocc.Lines,
+		FilesMatrix:        message.FilesCoocc.Matrix,
+		PeopleFiles:        make([][]int, len(message.PeopleCoocc.Index)),
+		PeopleMatrix:       message.PeopleCoocc.Matrix,
+		reversedPeopleDict: message.PeopleCoocc.Index,
+	}
+	files := map[string]int{}
+	for i, file := range result.Files {
+		files[file] = i
+	}
+	people := map[string]int{}
+	for i, person := range result.reversedPeopleDict {
+		people[person] = i
+	}
+	for _, authorFiles := range message.PeopleCoocc.AuthorFiles {
+		person, exists := people[authorFiles.Author]
+		if !exists {
+			return nil, fmt.Errorf("author_files: unknown author %s", authorFiles.Author)
+		}
+		indexes := make([]int, len(authorFiles.Files))
+		for i, file := range authorFiles.Files {
+			if indexes[i], exists = files[file]; !exists {
+				return nil, fmt.Errorf("author_files: %s: unknown file %s", authorFiles.Author, file)
+			}
+		}
+		sort.Ints(indexes)
+		result.PeopleFiles[person] = indexes
+	}
+	return result, nil
+}
+
+// couplesYAML is the layout of the YAML output. author_files is the list of single-key mappings
+// from the author to the files.
+type couplesYAML struct {
+	FilesCoocc struct {
+		Index  []string        `yaml:"index"`
+		Lines  []int           `yaml:"lines"`
+		Matrix []map[int]int64 `yaml:"matrix"`
+	} `yaml:"files_coocc"`
+	PeopleCoocc struct {
+		Index       []string              `yaml:"index"`
+		Matrix      []map[int]int64       `yaml:"matrix"`
+		AuthorFiles []map[string][]string `yaml:"author_files"`
+	} `yaml:"people_coocc"`
+}
+
+func parseCouplesYAML(text []byte) (couplesJSON, error) {
+	parsed := couplesYAML{}
+	message := couplesJSON{}
+	if err := yaml.Unmarshal(text, &parsed); err != nil {
+		return message, err
+	}
+	message.FilesCoocc.Index = parsed.FilesCoocc.Index
+	message.FilesCoocc.Lines = parsed.FilesCoocc.Lines
+	message.FilesCoocc.Matrix = parsed.FilesCoocc.Matrix
+	message.PeopleCoocc.Index = parsed.PeopleCoocc.Index
+	message.PeopleCoocc.Matrix = parsed.PeopleCoocc.Matrix
+	for _, mapping := range parsed.PeopleCoocc.AuthorFiles {
+		for author, files := range mapping {
+			message.PeopleCoocc.AuthorFiles = append(
+				message.PeopleCoocc.AuthorFiles, authorFiles{Author: author, Files: files})
+		}
+	}
+	return message, nil
+}
+
 // MergeResults combines two CouplesAnalysis-s together.
 func (couples *CouplesAnalysis) MergeResults(r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
 	cr1 := r1.(CouplesResult)
@@ -477,6 +689,36 @@ func (couples *CouplesAnalysis) serializeText(result *CouplesResult, writer io.W
 	}
 }
 
+// couplesJSON mirrors the YAML layout of CouplesResult.
+type couplesJSON struct {
+	FilesCoocc struct {
+		Index  []string        `json:"index"`
+		Lines  []int           `json:"lines"`
+		Matrix []map[int]int64 `json:"matrix"`
+	} `json:"files_coocc"`
+	PeopleCoocc struct {
+		Index       []string        `json:"index"`
+		Matrix      []map[int]int64 `json:"matrix"`
+		AuthorFiles authorFilesList `json:"author_files"`
+	} `json:"people_coocc"`
+}
+
+func (couples *CouplesAnalysis) serializeJSON(result *CouplesResult, writer io.Writer) error {
+	message := couplesJSON{}
+	message.FilesCoocc.Index = result.Files
+	message.FilesCoocc.Lines = result.FilesLines
+	message.FilesCoocc.Matrix = result.FilesMatrix
+	message.PeopleCoocc.Index = result.reversedPeopleDict
+	message.PeopleCoocc.Matrix = result.PeopleMatrix
+	// sorted by number of files each author changed
+	message.PeopleCoocc.AuthorFiles = sortByNumberOfFiles(
+		result.PeopleFiles, result.reversedPeopleDict, result.Files)
+	for _, authorFiles := range message.PeopleCoocc.AuthorFiles {
+		sort.Strings(authorFiles.Files)
+	}
+	return json.NewEncoder(writer).Encode(message)
+}
+
 func sortByNumberOfFiles(
 	peopleFiles [][]int, peopleDict []string, filesDict []string) authorFilesList {
 	var pfl authorFilesList
@@ -494,8 +736,8 @@ func sortByNumberOfFiles(
 }
 
 type authorFiles struct {
-	Author string
-	Files  []string
+	Author string   `json:"author"`
+	Files  []string `json:"files"`
 }
 
 type authorFilesList []authorFiles
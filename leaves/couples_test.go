@@ -28,11 +28,42 @@ func TestCouplesMeta(t *testing.T) {
 	c := fixtureCouples()
 	assert.Equal(t, c.Name(), "Couples")
 	assert.Equal(t, len(c.Provides()), 0)
-	assert.Equal(t, len(c.Requires()), 2)
+	assert.Equal(t, len(c.Requires()), 3)
 	assert.Equal(t, c.Requires()[0], identity.DependencyAuthor)
 	assert.Equal(t, c.Requires()[1], plumbing.DependencyTreeChanges)
+	assert.Equal(t, c.Requires()[2], identity.DependencyAuthors)
 	assert.Equal(t, c.Flag(), "couples")
-	assert.Len(t, c.ListConfigurationOptions(), 0)
+	assert.Len(t, c.ListConfigurationOptions(), 1)
+	assert.Equal(t, c.ListConfigurationOptions()[0].Name, ConfigCouplesCoAuthorsCredit)
+	assert.Equal(t, c.ListConfigurationOptions()[0].Flag, "couples-co-authors")
+}
+
+func TestCouplesConsumeCoAuthors(t *testing.T) {
+	c := fixtureCouples()
+	assert.NotNil(t, c.Configure(map[string]interface{}{
+		ConfigCouplesCoAuthorsCredit: CoAuthorsCreditSplit}))
+	assert.Nil(t, c.Configure(map[string]interface{}{
+		ConfigCouplesCoAuthorsCredit: CoAuthorsCreditFull}))
+	deps := map[string]interface{}{}
+	deps[identity.DependencyAuthor] = 0
+	deps[identity.DependencyAuthors] = []int{0, 2}
+	deps[core.DependencyCommit], _ = test.Repository.CommitObject(gitplumbing.NewHash(
+		"a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3"))
+	deps[core.DependencyIsMerge] = false
+	deps[plumbing.DependencyTreeChanges] = generateChanges("+LICENSE2", "=analyser.go")
+	_, err := c.Consume(deps)
+	assert.Nil(t, err)
+	deps[identity.DependencyAuthor] = identity.AuthorMissing
+	deps[identity.DependencyAuthors] = []int{identity.AuthorMissing, 1}
+	deps[plumbing.DependencyTreeChanges] = generateChanges("-LICENSE2")
+	_, err = c.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 1, 1, 1}, c.peopleCommits)
+	assert.Equal(t, map[string]int{"LICENSE2": 1, "analyser.go": 1}, c.people[0])
+	assert.Equal(t, map[string]int{"LICENSE2": 1}, c.people[1])
+	assert.Equal(t, map[string]int{"LICENSE2": 1, "analyser.go": 1}, c.people[2])
+	assert.Equal(t, map[string]int{"LICENSE2": 1}, c.people[3])
+	assert.Equal(t, identity.AuthorMissing, deps[identity.DependencyAuthors].([]int)[0])
 }
 
 func TestCouplesRegistration(t *testing.T) {
@@ -86,6 +117,32 @@ func generateChanges(names ...string) object.Changes {
 	return changes
 }
 
+func TestCouplesConsumeBots(t *testing.T) {
+	c := fixtureCouples()
+	assert.Nil(t, c.Configure(map[string]interface{}{
+		identity.FactIdentityDetectorBots:   map[int]bool{1: true},
+		identity.ConfigIdentityDetectorBots: identity.BotsDrop,
+	}))
+	deps := map[string]interface{}{}
+	deps[identity.DependencyAuthor] = 1
+	deps[core.DependencyCommit], _ = test.Repository.CommitObject(gitplumbing.NewHash(
+		"a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3"))
+	deps[core.DependencyIsMerge] = false
+	deps[plumbing.DependencyTreeChanges] = generateChanges("+LICENSE2", ">file2.go>file_test.go")
+	_, err := c.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 0, 0, 0}, c.peopleCommits)
+	assert.Len(t, c.people[1], 0)
+	assert.Len(t, c.files, 0)
+	assert.Equal(t, []rename{{FromName: "file2.go", ToName: "file_test.go"}}, *c.renames)
+	deps[identity.DependencyAuthor] = 0
+	deps[plumbing.DependencyTreeChanges] = generateChanges("=LICENSE2")
+	_, err = c.Consume(deps)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 0, 0, 0}, c.peopleCommits)
+	assert.Equal(t, map[string]int{"LICENSE2": 1}, c.people[0])
+}
+
 func TestCouplesConsumeFinalize(t *testing.T) {
 	c := fixtureCouples()
 	deps := map[string]interface{}{}
@@ -358,6 +415,43 @@ func TestCouplesFork(t *testing.T) {
 	couples1.Merge([]core.PipelineItem{couples2})
 }
 
+func TestCouplesSaveLoadState(t *testing.T) {
+	c := fixtureCouples()
+	deps := map[string]interface{}{}
+	deps[identity.DependencyAuthor] = 0
+	deps[core.DependencyCommit], _ = test.Repository.CommitObject(gitplumbing.NewHash(
+		"a3ee37f91f0d705ec9c41ae88426f0ae44b2fbc3"))
+	deps[core.DependencyIsMerge] = false
+	deps[plumbing.DependencyTreeChanges] = generateChanges("+README.md", "+analyser.go")
+	c.Consume(deps)
+	deps[identity.DependencyAuthor] = identity.AuthorMissing
+	deps[plumbing.DependencyTreeChanges] = generateChanges(">analyser.go>file_test.go")
+	c.Consume(deps)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, c.SaveState(buffer))
+	state := buffer.Bytes()
+	c2 := fixtureCouples()
+	assert.Nil(t, c2.LoadState(bytes.NewBuffer(state)))
+	assert.Equal(t, c.people, c2.people)
+	assert.Equal(t, c.peopleCommits, c2.peopleCommits)
+	assert.Equal(t, c.files, c2.files)
+	assert.Equal(t, *c.renames, *c2.renames)
+	assert.Equal(t, c.lastCommit.Hash, c2.lastCommit.Hash)
+	assert.Equal(t, c.Finalize(), c2.Finalize())
+	c3 := &CouplesAnalysis{PeopleNumber: 5}
+	assert.Nil(t, c3.Initialize(test.Repository))
+	assert.Nil(t, c3.LoadState(bytes.NewBuffer(state)))
+	assert.Len(t, c3.people, 6)
+	assert.Equal(t, c.people[0], c3.people[0])
+	assert.Equal(t, c.people[3], c3.people[5])
+	assert.Equal(t, map[string]int{}, c3.people[3])
+	assert.Equal(t, c.peopleCommits[3], c3.peopleCommits[5])
+	c4 := &CouplesAnalysis{PeopleNumber: 1}
+	assert.Nil(t, c4.Initialize(test.Repository))
+	assert.NotNil(t, c4.LoadState(bytes.NewBuffer(state)))
+	assert.NotNil(t, c4.LoadState(bytes.NewBuffer([]byte("garbage"))))
+}
+
 func TestCouplesSerialize(t *testing.T) {
 	c := fixtureCouples()
 	result := CouplesResult{
@@ -375,7 +469,7 @@ func TestCouplesSerialize(t *testing.T) {
 		reversedPeopleDict: []string{"p1", "p2", "p3"},
 	}
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, c.Serialize(result, false, buffer))
+	assert.Nil(t, c.Serialize(result, core.YAMLFormat, buffer))
 	assert.Equal(t, buffer.String(), `  files_coocc:
     index:
       - "five"
@@ -411,7 +505,25 @@ func TestCouplesSerialize(t *testing.T) {
         - "three"
 `)
 	buffer = &bytes.Buffer{}
-	assert.Nil(t, c.Serialize(result, true, buffer))
+	assert.Nil(t, c.Serialize(result, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{
+  "files_coocc": {
+    "index": ["five", "one", "three"],
+    "lines": [9, 8, 7],
+    "matrix": [{"0": 3, "1": 1, "2": 1}, {"0": 1, "1": 2, "2": 2}, {"0": 1, "1": 2, "2": 2}]
+  },
+  "people_coocc": {
+    "index": ["p1", "p2", "p3"],
+    "matrix": [{"0": 7, "1": 3, "2": 1}, {"0": 3, "1": 3}, {"0": 1, "2": 1}, {}],
+    "author_files": [
+      {"author": "p3", "files": ["five"]},
+      {"author": "p2", "files": ["one", "three"]},
+      {"author": "p1", "files": ["five", "one", "three"]}
+    ]
+  }
+}`, buffer.String())
+	buffer = &bytes.Buffer{}
+	assert.Nil(t, c.Serialize(result, core.ProtobufFormat, buffer))
 	msg := pb.CouplesAnalysisResults{}
 	assert.Nil(t, proto.Unmarshal(buffer.Bytes(), &msg))
 	assert.Equal(t, msg.FilesLines, []int32{9, 8, 7})
@@ -461,6 +573,34 @@ func TestCouplesDeserialize(t *testing.T) {
 	assert.Len(t, result.FilesMatrix, 74)
 }
 
+func TestCouplesDeserializeText(t *testing.T) {
+	couples := CouplesAnalysis{}
+	result := CouplesResult{
+		Files:              []string{"a.go", "b.go", "c.go"},
+		FilesLines:         []int{10, 20, 30},
+		FilesMatrix:        []map[int]int64{{0: 2, 1: 1}, {0: 1, 1: 3}, {2: 1}},
+		PeopleFiles:        [][]int{{0, 1}, {1, 2}},
+		PeopleMatrix:       []map[int]int64{{0: 2, 1: 1}, {0: 1, 1: 2}, {}},
+		reversedPeopleDict: []string{"one@srcd", "two@srcd"},
+	}
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, couples.Serialize(result, format, buffer))
+		iresult, err := couples.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, result, iresult, format)
+	}
+	_, err := couples.DeserializeText([]byte(`files_coocc:
+  index: [a.go]
+  lines: [1]
+people_coocc:
+  index: [one]
+  author_files:
+  - two: [a.go]
+`), core.YAMLFormat)
+	assert.NotNil(t, err)
+}
+
 func TestCouplesMerge(t *testing.T) {
 	r1, r2 := CouplesResult{}, CouplesResult{}
 	people1 := [...]string{"one", "two"}
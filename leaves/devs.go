@@ -1,6 +1,8 @@
 package leaves
 
 import (
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
@@ -26,9 +28,14 @@ type DevsAnalysis struct {
 	// ConsiderEmptyCommits indicates whether empty commits (e.g., merges) should be taken
 	// into account.
 	ConsiderEmptyCommits bool
+	// CoAuthorsCredit defines how the co-authors are credited: CoAuthorsCreditAuthor,
+	// CoAuthorsCreditSplit or CoAuthorsCreditFull.
+	CoAuthorsCredit string
 
 	// days maps days to developers to stats
 	days map[int]map[int]*DevDay
+	// droppedBots are the identities whose commits are ignored
+	droppedBots map[int]bool
 	// reversedPeopleDict references IdentityDetector.ReversedPeopleDict
 	reversedPeopleDict []string
 }
@@ -43,6 +50,11 @@ type DevsResult struct {
 	reversedPeopleDict []string
 }
 
+// ReversedPeopleDict returns the developer names which correspond to the indexes in Days.
+func (result DevsResult) ReversedPeopleDict() []string {
+	return result.reversedPeopleDict
+}
+
 // DevDay is the statistics for a development day and a particular developer.
 type DevDay struct {
 	// Commits is the number of commits made by a particular developer in a particular day.
@@ -55,6 +67,8 @@ type DevDay struct {
 const (
 	// ConfigDevsConsiderEmptyCommits is the name of the option to set DevsAnalysis.ConsiderEmptyCommits.
 	ConfigDevsConsiderEmptyCommits = "Devs.ConsiderEmptyCommits"
+	// ConfigDevsCoAuthorsCredit is the name of the option to set DevsAnalysis.CoAuthorsCredit.
+	ConfigDevsCoAuthorsCredit = "Devs.CoAuthorsCredit"
 )
 
 // Name of this PipelineItem. Uniquely identifies the type, used for mapping keys, etc.
@@ -75,7 +89,7 @@ func (devs *DevsAnalysis) Provides() []string {
 func (devs *DevsAnalysis) Requires() []string {
 	arr := [...]string{
 		identity.DependencyAuthor, items.DependencyTreeChanges, items.DependencyDay,
-		items.DependencyLanguages, items.DependencyLineStats}
+		items.DependencyLanguages, items.DependencyLineStats, identity.DependencyAuthors}
 	return arr[:]
 }
 
@@ -86,7 +100,14 @@ func (devs *DevsAnalysis) ListConfigurationOptions() []core.ConfigurationOption
 		Description: "Take into account empty commits such as trivial merges.",
 		Flag:        "empty-commits",
 		Type:        core.BoolConfigurationOption,
-		Default:     false}}
+		Default:     false}, {
+		Name: ConfigDevsCoAuthorsCredit,
+		Description: "How to credit the co-authors detected with --co-authors: \"author\" ignores them, " +
+			"\"split\" divides the lines equally, \"full\" credits each with the whole commit. " +
+			"Not set means \"author\", or \"full\" with --signatures both.",
+		Flag:    "devs-co-authors",
+		Type:    core.StringConfigurationOption,
+		Default: ""}}
 	return options[:]
 }
 
@@ -95,9 +116,21 @@ func (devs *DevsAnalysis) Configure(facts map[string]interface{}) error {
 	if val, exists := facts[ConfigDevsConsiderEmptyCommits].(bool); exists {
 		devs.ConsiderEmptyCommits = val
 	}
+	if val, exists := facts[ConfigDevsCoAuthorsCredit].(string); exists {
+		credit, err := checkCoAuthorsCredit(
+			devs.Name(), val, CoAuthorsCreditSplit, CoAuthorsCreditFull)
+		if err != nil {
+			return err
+		}
+		devs.CoAuthorsCredit = credit
+	}
+	devs.CoAuthorsCredit = signaturesCredit(facts, devs.CoAuthorsCredit, CoAuthorsCreditFull)
 	if val, exists := facts[identity.FactIdentityDetectorReversedPeopleDict].([]string); exists {
 		devs.reversedPeopleDict = val
 	}
+	if val, exists := droppedBots(facts); exists {
+		devs.droppedBots = val
+	}
 	return nil
 }
 
@@ -128,7 +161,10 @@ func (devs *DevsAnalysis) Consume(deps map[string]interface{}) (map[string]inter
 	if !devs.ShouldConsumeCommit(deps) {
 		return nil, nil
 	}
-	author := deps[identity.DependencyAuthor].(int)
+	if devs.droppedBots[deps[identity.DependencyAuthor].(int)] {
+		return nil, nil
+	}
+	authors := withoutBots(creditedAuthors(deps, devs.CoAuthorsCredit), devs.droppedBots)
 	treeDiff := deps[items.DependencyTreeChanges].(object.Changes)
 	if len(treeDiff) == 0 && !devs.ConsiderEmptyCommits {
 		return nil, nil
@@ -139,12 +175,17 @@ func (devs *DevsAnalysis) Consume(deps map[string]interface{}) (map[string]inter
 		devsDay = map[int]*DevDay{}
 		devs.days[day] = devsDay
 	}
-	dd, exists := devsDay[author]
-	if !exists {
-		dd = &DevDay{Languages: map[string]items.LineStats{}}
-		devsDay[author] = dd
+	// each author is counted as having made the commit, only the lines are split
+	dds := make([]*DevDay, len(authors))
+	for i, author := range authors {
+		dd, exists := devsDay[author]
+		if !exists {
+			dd = &DevDay{Languages: map[string]items.LineStats{}}
+			devsDay[author] = dd
+		}
+		dd.Commits++
+		dds[i] = dd
 	}
-	dd.Commits++
 	if deps[core.DependencyIsMerge].(bool) {
 		// we ignore merge commit diffs
 		// TODO(vmarkovtsev): handle them
@@ -153,20 +194,42 @@ func (devs *DevsAnalysis) Consume(deps map[string]interface{}) (map[string]inter
 	langs := deps[items.DependencyLanguages].(map[plumbing.Hash]string)
 	lineStats := deps[items.DependencyLineStats].(map[object.ChangeEntry]items.LineStats)
 	for changeEntry, stats := range lineStats {
-		dd.Added += stats.Added
-		dd.Removed += stats.Removed
-		dd.Changed += stats.Changed
 		lang := langs[changeEntry.TreeEntry.Hash]
-		langStats := dd.Languages[lang]
-		dd.Languages[lang] = items.LineStats{
-			Added:   langStats.Added + stats.Added,
-			Removed: langStats.Removed + stats.Removed,
-			Changed: langStats.Changed + stats.Changed,
+		var shares []items.LineStats
+		if devs.CoAuthorsCredit == CoAuthorsCreditSplit {
+			shares = splitLineStats(stats, len(dds))
+		}
+		for i, dd := range dds {
+			share := stats
+			if shares != nil {
+				share = shares[i]
+			}
+			dd.Added += share.Added
+			dd.Removed += share.Removed
+			dd.Changed += share.Changed
+			langStats := dd.Languages[lang]
+			dd.Languages[lang] = items.LineStats{
+				Added:   langStats.Added + share.Added,
+				Removed: langStats.Removed + share.Removed,
+				Changed: langStats.Changed + share.Changed,
+			}
 		}
 	}
 	return nil, nil
 }
 
+// splitLineStats divides the line stats between n authors with splitCredit().
+func splitLineStats(stats items.LineStats, n int) []items.LineStats {
+	added := splitCredit(stats.Added, n)
+	removed := splitCredit(stats.Removed, n)
+	changed := splitCredit(stats.Changed, n)
+	shares := make([]items.LineStats, n)
+	for i := range shares {
+		shares[i] = items.LineStats{Added: added[i], Removed: removed[i], Changed: changed[i]}
+	}
+	return shares
+}
+
 // Finalize returns the result of the analysis. Further Consume() calls are not expected.
 func (devs *DevsAnalysis) Finalize() interface{} {
 	return DevsResult{
@@ -180,17 +243,103 @@ func (devs *DevsAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(devs, n)
 }
 
+// Sequential returns true because all the branches write to the same days map.
+func (devs *DevsAnalysis) Sequential() bool {
+	return true
+}
+
+// SaveState writes the collected per-day stats.
+func (devs *DevsAnalysis) SaveState(writer io.Writer) error {
+	return gob.NewEncoder(writer).Encode(devs.days)
+}
+
+// LoadState restores the per-day stats written by SaveState().
+func (devs *DevsAnalysis) LoadState(reader io.Reader) error {
+	days := map[int]map[int]*DevDay{}
+	err := gob.NewDecoder(reader).Decode(&days)
+	if err != nil {
+		return err
+	}
+	for day, devsDay := range days {
+		if devsDay == nil {
+			days[day] = map[int]*DevDay{}
+		}
+		for _, dd := range devsDay {
+			if dd.Languages == nil {
+				dd.Languages = map[string]items.LineStats{}
+			}
+		}
+	}
+	devs.days = days
+	return nil
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (devs *DevsAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (devs *DevsAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	devsResult := result.(DevsResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return devs.serializeBinary(&devsResult, writer)
+	case core.JSONFormat:
+		return devs.serializeJSON(&devsResult, writer)
 	}
 	devs.serializeText(&devsResult, writer)
 	return nil
 }
 
+// Metrics exports the numbers of commits and lines per developer over the whole history.
+func (devs *DevsAnalysis) Metrics(result interface{}) []core.MetricFamily {
+	devsResult := result.(DevsResult)
+	totals := map[int]*DevDay{}
+	for _, day := range devsResult.Days {
+		for dev, stats := range day {
+			if dev >= len(devsResult.reversedPeopleDict) {
+				// all the unmatched developers share the same label
+				dev = identity.AuthorMissing
+			}
+			total := totals[dev]
+			if total == nil {
+				total = &DevDay{}
+				totals[dev] = total
+			}
+			total.Commits += stats.Commits
+			total.Added += stats.Added
+			total.Removed += stats.Removed
+			total.Changed += stats.Changed
+		}
+	}
+	sequence := make([]int, 0, len(totals))
+	for dev := range totals {
+		sequence = append(sequence, dev)
+	}
+	sort.Ints(sequence)
+	families := []core.MetricFamily{
+		{Name: "hercules_devs_commits", Help: "Number of commits per developer.",
+			Type: core.CounterMetric},
+		{Name: "hercules_devs_lines_added", Help: "Number of added lines per developer.",
+			Type: core.CounterMetric},
+		{Name: "hercules_devs_lines_removed", Help: "Number of removed lines per developer.",
+			Type: core.CounterMetric},
+		{Name: "hercules_devs_lines_changed", Help: "Number of changed lines per developer.",
+			Type: core.CounterMetric},
+	}
+	for _, dev := range sequence {
+		name := "<unmatched>"
+		if dev != identity.AuthorMissing {
+			name = devsResult.reversedPeopleDict[dev]
+		}
+		labels := map[string]string{"developer": name}
+		total := totals[dev]
+		for i, value := range [...]int{total.Commits, total.Added, total.Removed, total.Changed} {
+			families[i].Metrics = append(families[i].Metrics,
+				core.Metric{Labels: labels, Value: float64(value)})
+		}
+	}
+	return families
+}
+
 // Deserialize converts the specified protobuf bytes to DevsResult.
 func (devs *DevsAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
 	message := pb.DevsAnalysisResults{}
@@ -232,6 +381,106 @@ func (devs *DevsAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
 	return result, nil
 }
 
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to DevsResult.
+func (devs *DevsAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	message := devsJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &message); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var err error
+		if message, err = parseDevsYAML(text); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	days := map[int]map[int]*DevDay{}
+	for day, jday := range message.Days {
+		rday := map[int]*DevDay{}
+		days[day] = rday
+		for dev, stats := range jday {
+			if dev == -1 {
+				dev = identity.AuthorMissing
+			}
+			languages := map[string]items.LineStats{}
+			for lang, ls := range stats.Languages {
+				if lang == "none" {
+					lang = ""
+				}
+				languages[lang] = ls.lineStats()
+			}
+			rday[dev] = &DevDay{
+				Commits:   stats.Commits,
+				LineStats: stats.lineStatsJSON.lineStats(),
+				Languages: languages,
+			}
+		}
+	}
+	result := DevsResult{
+		Days:               days,
+		reversedPeopleDict: message.People,
+	}
+	return result, nil
+}
+
+// devDayYAMLField is a single value in the YAML row of DevDay:
+// [commits, added, removed, changed, {language: [added, removed, changed]}].
+type devDayYAMLField struct {
+	number    int
+	languages map[string][]int
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (field *devDayYAMLField) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&field.number); err == nil {
+		return nil
+	}
+	return unmarshal(&field.languages)
+}
+
+func parseDevsYAML(text []byte) (devsJSON, error) {
+	parsed := struct {
+		Days   map[int]map[int][]devDayYAMLField `yaml:"days"`
+		People []string                          `yaml:"people"`
+	}{}
+	message := devsJSON{}
+	if err := yaml.Unmarshal(text, &parsed); err != nil {
+		return message, err
+	}
+	message.Days = map[int]map[int]devDayJSON{}
+	message.People = parsed.People
+	for day, yday := range parsed.Days {
+		jday := map[int]devDayJSON{}
+		message.Days[day] = jday
+		for dev, fields := range yday {
+			if len(fields) != 5 {
+				return message, fmt.Errorf("day %d: developer %d: 5 values expected, got %d",
+					day, dev, len(fields))
+			}
+			stats := devDayJSON{
+				Commits: fields[0].number,
+				lineStatsJSON: lineStatsJSON{
+					Added: fields[1].number, Removed: fields[2].number, Changed: fields[3].number,
+				},
+				Languages: map[string]lineStatsJSON{},
+			}
+			for lang, ls := range fields[4].languages {
+				if len(ls) != 3 {
+					return message, fmt.Errorf("day %d: developer %d: %s: 3 values expected, got %d",
+						day, dev, lang, len(ls))
+				}
+				stats.Languages[lang] = lineStatsJSON{Added: ls[0], Removed: ls[1], Changed: ls[2]}
+			}
+			jday[dev] = stats
+		}
+	}
+	return message, nil
+}
+
 // MergeResults combines two DevsAnalysis-es together.
 func (devs *DevsAnalysis) MergeResults(r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
 	cr1 := r1.(DevsResult)
@@ -382,6 +631,60 @@ func (devs *DevsAnalysis) serializeText(result *DevsResult, writer io.Writer) {
 	}
 }
 
+// lineStatsJSON is the JSON representation of items.LineStats.
+type lineStatsJSON struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+func newLineStatsJSON(stats items.LineStats) lineStatsJSON {
+	return lineStatsJSON{Added: stats.Added, Removed: stats.Removed, Changed: stats.Changed}
+}
+
+func (stats lineStatsJSON) lineStats() items.LineStats {
+	return items.LineStats{Added: stats.Added, Removed: stats.Removed, Changed: stats.Changed}
+}
+
+// devDayJSON is the JSON representation of DevDay.
+type devDayJSON struct {
+	Commits int `json:"commits"`
+	lineStatsJSON
+	Languages map[string]lineStatsJSON `json:"languages"`
+}
+
+func (devs *DevsAnalysis) serializeJSON(result *DevsResult, writer io.Writer) error {
+	days := map[int]map[int]devDayJSON{}
+	for day, rday := range result.Days {
+		jday := map[int]devDayJSON{}
+		days[day] = jday
+		for dev, stats := range rday {
+			if dev == identity.AuthorMissing {
+				dev = -1
+			}
+			languages := map[string]lineStatsJSON{}
+			for lang, ls := range stats.Languages {
+				if lang == "" {
+					lang = "none"
+				}
+				languages[lang] = newLineStatsJSON(ls)
+			}
+			jday[dev] = devDayJSON{
+				Commits:       stats.Commits,
+				lineStatsJSON: newLineStatsJSON(stats.LineStats),
+				Languages:     languages,
+			}
+		}
+	}
+	return json.NewEncoder(writer).Encode(devsJSON{days, result.reversedPeopleDict})
+}
+
+// devsJSON mirrors the YAML layout of DevsResult.
+type devsJSON struct {
+	Days   map[int]map[int]devDayJSON `json:"days"`
+	People []string                   `json:"people"`
+}
+
 func (devs *DevsAnalysis) serializeBinary(result *DevsResult, writer io.Writer) error {
 	message := pb.DevsAnalysisResults{}
 	message.DevIndex = result.reversedPeopleDict
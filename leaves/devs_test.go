@@ -28,14 +28,15 @@ func TestDevsMeta(t *testing.T) {
 	d := fixtureDevs()
 	assert.Equal(t, d.Name(), "Devs")
 	assert.Equal(t, len(d.Provides()), 0)
-	assert.Equal(t, len(d.Requires()), 5)
+	assert.Equal(t, len(d.Requires()), 6)
 	assert.Equal(t, d.Requires()[0], identity.DependencyAuthor)
 	assert.Equal(t, d.Requires()[1], items.DependencyTreeChanges)
 	assert.Equal(t, d.Requires()[2], items.DependencyDay)
 	assert.Equal(t, d.Requires()[3], items.DependencyLanguages)
 	assert.Equal(t, d.Requires()[4], items.DependencyLineStats)
+	assert.Equal(t, d.Requires()[5], identity.DependencyAuthors)
 	assert.Equal(t, d.Flag(), "devs")
-	assert.Len(t, d.ListConfigurationOptions(), 1)
+	assert.Len(t, d.ListConfigurationOptions(), 2)
 	assert.Equal(t, d.ListConfigurationOptions()[0].Name, ConfigDevsConsiderEmptyCommits)
 	assert.Equal(t, d.ListConfigurationOptions()[0].Flag, "empty-commits")
 	assert.Equal(t, d.ListConfigurationOptions()[0].Type, core.BoolConfigurationOption)
@@ -71,6 +72,121 @@ func TestDevsInitialize(t *testing.T) {
 	assert.NotNil(t, d.days)
 }
 
+func TestDevsConsumeCoAuthors(t *testing.T) {
+	entry1 := object.ChangeEntry{Name: "a.go", TreeEntry: object.TreeEntry{
+		Name: "a.go", Hash: plumbing.NewHash("291286b4ac41952cbd1389fda66420ec03c1a9fe")}}
+	entry2 := object.ChangeEntry{Name: "b.py", TreeEntry: object.TreeEntry{
+		Name: "b.py", Hash: plumbing.NewHash("c29112dbd697ad9b401333b80c18a63951bc18d9")}}
+	deps := map[string]interface{}{
+		core.DependencyCommit:       &object.Commit{},
+		core.DependencyIsMerge:      false,
+		identity.DependencyAuthor:   0,
+		identity.DependencyAuthors:  []int{0, 1},
+		items.DependencyDay:         0,
+		items.DependencyTreeChanges: object.Changes{&object.Change{To: entry1}},
+		items.DependencyLanguages: map[plumbing.Hash]string{
+			entry1.TreeEntry.Hash: "Go", entry2.TreeEntry.Hash: "Python"},
+		items.DependencyLineStats: map[object.ChangeEntry]items.LineStats{
+			entry1: ls(11, 2, 3), entry2: ls(1, 0, 0)},
+	}
+	for _, credit := range []string{"", CoAuthorsCreditAuthor, CoAuthorsCreditSplit, CoAuthorsCreditFull} {
+		devs := fixtureDevs()
+		assert.Nil(t, devs.Configure(map[string]interface{}{ConfigDevsCoAuthorsCredit: credit}))
+		_, err := devs.Consume(deps)
+		assert.Nil(t, err)
+		day := devs.Finalize().(DevsResult).Days[0]
+		switch credit {
+		case "", CoAuthorsCreditAuthor:
+			assert.Len(t, day, 1)
+			assert.Equal(t, &DevDay{1, ls(12, 2, 3), map[string]items.LineStats{
+				"Go": ls(11, 2, 3), "Python": ls(1, 0, 0)}}, day[0])
+		case CoAuthorsCreditSplit:
+			assert.Len(t, day, 2)
+			assert.Equal(t, &DevDay{1, ls(7, 1, 2), map[string]items.LineStats{
+				"Go": ls(6, 1, 2), "Python": ls(1, 0, 0)}}, day[0])
+			assert.Equal(t, &DevDay{1, ls(5, 1, 1), map[string]items.LineStats{
+				"Go": ls(5, 1, 1), "Python": ls(0, 0, 0)}}, day[1])
+		case CoAuthorsCreditFull:
+			assert.Len(t, day, 2)
+			assert.Equal(t, day[0], day[1])
+			assert.Equal(t, ls(12, 2, 3), day[1].LineStats)
+		}
+	}
+	devs := fixtureDevs()
+	assert.NotNil(t, devs.Configure(map[string]interface{}{ConfigDevsCoAuthorsCredit: "half"}))
+}
+
+func TestDevsConsumeBothSignatures(t *testing.T) {
+	entry := object.ChangeEntry{Name: "a.go", TreeEntry: object.TreeEntry{
+		Name: "a.go", Hash: plumbing.NewHash("291286b4ac41952cbd1389fda66420ec03c1a9fe")}}
+	// the author is 0 and the committer is 1
+	deps := map[string]interface{}{
+		core.DependencyCommit:       &object.Commit{},
+		core.DependencyIsMerge:      false,
+		identity.DependencyAuthor:   0,
+		identity.DependencyAuthors:  []int{0, 1},
+		items.DependencyDay:         0,
+		items.DependencyTreeChanges: object.Changes{&object.Change{To: entry}},
+		items.DependencyLanguages:   map[plumbing.Hash]string{entry.TreeEntry.Hash: "Go"},
+		items.DependencyLineStats:   map[object.ChangeEntry]items.LineStats{entry: ls(11, 2, 3)},
+	}
+	devs := fixtureDevs()
+	// the explicit author mode is respected
+	assert.Nil(t, devs.Configure(map[string]interface{}{
+		ConfigDevsCoAuthorsCredit:   CoAuthorsCreditAuthor,
+		core.FactPipelineSignatures: identity.SignaturesBoth,
+	}))
+	assert.Equal(t, CoAuthorsCreditAuthor, devs.CoAuthorsCredit)
+	// the default options
+	devs = fixtureDevs()
+	assert.Nil(t, devs.Configure(map[string]interface{}{
+		ConfigDevsCoAuthorsCredit:   "",
+		core.FactPipelineSignatures: identity.SignaturesBoth,
+	}))
+	assert.Equal(t, CoAuthorsCreditFull, devs.CoAuthorsCredit)
+	_, err := devs.Consume(deps)
+	assert.Nil(t, err)
+	day := devs.Finalize().(DevsResult).Days[0]
+	assert.Len(t, day, 2)
+	assert.Equal(t, &DevDay{1, ls(11, 2, 3), map[string]items.LineStats{"Go": ls(11, 2, 3)}}, day[1])
+	assert.Equal(t, day[0], day[1])
+}
+
+func TestDevsConsumeBots(t *testing.T) {
+	devs := fixtureDevs()
+	assert.Nil(t, devs.Configure(map[string]interface{}{
+		identity.FactIdentityDetectorBots:   map[int]bool{1: true},
+		identity.ConfigIdentityDetectorBots: identity.BotsDrop,
+		ConfigDevsCoAuthorsCredit:           CoAuthorsCreditFull,
+	}))
+	assert.Equal(t, map[int]bool{1: true}, devs.droppedBots)
+	entry := object.ChangeEntry{Name: "a.go", TreeEntry: object.TreeEntry{
+		Name: "a.go", Hash: plumbing.NewHash("291286b4ac41952cbd1389fda66420ec03c1a9fe")}}
+	deps := map[string]interface{}{
+		core.DependencyCommit:       &object.Commit{},
+		core.DependencyIsMerge:      false,
+		identity.DependencyAuthor:   1,
+		identity.DependencyAuthors:  []int{1, 0},
+		items.DependencyDay:         0,
+		items.DependencyTreeChanges: object.Changes{&object.Change{To: entry}},
+		items.DependencyLanguages:   map[plumbing.Hash]string{entry.TreeEntry.Hash: "Go"},
+		items.DependencyLineStats:   map[object.ChangeEntry]items.LineStats{entry: ls(10, 0, 0)},
+	}
+	_, err := devs.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, devs.days, 0)
+	deps[identity.DependencyAuthor] = 0
+	deps[identity.DependencyAuthors] = []int{0, 1}
+	_, err = devs.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, devs.days[0], 1)
+	assert.Equal(t, ls(10, 0, 0), devs.days[0][0].LineStats)
+	assert.Nil(t, devs.Configure(map[string]interface{}{
+		identity.FactIdentityDetectorBots: map[int]bool{1: true},
+	}))
+	assert.Nil(t, devs.droppedBots)
+}
+
 func TestDevsConsumeFinalize(t *testing.T) {
 	devs := fixtureDevs()
 	deps := map[string]interface{}{}
@@ -282,6 +398,20 @@ func TestDevsFork(t *testing.T) {
 	assert.True(t, devs == clone)
 }
 
+func TestDevsSaveLoadState(t *testing.T) {
+	devs := fixtureDevs()
+	devs.days[1] = map[int]*DevDay{}
+	devs.days[1][1] = &DevDay{10, ls(20, 30, 40), map[string]items.LineStats{"Go": ls(20, 30, 40)}}
+	devs.days[2] = map[int]*DevDay{}
+	devs.days[2][identity.AuthorMissing] = &DevDay{1, ls(0, 0, 0), map[string]items.LineStats{}}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, devs.SaveState(buffer))
+	devs2 := fixtureDevs()
+	assert.Nil(t, devs2.LoadState(bytes.NewBuffer(buffer.Bytes())))
+	assert.Equal(t, devs.days, devs2.days)
+	assert.NotNil(t, devs2.LoadState(bytes.NewBuffer([]byte("garbage"))))
+}
+
 func TestDevsSerialize(t *testing.T) {
 	devs := fixtureDevs()
 	devs.days[1] = map[int]*DevDay{}
@@ -293,7 +423,7 @@ func TestDevsSerialize(t *testing.T) {
 		100, ls(200, 300, 400), map[string]items.LineStats{"Go": ls(32, 33, 34)}}
 	res := devs.Finalize().(DevsResult)
 	buffer := &bytes.Buffer{}
-	err := devs.Serialize(res, false, buffer)
+	err := devs.Serialize(res, core.YAMLFormat, buffer)
 	assert.Nil(t, err)
 	assert.Equal(t, `  days:
     1:
@@ -308,7 +438,7 @@ func TestDevsSerialize(t *testing.T) {
 `, buffer.String())
 
 	buffer = &bytes.Buffer{}
-	err = devs.Serialize(res, true, buffer)
+	err = devs.Serialize(res, core.ProtobufFormat, buffer)
 	assert.Nil(t, err)
 	msg := pb.DevsAnalysisResults{}
 	assert.Nil(t, proto.Unmarshal(buffer.Bytes(), &msg))
@@ -330,6 +460,51 @@ func TestDevsSerialize(t *testing.T) {
 		Languages: map[string]*pb.LineStats{"Go": {Added: 32, Removed: 33, Changed: 34}}})
 }
 
+func TestDevsSerializeJSON(t *testing.T) {
+	devs := fixtureDevs()
+	devs.days[1] = map[int]*DevDay{}
+	devs.days[1][0] = &DevDay{10, ls(20, 30, 40), map[string]items.LineStats{"Go": ls(2, 3, 4)}}
+	devs.days[10] = map[int]*DevDay{}
+	devs.days[10][identity.AuthorMissing] = &DevDay{
+		100, ls(200, 300, 400), map[string]items.LineStats{"": ls(32, 33, 34)}}
+	res := devs.Finalize().(DevsResult)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, devs.Serialize(res, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{
+  "days": {
+    "1": {"0": {"commits": 10, "added": 20, "removed": 30, "changed": 40,
+                "languages": {"Go": {"added": 2, "removed": 3, "changed": 4}}}},
+    "10": {"-1": {"commits": 100, "added": 200, "removed": 300, "changed": 400,
+                  "languages": {"none": {"added": 32, "removed": 33, "changed": 34}}}}
+  },
+  "people": ["one@srcd", "two@srcd"]
+}`, buffer.String())
+}
+
+func TestDevsMetrics(t *testing.T) {
+	devs := fixtureDevs()
+	devs.days[1] = map[int]*DevDay{}
+	devs.days[1][0] = &DevDay{10, ls(20, 30, 40), map[string]items.LineStats{"Go": ls(2, 3, 4)}}
+	devs.days[10] = map[int]*DevDay{}
+	devs.days[10][0] = &DevDay{11, ls(21, 31, 41), map[string]items.LineStats{}}
+	devs.days[10][identity.AuthorMissing] = &DevDay{
+		100, ls(200, 300, 400), map[string]items.LineStats{}}
+	// the index outside of the identities is unmatched, too
+	devs.days[10][5] = &DevDay{1000, ls(2000, 3000, 4000), map[string]items.LineStats{}}
+	families := devs.Metrics(devs.Finalize())
+	assert.Len(t, families, 4)
+	assert.Equal(t, "hercules_devs_commits", families[0].Name)
+	assert.Equal(t, core.CounterMetric, families[0].Type)
+	assert.Equal(t, []core.Metric{
+		{Labels: map[string]string{"developer": "one@srcd"}, Value: 21},
+		{Labels: map[string]string{"developer": "<unmatched>"}, Value: 1100},
+	}, families[0].Metrics)
+	assert.Equal(t, "hercules_devs_lines_added", families[1].Name)
+	assert.Equal(t, float64(41), families[1].Metrics[0].Value)
+	assert.Equal(t, float64(61), families[2].Metrics[0].Value)
+	assert.Equal(t, float64(4400), families[3].Metrics[1].Value)
+}
+
 func TestDevsDeserialize(t *testing.T) {
 	devs := fixtureDevs()
 	devs.days[1] = map[int]*DevDay{}
@@ -341,7 +516,7 @@ func TestDevsDeserialize(t *testing.T) {
 		100, ls(200, 300, 400), map[string]items.LineStats{"Go": ls(42, 43, 44)}}
 	res := devs.Finalize().(DevsResult)
 	buffer := &bytes.Buffer{}
-	err := devs.Serialize(res, true, buffer)
+	err := devs.Serialize(res, core.ProtobufFormat, buffer)
 	assert.Nil(t, err)
 	rawres2, err := devs.Deserialize(buffer.Bytes())
 	assert.Nil(t, err)
@@ -349,6 +524,29 @@ func TestDevsDeserialize(t *testing.T) {
 	assert.Equal(t, res, res2)
 }
 
+func TestDevsDeserializeText(t *testing.T) {
+	devs := fixtureDevs()
+	devs.days[1] = map[int]*DevDay{}
+	devs.days[1][0] = &DevDay{10, ls(20, 30, 40), map[string]items.LineStats{"Go": ls(12, 13, 14)}}
+	devs.days[1][1] = &DevDay{1, ls(2, 3, 4), map[string]items.LineStats{"Go": ls(22, 23, 24)}}
+	devs.days[10] = map[int]*DevDay{}
+	devs.days[10][0] = &DevDay{11, ls(21, 31, 41), map[string]items.LineStats{"": ls(32, 33, 34)}}
+	devs.days[10][identity.AuthorMissing] = &DevDay{
+		100, ls(200, 300, 400), map[string]items.LineStats{"Go": ls(42, 43, 44)}}
+	res := devs.Finalize().(DevsResult)
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, devs.Serialize(res, format, buffer))
+		rawres2, err := devs.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, res, rawres2, format)
+	}
+	_, err := devs.DeserializeText([]byte("days: {1: {0: [1, 2]}}"), core.YAMLFormat)
+	assert.NotNil(t, err)
+	_, err = devs.DeserializeText(nil, core.ProtobufFormat)
+	assert.NotNil(t, err)
+}
+
 func TestDevsMergeResults(t *testing.T) {
 	people1 := [...]string{"1@srcd", "2@srcd"}
 	people2 := [...]string{"3@srcd", "1@srcd"}
@@ -1,6 +1,8 @@
 package leaves
 
 import (
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -16,20 +18,35 @@ import (
 	"gopkg.in/src-d/hercules.v9/internal/pb"
 	items "gopkg.in/src-d/hercules.v9/internal/plumbing"
 	"gopkg.in/src-d/hercules.v9/internal/plumbing/identity"
+	"gopkg.in/src-d/hercules.v9/internal/yaml"
 )
 
 // FileHistoryAnalysis contains the intermediate state which is mutated by Consume(). It should implement
-// LeafPipelineItem.
+// LeafPipelineItem. It is a ResultMergeablePipelineItem.
 type FileHistoryAnalysis struct {
 	core.NoopMerger
 	core.OneShotMergeProcessor
+	// PrefixRepository indicates whether MergeResults() must prepend the repository names
+	// to the file paths so that the same paths in different repositories do not collide.
+	PrefixRepository bool
+
 	files      map[string]*FileHistory
 	lastCommit *object.Commit
+	repository *git.Repository
+	// reversedPeopleDict references IdentityDetector.ReversedPeopleDict
+	reversedPeopleDict []string
+	// droppedBots are the identities whose line stats are ignored
+	droppedBots map[int]bool
 }
 
 // FileHistoryResult is returned by Finalize() and represents the analysis result.
 type FileHistoryResult struct {
 	Files map[string]FileHistory
+
+	// reversedPeopleDict references IdentityDetector.ReversedPeopleDict
+	reversedPeopleDict []string
+	// repositoryPrefixed indicates that the file paths already start with the repository names.
+	repositoryPrefixed bool
 }
 
 // FileHistory is the gathered stats about a particular file.
@@ -40,6 +57,12 @@ type FileHistory struct {
 	People map[int]items.LineStats
 }
 
+const (
+	// ConfigFileHistoryPrefixRepository is the name of the option to set
+	// FileHistoryAnalysis.PrefixRepository.
+	ConfigFileHistoryPrefixRepository = "FileHistory.PrefixRepository"
+)
+
 // Name of this PipelineItem. Uniquely identifies the type, used for mapping keys, etc.
 func (history *FileHistoryAnalysis) Name() string {
 	return "FileHistoryAnalysis"
@@ -62,7 +85,14 @@ func (history *FileHistoryAnalysis) Requires() []string {
 
 // ListConfigurationOptions returns the list of changeable public properties of this PipelineItem.
 func (history *FileHistoryAnalysis) ListConfigurationOptions() []core.ConfigurationOption {
-	return []core.ConfigurationOption{}
+	options := [...]core.ConfigurationOption{{
+		Name: ConfigFileHistoryPrefixRepository,
+		Description: "Prepend the repository names to the file paths while merging the results " +
+			"of several repositories.",
+		Flag:    "file-history-prefix-repository",
+		Type:    core.BoolConfigurationOption,
+		Default: false}}
+	return options[:]
 }
 
 // Flag for the command line switch which enables this analysis.
@@ -79,12 +109,22 @@ func (history *FileHistoryAnalysis) Description() string {
 
 // Configure sets the properties previously published by ListConfigurationOptions().
 func (history *FileHistoryAnalysis) Configure(facts map[string]interface{}) error {
+	if val, exists := facts[ConfigFileHistoryPrefixRepository].(bool); exists {
+		history.PrefixRepository = val
+	}
+	if val, exists := facts[identity.FactIdentityDetectorReversedPeopleDict].([]string); exists {
+		history.reversedPeopleDict = val
+	}
+	if val, exists := droppedBots(facts); exists {
+		history.droppedBots = val
+	}
 	return nil
 }
 
 // Initialize resets the temporary caches and prepares this PipelineItem for a series of Consume()
 // calls. The repository which is going to be analysed is supplied as an argument.
 func (history *FileHistoryAnalysis) Initialize(repository *git.Repository) error {
+	history.repository = repository
 	history.files = map[string]*FileHistory{}
 	history.OneShotMergeProcessor.Initialize()
 	return nil
@@ -132,6 +172,10 @@ func (history *FileHistoryAnalysis) Consume(deps map[string]interface{}) (map[st
 	}
 	lineStats := deps[items.DependencyLineStats].(map[object.ChangeEntry]items.LineStats)
 	author := deps[identity.DependencyAuthor].(int)
+	if history.droppedBots[author] {
+		// the commits are still recorded in the file histories
+		return nil, nil
+	}
 	for changeEntry, stats := range lineStats {
 		file := history.files[changeEntry.Name]
 		if file == nil {
@@ -169,7 +213,7 @@ func (history *FileHistoryAnalysis) Finalize() interface{} {
 	if err != nil {
 		log.Panicf("Failed to iterate files of %s", history.lastCommit.Hash.String())
 	}
-	return FileHistoryResult{Files: files}
+	return FileHistoryResult{Files: files, reversedPeopleDict: history.reversedPeopleDict}
 }
 
 // Fork clones this PipelineItem.
@@ -177,17 +221,97 @@ func (history *FileHistoryAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(history, n)
 }
 
+// Sequential returns true because all the branches write to the same files map.
+func (history *FileHistoryAnalysis) Sequential() bool {
+	return true
+}
+
+// fileHistoryState is the serialized state of FileHistoryAnalysis, see SaveState().
+type fileHistoryState struct {
+	Files      map[string]*FileHistory
+	LastCommit plumbing.Hash
+}
+
+// SaveState writes the collected file histories.
+func (history *FileHistoryAnalysis) SaveState(writer io.Writer) error {
+	state := fileHistoryState{Files: history.files}
+	if history.lastCommit != nil {
+		state.LastCommit = history.lastCommit.Hash
+	}
+	return gob.NewEncoder(writer).Encode(state)
+}
+
+// LoadState restores the file histories written by SaveState().
+func (history *FileHistoryAnalysis) LoadState(reader io.Reader) error {
+	state := fileHistoryState{}
+	err := gob.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return err
+	}
+	history.files = state.Files
+	if history.files == nil {
+		history.files = map[string]*FileHistory{}
+	}
+	history.lastCommit = nil
+	if state.LastCommit != plumbing.ZeroHash {
+		history.lastCommit, err = history.repository.CommitObject(state.LastCommit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (history *FileHistoryAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (history *FileHistoryAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	historyResult := result.(FileHistoryResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return history.serializeBinary(&historyResult, writer)
+	case core.JSONFormat:
+		return history.serializeJSON(&historyResult, writer)
 	}
 	history.serializeText(&historyResult, writer)
 	return nil
 }
 
+// Metrics exports the number of commits and the line churn per file.
+func (history *FileHistoryAnalysis) Metrics(result interface{}) []core.MetricFamily {
+	historyResult := result.(FileHistoryResult)
+	files := make([]string, 0, len(historyResult.Files))
+	for file := range historyResult.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	families := []core.MetricFamily{
+		{Name: "hercules_file_commits", Help: "Number of commits which changed the file.",
+			Type: core.CounterMetric},
+		{Name: "hercules_file_lines_added", Help: "Number of lines added to the file.",
+			Type: core.CounterMetric},
+		{Name: "hercules_file_lines_removed", Help: "Number of lines removed from the file.",
+			Type: core.CounterMetric},
+		{Name: "hercules_file_lines_changed", Help: "Number of changed lines in the file.",
+			Type: core.CounterMetric},
+	}
+	for _, file := range files {
+		fh := historyResult.Files[file]
+		var churn items.LineStats
+		for _, stats := range fh.People {
+			churn.Added += stats.Added
+			churn.Removed += stats.Removed
+			churn.Changed += stats.Changed
+		}
+		labels := map[string]string{"file": file}
+		for i, value := range [...]int{len(fh.Hashes), churn.Added, churn.Removed, churn.Changed} {
+			families[i].Metrics = append(families[i].Metrics,
+				core.Metric{Labels: labels, Value: float64(value)})
+		}
+	}
+	return families
+}
+
 func (history *FileHistoryAnalysis) serializeText(result *FileHistoryResult, writer io.Writer) {
 	keys := make([]string, len(result.Files))
 	i := 0
@@ -197,7 +321,7 @@ func (history *FileHistoryAnalysis) serializeText(result *FileHistoryResult, wri
 	}
 	sort.Strings(keys)
 	for _, key := range keys {
-		fmt.Fprintf(writer, "  - %s:\n", key)
+		fmt.Fprintf(writer, "  - %s:\n", yaml.SafeString(key))
 		file := result.Files[key]
 		hashes := file.Hashes
 		strhashes := make([]string, len(hashes))
@@ -208,16 +332,41 @@ func (history *FileHistoryAnalysis) serializeText(result *FileHistoryResult, wri
 		fmt.Fprintf(writer, "    commits: [%s]\n", strings.Join(strhashes, ","))
 		strpeople := make([]string, 0, len(file.People))
 		for key, val := range file.People {
-			strpeople = append(strpeople, fmt.Sprintf("%d:[%d,%d,%d]", key, val.Added, val.Removed, val.Changed))
+			strpeople = append(strpeople, fmt.Sprintf("%d: [%d, %d, %d]", key, val.Added, val.Removed, val.Changed))
 		}
 		sort.Strings(strpeople)
-		fmt.Fprintf(writer, "    people: {%s}\n", strings.Join(strpeople, ","))
+		fmt.Fprintf(writer, "    people: {%s}\n", strings.Join(strpeople, ", "))
+	}
+}
+
+// fileHistoryJSON is the JSON representation of FileHistory.
+type fileHistoryJSON struct {
+	Commits []string              `json:"commits"`
+	People  map[int]lineStatsJSON `json:"people"`
+}
+
+func (history *FileHistoryAnalysis) serializeJSON(result *FileHistoryResult, writer io.Writer) error {
+	files := map[string]fileHistoryJSON{}
+	for key, file := range result.Files {
+		strhashes := make([]string, len(file.Hashes))
+		for i, hash := range file.Hashes {
+			strhashes[i] = hash.String()
+		}
+		sort.Strings(strhashes)
+		people := map[int]lineStatsJSON{}
+		for dev, val := range file.People {
+			people[dev] = newLineStatsJSON(val)
+		}
+		files[key] = fileHistoryJSON{Commits: strhashes, People: people}
 	}
+	return json.NewEncoder(writer).Encode(files)
 }
 
 func (history *FileHistoryAnalysis) serializeBinary(result *FileHistoryResult, writer io.Writer) error {
 	message := pb.FileHistoryResultMessage{
-		Files: map[string]*pb.FileHistory{},
+		Files:              map[string]*pb.FileHistory{},
+		DevIndex:           result.reversedPeopleDict,
+		RepositoryPrefixed: result.repositoryPrefixed,
 	}
 	for key, vals := range result.Files {
 		fh := &pb.FileHistory{
@@ -244,6 +393,172 @@ func (history *FileHistoryAnalysis) serializeBinary(result *FileHistoryResult, w
 	return err
 }
 
+// Deserialize converts the specified protobuf bytes to FileHistoryResult.
+func (history *FileHistoryAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
+	message := pb.FileHistoryResultMessage{}
+	err := proto.Unmarshal(pbmessage, &message)
+	if err != nil {
+		return nil, err
+	}
+	result := FileHistoryResult{
+		Files:              map[string]FileHistory{},
+		reversedPeopleDict: message.DevIndex,
+		repositoryPrefixed: message.RepositoryPrefixed,
+	}
+	for key, vals := range message.Files {
+		fh := FileHistory{
+			Hashes: make([]plumbing.Hash, len(vals.Commits)),
+			People: map[int]items.LineStats{},
+		}
+		for i, hash := range vals.Commits {
+			fh.Hashes[i] = plumbing.NewHash(hash)
+		}
+		for dev, val := range vals.ChangesByDeveloper {
+			fh.People[int(dev)] = items.LineStats{
+				Added:   int(val.Added),
+				Removed: int(val.Removed),
+				Changed: int(val.Changed),
+			}
+		}
+		result.Files[key] = fh
+	}
+	return result, nil
+}
+
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to FileHistoryResult.
+// The text formats do not carry the developer names, so the result does not have them.
+func (history *FileHistoryAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	files := map[string]fileHistoryJSON{}
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &files); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		var entries []fileHistoryYAMLItem
+		if err := yaml.Unmarshal(text, &entries); err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			files[entry.name] = entry.fileHistoryJSON
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	result := FileHistoryResult{Files: map[string]FileHistory{}}
+	for key, file := range files {
+		fh := FileHistory{
+			Hashes: make([]plumbing.Hash, len(file.Commits)),
+			People: map[int]items.LineStats{},
+		}
+		for i, hash := range file.Commits {
+			fh.Hashes[i] = plumbing.NewHash(hash)
+		}
+		for dev, stats := range file.People {
+			fh.People[dev] = stats.lineStats()
+		}
+		result.Files[key] = fh
+	}
+	return result, nil
+}
+
+// fileHistoryYAMLItem is a single item in the YAML output: the file name mapped to null
+// next to "commits" and "people".
+type fileHistoryYAMLItem struct {
+	name string
+	fileHistoryJSON
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (item *fileHistoryYAMLItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var keys map[string]interface{}
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		if key != "commits" && key != "people" {
+			item.name = key
+		}
+	}
+	var values struct {
+		Commits []string      `yaml:"commits"`
+		People  map[int][]int `yaml:"people"`
+	}
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	item.Commits = values.Commits
+	item.People = map[int]lineStatsJSON{}
+	for dev, stats := range values.People {
+		if len(stats) != 3 {
+			return fmt.Errorf("%s: developer %d: 3 values expected, got %d", item.name, dev, len(stats))
+		}
+		item.People[dev] = lineStatsJSON{Added: stats[0], Removed: stats[1], Changed: stats[2]}
+	}
+	return nil
+}
+
+// MergeResults combines two FileHistoryResult-s together. The commits which changed the same file
+// are joined without duplicates and the line statistics of the same developers are summed.
+// The developer indexes of each result are mapped to the merged identities unless that result
+// lacks them, e.g. when it was produced by an old version.
+func (history *FileHistoryAnalysis) MergeResults(
+	r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
+	fhr1 := r1.(FileHistoryResult)
+	fhr2 := r2.(FileHistoryResult)
+	merged := FileHistoryResult{Files: map[string]FileHistory{}}
+	var people map[string][3]int
+	people, merged.reversedPeopleDict = identity.Detector{}.MergeReversedDicts(
+		fhr1.reversedPeopleDict, fhr2.reversedPeopleDict)
+	merged.repositoryPrefixed = history.PrefixRepository ||
+		(fhr1.repositoryPrefixed && fhr2.repositoryPrefixed)
+	addFiles := func(result *FileHistoryResult, common *core.CommonAnalysisResult) {
+		remap := len(result.reversedPeopleDict) > 0
+		prefix := ""
+		if history.PrefixRepository && !result.repositoryPrefixed &&
+			common != nil && common.Repository != "" {
+			prefix = common.Repository + "/"
+		}
+		for key, file := range result.Files {
+			key = prefix + key
+			mfile := merged.Files[key]
+			if mfile.People == nil {
+				mfile.People = map[int]items.LineStats{}
+			}
+			seen := map[plumbing.Hash]bool{}
+			for _, hash := range mfile.Hashes {
+				seen[hash] = true
+			}
+			for _, hash := range file.Hashes {
+				if !seen[hash] {
+					seen[hash] = true
+					mfile.Hashes = append(mfile.Hashes, hash)
+				}
+			}
+			for dev, stats := range file.People {
+				if remap {
+					if dev >= 0 && dev < len(result.reversedPeopleDict) {
+						dev = people[result.reversedPeopleDict[dev]][0]
+					} else {
+						dev = identity.AuthorMissing
+					}
+				}
+				oldStats := mfile.People[dev]
+				mfile.People[dev] = items.LineStats{
+					Added:   oldStats.Added + stats.Added,
+					Removed: oldStats.Removed + stats.Removed,
+					Changed: oldStats.Changed + stats.Changed,
+				}
+			}
+			merged.Files[key] = mfile
+		}
+	}
+	addFiles(&fhr1, c1)
+	addFiles(&fhr2, c2)
+	return merged
+}
+
 func init() {
 	core.Registry.Register(&FileHistoryAnalysis{})
 }
@@ -30,8 +30,18 @@ func TestFileHistoryMeta(t *testing.T) {
 	assert.Equal(t, fh.Requires()[0], items.DependencyTreeChanges)
 	assert.Equal(t, fh.Requires()[1], items.DependencyLineStats)
 	assert.Equal(t, fh.Requires()[2], identity.DependencyAuthor)
-	assert.Len(t, fh.ListConfigurationOptions(), 0)
+	opts := fh.ListConfigurationOptions()
+	assert.Len(t, opts, 1)
+	assert.Equal(t, opts[0].Name, ConfigFileHistoryPrefixRepository)
 	assert.Nil(t, fh.Configure(nil))
+	assert.False(t, fh.PrefixRepository)
+	people := []string{"one@srcd", "two@srcd"}
+	assert.Nil(t, fh.Configure(map[string]interface{}{
+		ConfigFileHistoryPrefixRepository:               true,
+		identity.FactIdentityDetectorReversedPeopleDict: people,
+	}))
+	assert.True(t, fh.PrefixRepository)
+	assert.Equal(t, people, fh.reversedPeopleDict)
 }
 
 func TestFileHistoryRegistration(t *testing.T) {
@@ -86,6 +96,26 @@ func TestFileHistoryConsume(t *testing.T) {
 	assert.Panics(t, func() { fh.Finalize() })
 }
 
+func TestFileHistoryConsumeBots(t *testing.T) {
+	fh := fixtureFileHistory()
+	assert.Nil(t, fh.Configure(map[string]interface{}{
+		identity.FactIdentityDetectorBots:   map[int]bool{1: true},
+		identity.ConfigIdentityDetectorBots: identity.BotsDrop,
+	}))
+	entry := object.ChangeEntry{Name: "a.go"}
+	deps := map[string]interface{}{
+		core.DependencyIsMerge:      false,
+		core.DependencyCommit:       &object.Commit{},
+		identity.DependencyAuthor:   1,
+		items.DependencyTreeChanges: object.Changes{&object.Change{To: entry}},
+		items.DependencyLineStats:   map[object.ChangeEntry]items.LineStats{entry: ls(10, 0, 0)},
+	}
+	_, err := fh.Consume(deps)
+	assert.Nil(t, err)
+	assert.Len(t, fh.files["a.go"].Hashes, 1)
+	assert.Len(t, fh.files["a.go"].People, 0)
+}
+
 func TestFileHistoryFork(t *testing.T) {
 	fh1 := fixtureFileHistory()
 	clones := fh1.Fork(1)
@@ -95,25 +125,67 @@ func TestFileHistoryFork(t *testing.T) {
 	fh1.Merge([]core.PipelineItem{fh2})
 }
 
+func TestFileHistorySaveLoadState(t *testing.T) {
+	fh, _ := bakeFileHistoryForSerialization(t)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, fh.SaveState(buffer))
+	fh2 := fixtureFileHistory()
+	assert.Nil(t, fh2.LoadState(bytes.NewBuffer(buffer.Bytes())))
+	assert.Equal(t, fh.files, fh2.files)
+	assert.Equal(t, fh.lastCommit.Hash, fh2.lastCommit.Hash)
+	assert.Equal(t, fh.Finalize(), fh2.Finalize())
+	assert.NotNil(t, fh2.LoadState(bytes.NewBuffer([]byte("garbage"))))
+}
+
 func TestFileHistorySerializeText(t *testing.T) {
 	fh, _ := bakeFileHistoryForSerialization(t)
 	res := fh.Finalize().(FileHistoryResult)
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, fh.Serialize(res, false, buffer))
-	assert.Equal(t, buffer.String(), `  - .travis.yml:
+	assert.Nil(t, fh.Serialize(res, core.YAMLFormat, buffer))
+	assert.Equal(t, buffer.String(), `  - ".travis.yml":
     commits: ["2b1ed978194a94edeabbca6de7ff3b5771d4d665"]
-    people: {1:[12,0,0]}
-  - cmd/hercules/main.go:
+    people: {1: [12, 0, 0]}
+  - "cmd/hercules/main.go":
     commits: ["0000000000000000000000000000000000000000","2b1ed978194a94edeabbca6de7ff3b5771d4d665"]
-    people: {1:[0,207,0]}
+    people: {1: [0, 207, 0]}
 `)
 }
 
+func TestFileHistorySerializeJSON(t *testing.T) {
+	fh, _ := bakeFileHistoryForSerialization(t)
+	res := fh.Finalize().(FileHistoryResult)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, fh.Serialize(res, core.JSONFormat, buffer))
+	assert.JSONEq(t, `{
+  ".travis.yml": {
+    "commits": ["2b1ed978194a94edeabbca6de7ff3b5771d4d665"],
+    "people": {"1": {"added": 12, "removed": 0, "changed": 0}}},
+  "cmd/hercules/main.go": {
+    "commits": ["0000000000000000000000000000000000000000",
+                "2b1ed978194a94edeabbca6de7ff3b5771d4d665"],
+    "people": {"1": {"added": 0, "removed": 207, "changed": 0}}}
+}`, buffer.String())
+}
+
+func TestFileHistoryMetrics(t *testing.T) {
+	fh, _ := bakeFileHistoryForSerialization(t)
+	families := fh.Metrics(fh.Finalize())
+	assert.Len(t, families, 4)
+	assert.Equal(t, "hercules_file_commits", families[0].Name)
+	assert.Equal(t, []core.Metric{
+		{Labels: map[string]string{"file": ".travis.yml"}, Value: 1},
+		{Labels: map[string]string{"file": "cmd/hercules/main.go"}, Value: 2},
+	}, families[0].Metrics)
+	assert.Equal(t, float64(12), families[1].Metrics[0].Value)
+	assert.Equal(t, float64(207), families[2].Metrics[1].Value)
+	assert.Equal(t, float64(0), families[3].Metrics[1].Value)
+}
+
 func TestFileHistorySerializeBinary(t *testing.T) {
 	fh, _ := bakeFileHistoryForSerialization(t)
 	res := fh.Finalize().(FileHistoryResult)
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, fh.Serialize(res, true, buffer))
+	assert.Nil(t, fh.Serialize(res, core.ProtobufFormat, buffer))
 	msg := pb.FileHistoryResultMessage{}
 	assert.Nil(t, proto.Unmarshal(buffer.Bytes(), &msg))
 	assert.Len(t, msg.Files, 2)
@@ -130,6 +202,115 @@ func TestFileHistorySerializeBinary(t *testing.T) {
 		map[int32]*pb.LineStats{1: {Added: 0, Removed: 207, Changed: 0}})
 }
 
+func TestFileHistoryDeserialize(t *testing.T) {
+	fh, _ := bakeFileHistoryForSerialization(t)
+	fh.reversedPeopleDict = []string{"one@srcd", "two@srcd"}
+	res := fh.Finalize().(FileHistoryResult)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, fh.Serialize(res, core.ProtobufFormat, buffer))
+	rawres2, err := fh.Deserialize(buffer.Bytes())
+	assert.Nil(t, err)
+	res2 := rawres2.(FileHistoryResult)
+	assert.Equal(t, res, res2)
+	_, err = fh.Deserialize([]byte("garbage"))
+	assert.NotNil(t, err)
+}
+
+func TestFileHistoryDeserializeText(t *testing.T) {
+	fh, _ := bakeFileHistoryForSerialization(t)
+	res := fh.Finalize().(FileHistoryResult)
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, fh.Serialize(res, format, buffer))
+		rawres2, err := fh.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, res, rawres2, format)
+	}
+	_, err := fh.DeserializeText([]byte("- a.go:\n  people: {1: [1, 2]}\n"), core.YAMLFormat)
+	assert.NotNil(t, err)
+}
+
+func TestFileHistoryMergeResults(t *testing.T) {
+	hash1 := plumbing.NewHash("2b1ed978194a94edeabbca6de7ff3b5771d4d665")
+	hash2 := plumbing.NewHash("c29112dbd697ad9b401333b80c18a63951bc18d9")
+	hash3 := plumbing.NewHash("cce947b98a050c6d356bc6ba95030254914027b1")
+	r1 := FileHistoryResult{
+		Files: map[string]FileHistory{
+			"README.md": {
+				Hashes: []plumbing.Hash{hash1, hash2},
+				People: map[int]items.LineStats{0: ls(10, 1, 2), 1: ls(5, 0, 0)},
+			},
+			"main.go": {
+				Hashes: []plumbing.Hash{hash1},
+				People: map[int]items.LineStats{1: ls(100, 0, 0)},
+			},
+		},
+		reversedPeopleDict: []string{"one@srcd", "two@srcd"},
+	}
+	r2 := FileHistoryResult{
+		Files: map[string]FileHistory{
+			"README.md": {
+				Hashes: []plumbing.Hash{hash2, hash3},
+				People: map[int]items.LineStats{
+					0: ls(1, 1, 1), 1: ls(3, 0, 0), identity.AuthorMissing: ls(7, 0, 0)},
+			},
+		},
+		reversedPeopleDict: []string{"three@srcd", "one@srcd"},
+	}
+	c1 := &core.CommonAnalysisResult{Repository: "hercules"}
+	c2 := &core.CommonAnalysisResult{Repository: "go-git"}
+	fh := fixtureFileHistory()
+	merged := fh.MergeResults(r1, r2, c1, c2).(FileHistoryResult)
+	assert.Equal(t, []string{"one@srcd", "two@srcd", "three@srcd"}, merged.reversedPeopleDict)
+	assert.Len(t, merged.Files, 2)
+	assert.Equal(t, []plumbing.Hash{hash1, hash2, hash3}, merged.Files["README.md"].Hashes)
+	assert.Equal(t, map[int]items.LineStats{
+		0: ls(13, 1, 2), 1: ls(5, 0, 0), 2: ls(1, 1, 1), identity.AuthorMissing: ls(7, 0, 0),
+	}, merged.Files["README.md"].People)
+	assert.Equal(t, r1.Files["main.go"], merged.Files["main.go"])
+	// the source results are not modified
+	assert.Len(t, r1.Files["README.md"].Hashes, 2)
+	assert.Len(t, r1.Files["README.md"].People, 2)
+
+	fh.PrefixRepository = true
+	merged = fh.MergeResults(r1, r2, c1, c2).(FileHistoryResult)
+	assert.Len(t, merged.Files, 3)
+	assert.Equal(t, []plumbing.Hash{hash1, hash2}, merged.Files["hercules/README.md"].Hashes)
+	assert.Equal(t, []plumbing.Hash{hash2, hash3}, merged.Files["go-git/README.md"].Hashes)
+	assert.Contains(t, merged.Files, "hercules/main.go")
+	// the already prefixed paths stay the same
+	c3 := &core.CommonAnalysisResult{Repository: "gitbase"}
+	merged = fh.MergeResults(merged, r1, c1, c3).(FileHistoryResult)
+	assert.Len(t, merged.Files, 5)
+	assert.Contains(t, merged.Files, "hercules/main.go")
+	assert.Contains(t, merged.Files, "gitbase/main.go")
+	// the prefixed paths survive the serialization
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, fh.Serialize(merged, core.ProtobufFormat, buffer))
+	deserialized, err := fh.Deserialize(buffer.Bytes())
+	assert.Nil(t, err)
+	c4 := &core.CommonAnalysisResult{Repository: "hercules & go-git & gitbase"}
+	merged = fh.MergeResults(deserialized, r2, c4, c2).(FileHistoryResult)
+	assert.Len(t, merged.Files, 5)
+	assert.Contains(t, merged.Files, "hercules/main.go")
+	assert.Contains(t, merged.Files, "go-git/README.md")
+
+	// the result without the identities keeps its developer indexes
+	fh.PrefixRepository = false
+	r2.reversedPeopleDict = nil
+	merged = fh.MergeResults(r1, r2, c1, c2).(FileHistoryResult)
+	assert.Equal(t, []string{"one@srcd", "two@srcd"}, merged.reversedPeopleDict)
+	assert.Equal(t, map[int]items.LineStats{
+		0: ls(11, 2, 3), 1: ls(8, 0, 0), identity.AuthorMissing: ls(7, 0, 0),
+	}, merged.Files["README.md"].People)
+	r1.reversedPeopleDict = nil
+	merged = fh.MergeResults(r1, r2, c1, c2).(FileHistoryResult)
+	assert.Len(t, merged.reversedPeopleDict, 0)
+	assert.Equal(t, map[int]items.LineStats{
+		0: ls(11, 2, 3), 1: ls(8, 0, 0), identity.AuthorMissing: ls(7, 0, 0),
+	}, merged.Files["README.md"].People)
+}
+
 func bakeFileHistoryForSerialization(t *testing.T) (*FileHistoryAnalysis, map[string]interface{}) {
 	fh := fixtureFileHistory()
 	deps := map[string]interface{}{}
@@ -1,10 +1,12 @@
 package leaves
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"sort"
+	"strconv"
 	"unicode/utf8"
 
 	"github.com/gogo/protobuf/proto"
@@ -19,10 +21,11 @@ import (
 	"gopkg.in/src-d/hercules.v9/internal/pb"
 	items "gopkg.in/src-d/hercules.v9/internal/plumbing"
 	uast_items "gopkg.in/src-d/hercules.v9/internal/plumbing/uast"
+	"gopkg.in/src-d/hercules.v9/internal/yaml"
 )
 
 // ShotnessAnalysis contains the intermediate state which is mutated by Consume(). It should implement
-// LeafPipelineItem.
+// LeafPipelineItem. It is a ResultMergeablePipelineItem.
 type ShotnessAnalysis struct {
 	core.NoopMerger
 	core.OneShotMergeProcessor
@@ -348,6 +351,11 @@ func (shotness *ShotnessAnalysis) Fork(n int) []core.PipelineItem {
 	return core.ForkSamePipelineItem(shotness, n)
 }
 
+// Sequential returns true because all the branches update the same nodes map.
+func (shotness *ShotnessAnalysis) Sequential() bool {
+	return true
+}
+
 // Finalize returns the result of the analysis. Further Consume() calls are not expected.
 func (shotness *ShotnessAnalysis) Finalize() interface{} {
 	result := ShotnessResult{
@@ -379,11 +387,15 @@ func (shotness *ShotnessAnalysis) Finalize() interface{} {
 }
 
 // Serialize converts the analysis result as returned by Finalize() to text or bytes.
-// The text format is YAML and the bytes format is Protocol Buffers.
-func (shotness *ShotnessAnalysis) Serialize(result interface{}, binary bool, writer io.Writer) error {
+// The text formats are YAML and JSON and the bytes format is Protocol Buffers.
+func (shotness *ShotnessAnalysis) Serialize(
+	result interface{}, format core.SerializationFormat, writer io.Writer) error {
 	shotnessResult := result.(ShotnessResult)
-	if binary {
+	switch format {
+	case core.ProtobufFormat:
 		return shotness.serializeBinary(&shotnessResult, writer)
+	case core.JSONFormat:
+		return shotness.serializeJSON(&shotnessResult, writer)
 	}
 	shotness.serializeText(&shotnessResult, writer)
 	return nil
@@ -413,6 +425,27 @@ func (shotness *ShotnessAnalysis) serializeText(result *ShotnessResult, writer i
 	}
 }
 
+// shotnessRecordJSON is the JSON representation of a single node in ShotnessResult.
+type shotnessRecordJSON struct {
+	Name         string      `json:"name"`
+	File         string      `json:"file"`
+	InternalRole string      `json:"internal_role"`
+	Counters     map[int]int `json:"counters"`
+}
+
+func (shotness *ShotnessAnalysis) serializeJSON(result *ShotnessResult, writer io.Writer) error {
+	records := make([]shotnessRecordJSON, len(result.Nodes))
+	for i, summary := range result.Nodes {
+		records[i] = shotnessRecordJSON{
+			Name:         summary.Name,
+			File:         summary.File,
+			InternalRole: summary.Type,
+			Counters:     result.Counters[i],
+		}
+	}
+	return json.NewEncoder(writer).Encode(records)
+}
+
 func (shotness *ShotnessAnalysis) serializeBinary(result *ShotnessResult, writer io.Writer) error {
 	message := pb.ShotnessAnalysisResults{
 		Records: make([]*pb.ShotnessRecord, len(result.Nodes)),
@@ -437,6 +470,121 @@ func (shotness *ShotnessAnalysis) serializeBinary(result *ShotnessResult, writer
 	return err
 }
 
+// Deserialize converts the specified protobuf bytes to ShotnessResult.
+func (shotness *ShotnessAnalysis) Deserialize(pbmessage []byte) (interface{}, error) {
+	message := pb.ShotnessAnalysisResults{}
+	err := proto.Unmarshal(pbmessage, &message)
+	if err != nil {
+		return nil, err
+	}
+	result := ShotnessResult{
+		Nodes:    make([]NodeSummary, len(message.Records)),
+		Counters: make([]map[int]int, len(message.Records)),
+	}
+	for i, record := range message.Records {
+		result.Nodes[i] = NodeSummary{Type: record.Type, Name: record.Name, File: record.File}
+		counter := map[int]int{}
+		for key, val := range record.Counters {
+			counter[int(key)] = int(val)
+		}
+		result.Counters[i] = counter
+	}
+	return result, nil
+}
+
+// DeserializeText converts the specified YAML or JSON text written by Serialize() to ShotnessResult.
+func (shotness *ShotnessAnalysis) DeserializeText(
+	text []byte, format core.SerializationFormat) (interface{}, error) {
+	var records []shotnessRecordJSON
+	switch format {
+	case core.JSONFormat:
+		if err := json.Unmarshal(text, &records); err != nil {
+			return nil, err
+		}
+	case core.YAMLFormat:
+		// the counter keys are quoted, so they are parsed as strings
+		var parsed []struct {
+			Name         string         `yaml:"name"`
+			File         string         `yaml:"file"`
+			InternalRole string         `yaml:"internal_role"`
+			Counters     map[string]int `yaml:"counters"`
+		}
+		if err := yaml.Unmarshal(text, &parsed); err != nil {
+			return nil, err
+		}
+		records = make([]shotnessRecordJSON, len(parsed))
+		for i, record := range parsed {
+			records[i] = shotnessRecordJSON{
+				Name: record.Name, File: record.File, InternalRole: record.InternalRole,
+				Counters: map[int]int{},
+			}
+			for key, val := range record.Counters {
+				index, err := strconv.Atoi(key)
+				if err != nil {
+					return nil, fmt.Errorf("%s: counters: %v", record.Name, err)
+				}
+				records[i].Counters[index] = val
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a text format", format)
+	}
+	result := ShotnessResult{
+		Nodes:    make([]NodeSummary, len(records)),
+		Counters: make([]map[int]int, len(records)),
+	}
+	for i, record := range records {
+		result.Nodes[i] = NodeSummary{Type: record.InternalRole, Name: record.Name, File: record.File}
+		result.Counters[i] = record.Counters
+		if result.Counters[i] == nil {
+			result.Counters[i] = map[int]int{}
+		}
+	}
+	return result, nil
+}
+
+// MergeResults combines two ShotnessResult-s together. The nodes are joined and the counters
+// of the same nodes and couples are summed. The merged nodes are sorted the same way
+// as in Finalize().
+func (shotness *ShotnessAnalysis) MergeResults(
+	r1, r2 interface{}, c1, c2 *core.CommonAnalysisResult) interface{} {
+	sr1 := r1.(ShotnessResult)
+	sr2 := r2.(ShotnessResult)
+	summaries := map[string]NodeSummary{}
+	for _, node := range sr1.Nodes {
+		summaries[node.String()] = node
+	}
+	for _, node := range sr2.Nodes {
+		summaries[node.String()] = node
+	}
+	keys := make([]string, 0, len(summaries))
+	for key := range summaries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	reverseKeys := map[string]int{}
+	merged := ShotnessResult{
+		Nodes:    make([]NodeSummary, len(keys)),
+		Counters: make([]map[int]int, len(keys)),
+	}
+	for i, key := range keys {
+		reverseKeys[key] = i
+		merged.Nodes[i] = summaries[key]
+		merged.Counters[i] = map[int]int{}
+	}
+	addCounters := func(result ShotnessResult) {
+		for i, node := range result.Nodes {
+			counter := merged.Counters[reverseKeys[node.String()]]
+			for key, val := range result.Counters[i] {
+				counter[reverseKeys[result.Nodes[key].String()]] += val
+			}
+		}
+	}
+	addCounters(sr1)
+	addCounters(sr2)
+	return merged
+}
+
 func (shotness *ShotnessAnalysis) extractNodes(root uast_nodes.Node) (map[string]uast_nodes.Node, error) {
 	it, err := tools.Filter(root, shotness.XpathStruct)
 	if err != nil {
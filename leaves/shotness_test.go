@@ -2,6 +2,7 @@ package leaves
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path"
@@ -242,7 +243,7 @@ func TestShotnessConsumeNoEnd(t *testing.T) {
 func TestShotnessSerializeText(t *testing.T) {
 	sh, result := bakeShotness(t, false)
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, sh.Serialize(result, false, buffer))
+	assert.Nil(t, sh.Serialize(result, core.YAMLFormat, buffer))
 	assert.Equal(t, buffer.String(), `  - name: testAddEntry
     file: test.java
     internal_role: uast:FunctionGroup
@@ -318,10 +319,25 @@ func TestShotnessSerializeText(t *testing.T) {
 `)
 }
 
+func TestShotnessSerializeJSON(t *testing.T) {
+	sh, result := bakeShotness(t, false)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, sh.Serialize(result, core.JSONFormat, buffer))
+	var records []shotnessRecordJSON
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &records))
+	assert.Len(t, records, 18)
+	assert.Equal(t, records[14], shotnessRecordJSON{
+		Name:         "testUnpackEntryFromStreamToFile",
+		File:         "test.java",
+		InternalRole: "uast:FunctionGroup",
+		Counters:     map[int]int{13: 1, 14: 1},
+	})
+}
+
 func TestShotnessSerializeBinary(t *testing.T) {
 	sh, result := bakeShotness(t, false)
 	buffer := &bytes.Buffer{}
-	assert.Nil(t, sh.Serialize(result, true, buffer))
+	assert.Nil(t, sh.Serialize(result, core.ProtobufFormat, buffer))
 	message := pb.ShotnessAnalysisResults{}
 	err := proto.Unmarshal(buffer.Bytes(), &message)
 	assert.Nil(t, err)
@@ -329,3 +345,61 @@ func TestShotnessSerializeBinary(t *testing.T) {
 	assert.Equal(t, message.Records[14].Name, "testUnpackEntryFromStreamToFile")
 	assert.Equal(t, message.Records[14].Counters, map[int32]int32{14: 1, 13: 1})
 }
+
+func TestShotnessDeserialize(t *testing.T) {
+	sh, result := bakeShotness(t, false)
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, sh.Serialize(result, core.ProtobufFormat, buffer))
+	rawResult2, err := sh.Deserialize(buffer.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, result, rawResult2)
+	_, err = sh.Deserialize([]byte("garbage"))
+	assert.NotNil(t, err)
+}
+
+func TestShotnessDeserializeText(t *testing.T) {
+	sh := &ShotnessAnalysis{}
+	result := ShotnessResult{
+		Nodes: []NodeSummary{
+			{Type: "uast:FunctionGroup", Name: "a", File: "test.java"},
+			{Type: "uast:FunctionGroup", Name: "b", File: "test.java"},
+		},
+		Counters: []map[int]int{{0: 3, 1: 1}, {0: 1, 1: 2}},
+	}
+	for _, format := range []core.SerializationFormat{core.YAMLFormat, core.JSONFormat} {
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, sh.Serialize(result, format, buffer))
+		rawResult2, err := sh.DeserializeText(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, result, rawResult2, format)
+	}
+}
+
+func TestShotnessMergeResults(t *testing.T) {
+	sh := &ShotnessAnalysis{}
+	r1 := ShotnessResult{
+		Nodes: []NodeSummary{
+			{Type: "uast:FunctionGroup", Name: "a", File: "test.java"},
+			{Type: "uast:FunctionGroup", Name: "c", File: "test.java"},
+		},
+		Counters: []map[int]int{{0: 3, 1: 1}, {0: 1, 1: 2}},
+	}
+	r2 := ShotnessResult{
+		Nodes: []NodeSummary{
+			{Type: "uast:FunctionGroup", Name: "c", File: "test.java"},
+			{Type: "uast:FunctionGroup", Name: "b", File: "test.java"},
+		},
+		Counters: []map[int]int{{0: 4, 1: 2}, {0: 2, 1: 5}},
+	}
+	merged := sh.MergeResults(r1, r2, nil, nil).(ShotnessResult)
+	assert.Equal(t, []NodeSummary{
+		{Type: "uast:FunctionGroup", Name: "a", File: "test.java"},
+		{Type: "uast:FunctionGroup", Name: "b", File: "test.java"},
+		{Type: "uast:FunctionGroup", Name: "c", File: "test.java"},
+	}, merged.Nodes)
+	assert.Equal(t, []map[int]int{
+		{0: 3, 2: 1},
+		{1: 5, 2: 2},
+		{0: 1, 1: 2, 2: 6},
+	}, merged.Counters)
+}